@@ -0,0 +1,188 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// anyTypeField is the member of the JSON form of a google.protobuf.Any naming the type of the
+// message it holds.
+const anyTypeField = "@type"
+
+// unmarshalAny decodes the JSON form of a google.protobuf.Any into m: the message it holds is
+// looked up by its "@type" with the TypeResolver, decoded, and stored in the wire format. For
+// UnmarshalWithLazyAnys only the type URL is set, and the JSON is kept in d.lazyAnys instead.
+func (d *decodeState) unmarshalAny(inputValue json.RawMessage, m protoreflect.Message) error {
+	if err := d.enterMessage(); err != nil {
+		return err
+	}
+	defer d.leaveMessage()
+	jsonFields := map[string]json.RawMessage{}
+	if err := d.tokens().splitObject(inputValue, jsonFields); err != nil {
+		return correctDynamicJsonType(err, "message google.protobuf.Any")
+	}
+	if len(jsonFields) == 0 {
+		return nil // An empty Any.
+	}
+	typeURL, err := d.anyTypeURL(jsonFields)
+	if err != nil {
+		return err
+	}
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("type_url"), protoreflect.ValueOfString(typeURL))
+	if d.lazyAnys != nil {
+		d.lazyAnys[m.Interface()] = append(json.RawMessage(nil), inputValue...)
+		return nil
+	}
+	held, err := d.unmarshalAnyPayload(typeURL, jsonFields)
+	if err != nil {
+		return err
+	}
+	value, err := protov2.MarshalOptions{Deterministic: true}.Marshal(held.Interface())
+	if err != nil {
+		return err
+	}
+	m.Set(fields.ByName("value"), protoreflect.ValueOfBytes(value))
+	return nil
+}
+
+// anyTypeURL returns the "@type" of the JSON form of an Any, split into jsonFields.
+func (d *decodeState) anyTypeURL(jsonFields map[string]json.RawMessage) (string, error) {
+	raw, ok := jsonFields[anyTypeField]
+	if !ok {
+		return "", fieldErrorAt(anyTypeField, anyTypeField, codeErrorf(CodeRequiredFieldMissing, "required field %s is missing", anyTypeField))
+	}
+	typeURL, err := strconv.Unquote(string(raw))
+	if err != nil || typeURL == "" {
+		return "", d.withRaw(fieldErrorAt(anyTypeField, anyTypeField, codeErrorf(CodeTypeMismatch, "%s must be a type URL string", anyTypeField)), raw)
+	}
+	return typeURL, nil
+}
+
+// unmarshalAnyPayload decodes the message of type typeURL held in the JSON form of an Any, split
+// into jsonFields.
+func (d *decodeState) unmarshalAnyPayload(typeURL string, jsonFields map[string]json.RawMessage) (protoreflect.Message, error) {
+	mt, err := d.typeResolver().FindMessageByURL(typeURL)
+	if errors.Is(err, protoregistry.NotFound) {
+		return nil, d.withRaw(fieldErrorAt(anyTypeField, anyTypeField, codeErrorf(CodeUnknownType, "unknown message type %q", typeURL)), jsonFields[anyTypeField])
+	} else if err != nil {
+		return nil, fieldErrorAt(anyTypeField, anyTypeField, err)
+	}
+	held := mt.New()
+	name := held.Descriptor().FullName()
+	_, custom := wellKnownHandlers[name]
+	payload, hasValue := jsonFields["value"]
+	// Messages with a JSON form of their own are held in "value", which the well-known types of
+	// google.protobuf must have. Others, such as google.type.Date, may be held field by field.
+	if custom && (hasValue || name.Parent() == "google.protobuf") {
+		if !hasValue {
+			return nil, fieldErrorAt("value", "value", codeErrorf(CodeRequiredFieldMissing, "required field value is missing"))
+		}
+		if err := d.unmarshalHeld(held, payload); err != nil {
+			return nil, FieldError("value", err)
+		}
+		return held, nil
+	}
+	members := make(map[string]json.RawMessage, len(jsonFields))
+	for key, raw := range jsonFields {
+		if key != anyTypeField {
+			members[key] = raw
+		}
+	}
+	if payload, err = json.Marshal(members); err != nil {
+		return nil, err
+	}
+	return held, d.unmarshalHeld(held, payload)
+}
+
+// unmarshalHeld decodes inputValue into held, a message of an Any, generated or dynamic.
+func (d *decodeState) unmarshalHeld(held protoreflect.Message, inputValue json.RawMessage) error {
+	if pb, ok := messageV1(held.Interface()); ok {
+		return d.unmarshalValue(reflect.ValueOf(pb).Elem(), inputValue, nil)
+	}
+	return d.unmarshalDynamic(held, inputValue)
+}
+
+// LazyAnys holds the JSON forms, "@type" included, of the Anys decoded by UnmarshalWithLazyAnys, by
+// the Any they were decoded into. An Any holds its message in the wire format, which the JSON can't
+// stand in for, so those Anys only have their type URL set: forward their JSON from here rather than
+// marshaling them again, and decode it with ResolveLazyAny.
+type LazyAnys map[protoreflect.ProtoMessage]json.RawMessage
+
+// UnmarshalWithLazyAnys is Unmarshal, except that the messages held in Anys are left undecoded, so
+// that gateways forwarding them needn't link in every type they may hold. Their JSON forms are
+// returned instead.
+func (u *Unmarshaler) UnmarshalWithLazyAnys(r io.Reader, pb proto.Message) (LazyAnys, error) {
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := u.readDocument(r, inputValue); err != nil {
+		return nil, err
+	}
+	d := u.newDecodeState()
+	defer d.release()
+	d.lazyAnys = LazyAnys{}
+	if err := d.unmarshalDocument(pb, *inputValue); err != nil {
+		return nil, err
+	}
+	return d.lazyAnys, nil
+}
+
+// UnmarshalWithLazyAnys unmarshals a JSON object stream into a protocol buffer, returning the JSON
+// forms of the Anys in it rather than decoding the messages they hold.
+func UnmarshalWithLazyAnys(r io.Reader, pb proto.Message) (LazyAnys, error) {
+	return new(Unmarshaler).UnmarshalWithLazyAnys(r, pb)
+}
+
+// ResolveLazyAny decodes the message held in raw, the JSON form of an Any as returned in LazyAnys,
+// looked up by its "@type" with the TypeResolver.
+func (u *Unmarshaler) ResolveLazyAny(raw json.RawMessage) (proto.Message, error) {
+	d := u.newDecodeState()
+	defer d.release()
+	jsonFields := map[string]json.RawMessage{}
+	if err := d.tokens().splitObject(bytes.TrimSpace(raw), jsonFields); err != nil {
+		return nil, correctDynamicJsonType(err, "message google.protobuf.Any")
+	}
+	typeURL, err := d.anyTypeURL(jsonFields)
+	if err != nil {
+		return nil, d.formatErrors(err)
+	}
+	held, err := d.unmarshalAnyPayload(typeURL, jsonFields)
+	if err == nil {
+		err = d.finishDocument(held)
+	}
+	if err != nil {
+		return nil, d.formatErrors(err)
+	}
+	return proto.MessageV1(held.Interface()), nil
+}
+
+// ResolveAny returns the message held in a, looked up with the TypeResolver and unmarshaled from the
+// wire format.
+func (u *Unmarshaler) ResolveAny(a *anypb.Any) (proto.Message, error) {
+	mt, err := u.typeResolver().FindMessageByURL(a.GetTypeUrl())
+	if errors.Is(err, protoregistry.NotFound) {
+		return nil, codeErrorf(CodeUnknownType, "unknown message type %q", a.GetTypeUrl())
+	} else if err != nil {
+		return nil, err
+	}
+	m := mt.New().Interface()
+	if err := protov2.Unmarshal(a.GetValue(), m); err != nil {
+		return nil, err
+	}
+	return proto.MessageV1(m), nil
+}
+
+// ResolveAny returns the message held in a, looked up in protoregistry.GlobalTypes.
+func ResolveAny(a *anypb.Any) (proto.Message, error) {
+	return new(Unmarshaler).ResolveAny(a)
+}
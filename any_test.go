@@ -0,0 +1,136 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestUnmarshal_Any(t *testing.T) {
+	m := &validatortest.AnyHolder{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{
+		"single": {"@type": "type.googleapis.com/validatortest.Message3", "someString": "foo"},
+		"many": [{"@type": "type.googleapis.com/google.protobuf.Duration", "value": "1.5s"}]
+	}`, m))
+	held, err := m.Single.UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, "foo", held.(*validatortest.Message3).SomeString)
+	held, err = m.Many[0].UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, held.(*durationpb.Duration).AsDuration())
+}
+
+func TestUnmarshal_AnyErrors(t *testing.T) {
+	for _, tc := range []struct {
+		doc     string
+		pointer string
+		code    nicejsonpb.Code
+	}{
+		{`{"single": {"someString": "foo"}}`, "/single/@type", nicejsonpb.CodeRequiredFieldMissing},
+		{`{"single": {"@type": "type.googleapis.com/validatortest.Nope"}}`, "/single/@type", nicejsonpb.CodeUnknownType},
+		{`{"single": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": "x"}}`, "/single/someInt32", nicejsonpb.CodeTypeMismatch},
+		{`{"single": {"@type": "type.googleapis.com/google.protobuf.Duration", "value": "x"}}`, "/single/value", nicejsonpb.CodeBadDuration},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.doc, &validatortest.AnyHolder{})
+		require.Error(t, err, tc.doc)
+		require.Equal(t, tc.pointer, nicejsonpb.ErrorPointer(err), tc.doc)
+		require.Equal(t, tc.code, nicejsonpb.ErrorCode(err), tc.doc)
+	}
+}
+
+func TestUnmarshalDynamic_Any(t *testing.T) {
+	desc := validatortest.File_nicejsonpb_any_proto.Messages().ByName("AnyHolder")
+	m, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"single": {"@type": "type.googleapis.com/validatortest.Message3", "someString": "foo"}}`), desc)
+	require.NoError(t, err)
+	single := m.Get(desc.Fields().ByName("single")).Message()
+	wire := &anypb.Any{}
+	proto.Merge(wire, single.Interface())
+	held, err := wire.UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, "foo", held.(*validatortest.Message3).SomeString)
+
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"single": {"@type": "type.googleapis.com/validatortest.Nope"}}`), desc)
+	require.Equal(t, "/single/@type", nicejsonpb.ErrorPointer(err))
+}
+
+func TestUnmarshal_AnyOfAny(t *testing.T) {
+	m := &validatortest.AnyHolder{}
+	u := nicejsonpb.New()
+	require.NoError(t, u.UnmarshalBytes([]byte(`{"single": {"@type": "type.googleapis.com/google.protobuf.Any", "value": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": 3}}}`), m))
+	held, err := u.ResolveAny(m.Single)
+	require.NoError(t, err)
+	held, err = u.ResolveAny(held.(*anypb.Any))
+	require.NoError(t, err)
+	require.Equal(t, int32(3), held.(*validatortest.Message3).SomeInt32)
+}
+
+func TestUnmarshal_LazyAnys(t *testing.T) {
+	u := nicejsonpb.New()
+	doc := `{"@type": "type.googleapis.com/validatortest.Nope", "some": ["thing"]}`
+	m := &validatortest.AnyHolder{}
+	anys, err := u.UnmarshalWithLazyAnys(strings.NewReader(`{"single": `+doc+`}`), m)
+	require.NoError(t, err)
+	require.Equal(t, "type.googleapis.com/validatortest.Nope", m.Single.TypeUrl)
+	require.Empty(t, m.Single.Value)
+	require.JSONEq(t, doc, string(anys[m.Single]))
+	_, err = u.ResolveLazyAny(anys[m.Single])
+	require.Equal(t, nicejsonpb.CodeUnknownType, nicejsonpb.ErrorCode(err))
+	// The holder stays valid to marshal, in the wire format and as JSON.
+	_, err = proto.Marshal(m)
+	require.NoError(t, err)
+
+	anys, err = u.UnmarshalWithLazyAnys(strings.NewReader(`{"single": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": "x"}}`), m)
+	require.NoError(t, err)
+	_, err = u.ResolveLazyAny(anys[m.Single])
+	require.Equal(t, "/someInt32", nicejsonpb.ErrorPointer(err))
+
+	anys, err = u.UnmarshalWithLazyAnys(strings.NewReader(`{"single": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": 3}}`), m)
+	require.NoError(t, err)
+	_, err = protojson.Marshal(m)
+	require.NoError(t, err)
+	held, err := u.ResolveLazyAny(anys[m.Single])
+	require.NoError(t, err)
+	require.Equal(t, int32(3), held.(*validatortest.Message3).SomeInt32)
+
+	wire, err := anypb.New(&validatortest.Message3{SomeInt32: 4})
+	require.NoError(t, err)
+	held, err = nicejsonpb.ResolveAny(wire)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), held.(*validatortest.Message3).SomeInt32)
+}
+
+func TestUnmarshal_NestedAnyErrors(t *testing.T) {
+	for doc, field := range map[string]string{
+		`{"attributes": {"ctx": {"@type": "type.googleapis.com/validatortest.Nope"}}}`:                                                                         "attributes.ctx.@type",
+		`{"many": [{}, {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": "x"}]}`:                                                            "many.1.someInt32",
+		`{"single": {"@type": "type.googleapis.com/google.protobuf.Any", "value": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": "x"}}}`: "single.value.someInt32",
+	} {
+		err := nicejsonpb.UnmarshalString(doc, &validatortest.AnyHolder{})
+		require.Error(t, err, doc)
+		params := nicejsonpb.NewProblem(err).InvalidParams
+		require.Len(t, params, 1, doc)
+		require.Equal(t, field, params[0].Field, doc)
+	}
+}
+
+func TestUnmarshal_LazyAnysNested(t *testing.T) {
+	m := &validatortest.AnyHolder{}
+	anys, err := nicejsonpb.UnmarshalWithLazyAnys(strings.NewReader(`{
+		"many": [{"@type": "type.googleapis.com/validatortest.Nope"}],
+		"attributes": {"ctx": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": 3}}
+	}`), m)
+	require.NoError(t, err)
+	require.Len(t, anys, 2)
+	require.Contains(t, anys, m.Many[0])
+	held, err := nicejsonpb.New().ResolveLazyAny(anys[m.Attributes["ctx"]])
+	require.NoError(t, err)
+	require.Equal(t, int32(3), held.(*validatortest.Message3).SomeInt32)
+}
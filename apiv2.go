@@ -0,0 +1,49 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoimpl"
+)
+
+// UnmarshalMessage unmarshals a JSON object stream into a google.golang.org/protobuf (API v2) message.
+// Generated API v2 messages can also be passed to Unmarshal directly, this is for code that only holds
+// them as a protoreflect.ProtoMessage. Messages not backed by a generated Go struct, such as
+// dynamicpb ones, are decoded through their descriptors instead.
+func (u *Unmarshaler) UnmarshalMessage(r io.Reader, m protoreflect.ProtoMessage) error {
+	pb, ok := messageV1(m)
+	if !ok {
+		inputValue := getRawMessage()
+		defer putRawMessage(inputValue)
+		if err := u.readDocument(r, inputValue); err != nil {
+			return err
+		}
+		return u.unmarshalDynamicDocument(m.ProtoReflect(), *inputValue)
+	}
+	return u.Unmarshal(r, pb)
+}
+
+// UnmarshalMessage unmarshals a JSON object stream into a google.golang.org/protobuf (API v2) message.
+func UnmarshalMessage(r io.Reader, m protoreflect.ProtoMessage) error {
+	return new(Unmarshaler).UnmarshalMessage(r, m)
+}
+
+// UnmarshalMessageBytes unmarshals a JSON document held in memory into a google.golang.org/protobuf
+// (API v2) message.
+func UnmarshalMessageBytes(b []byte, m protoreflect.ProtoMessage) error {
+	return new(Unmarshaler).UnmarshalMessage(bytes.NewReader(b), m)
+}
+
+// messageV1 returns the struct-backed message the decoder can reflect over for an API v2 message,
+// or false if there is none.
+func messageV1(m protoreflect.ProtoMessage) (proto.Message, bool) {
+	// Both generated API v2 messages and wrapped legacy ones are backed by a Go struct with
+	// protobuf tags, which is all the decoder needs. Dynamic messages aren't.
+	if _, ok := m.ProtoReflect().(interface{ ProtoMessageInfo() *protoimpl.MessageInfo }); !ok {
+		return nil, false
+	}
+	return proto.MessageV1(m), true
+}
@@ -0,0 +1,42 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestUnmarshalMessage_DecodesApiV2Messages(t *testing.T) {
+	input := `{
+		"someString": "foo",
+		"someInt64": "12",
+		"someEmbedded": {"identifier": "bar", "children": [{"someValue": 3}]},
+		"someTimestamp": "2017-01-15T01:30:15.01Z",
+		"someDuration": "3.5s",
+		"someWrappedInt": "7",
+		"someOptionalString": "",
+		"phone": "555-1234"
+	}`
+	var stuff protoreflect.ProtoMessage = &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalMessageBytes([]byte(input), stuff))
+	msg := stuff.(*validatortest.Message3)
+	require.Equal(t, "foo", msg.SomeString)
+	require.Equal(t, int64(12), msg.SomeInt64)
+	require.Equal(t, "bar", msg.SomeEmbedded.Identifier)
+	require.Equal(t, int64(3), msg.SomeEmbedded.Children[0].SomeValue)
+	require.Equal(t, time.Date(2017, 1, 15, 1, 30, 15, 1e7, time.UTC), msg.SomeTimestamp.AsTime())
+	require.Equal(t, 3500*time.Millisecond, msg.SomeDuration.AsDuration())
+	require.Equal(t, int64(7), msg.SomeWrappedInt.Value)
+	require.NotNil(t, msg.SomeOptionalString)
+	require.Equal(t, "555-1234", msg.GetPhone())
+}
+
+func TestUnmarshalMessage_FindsErrorsInNested(t *testing.T) {
+	input := `{"someEmbedded": {"children": [{}, {"identifier": 3.1}]}}`
+	err := nicejsonpb.UnmarshalMessageBytes([]byte(input), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Children.[1].Identifier: json: cannot unmarshal number into Go value of type string")
+}
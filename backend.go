@@ -0,0 +1,111 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Backend splits JSON objects and arrays into their members, which is where most of the time of an
+// unmarshal goes. Setting Unmarshaler.Backend swaps encoding/json for a faster parser there, such as
+// the one in the jsoniterbackend package. Whenever a Backend fails, the input is parsed again with
+// encoding/json to report the error, so the choice of backend never changes the errors returned.
+type Backend interface {
+	// SplitObject adds the members of the JSON object held in raw to fields, keyed by their unescaped
+	// names. Their values must be left as raw JSON. If a name is repeated, the last value wins.
+	SplitObject(raw []byte, fields map[string]json.RawMessage) error
+	// SplitArray returns the raw JSON elements of the JSON array held in raw.
+	SplitArray(raw []byte) ([]json.RawMessage, error)
+}
+
+// tokenReader is what decoding uses to split objects and arrays.
+type tokenReader interface {
+	splitObject(raw json.RawMessage, fields map[string]json.RawMessage) error
+	splitArray(raw json.RawMessage) ([]json.RawMessage, error)
+}
+
+// stdTokenReader splits with encoding/json.
+type stdTokenReader struct{}
+
+func (stdTokenReader) splitObject(raw json.RawMessage, fields map[string]json.RawMessage) error {
+	return json.Unmarshal(raw, &fields)
+}
+
+func (stdTokenReader) splitArray(raw json.RawMessage) ([]json.RawMessage, error) {
+	var slc []json.RawMessage
+	err := json.Unmarshal(raw, &slc)
+	return slc, err
+}
+
+// backendTokenReader splits with a Backend, relying on encoding/json for the errors.
+type backendTokenReader struct {
+	backend Backend
+}
+
+func (b backendTokenReader) splitObject(raw json.RawMessage, fields map[string]json.RawMessage) error {
+	if err := b.backend.SplitObject(raw, fields); err != nil {
+		for k := range fields {
+			delete(fields, k)
+		}
+		return stdTokenReader{}.splitObject(raw, fields)
+	}
+	return nil
+}
+
+func (b backendTokenReader) splitArray(raw json.RawMessage) ([]json.RawMessage, error) {
+	slc, err := b.backend.SplitArray(raw)
+	if err != nil {
+		return stdTokenReader{}.splitArray(raw)
+	}
+	return slc, nil
+}
+
+// uniqueTokenReader rejects objects that repeat a name, which the other tokenReaders let the last
+// value of win.
+type uniqueTokenReader struct {
+	tokenReader
+	d *decodeState
+}
+
+func (u uniqueTokenReader) splitObject(raw json.RawMessage, fields map[string]json.RawMessage) error {
+	if err := u.tokenReader.splitObject(raw, fields); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	// Only well-formed objects get here, so the only thing to look for is names seen before.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.Token()
+	seen := make(map[string]bool, len(fields))
+	for dec.More() {
+		tok, _ := dec.Token()
+		name, _ := tok.(string)
+		if seen[name] {
+			return codeErrorf(CodeSyntax, "duplicate name %s in object", u.d.echo("%q", "name", name))
+		}
+		seen[name] = true
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errDuplicateName is the error for a field set under both its proto name orig and its JSON name
+// camel, when names may not be repeated.
+func errDuplicateName(orig, camel string) error {
+	return codeErrorf(CodeSyntax, "duplicate field, both %q and %q are set", orig, camel)
+}
+
+// tokens returns the tokenReader for the Backend of d.
+func (d *decodeState) tokens() tokenReader {
+	var tokens tokenReader = stdTokenReader{}
+	if d.Backend != nil {
+		tokens = backendTokenReader{d.Backend}
+	}
+	if d.rejectDuplicateKeys {
+		return uniqueTokenReader{tokens, d}
+	}
+	return tokens
+}
@@ -0,0 +1,46 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend splits objects with encoding/json, and fails on all arrays.
+type countingBackend struct {
+	objects, arrays int
+}
+
+func (b *countingBackend) SplitObject(raw []byte, fields map[string]json.RawMessage) error {
+	b.objects++
+	return json.Unmarshal(raw, &fields)
+}
+
+func (b *countingBackend) SplitArray(raw []byte) ([]json.RawMessage, error) {
+	b.arrays++
+	return nil, errors.New("backend failure")
+}
+
+func TestUnmarshal_Backend(t *testing.T) {
+	backend := &countingBackend{}
+	u := nicejsonpb.New(nicejsonpb.WithBackend(backend))
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someEmbedded": {"identifier": "x"}, "someStringToInt64": {"a": 1}, "someStringRep": ["b"]}`), m))
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Equal(t, int64(1), m.SomeStringToInt64["a"])
+	require.Equal(t, []string{"b"}, m.SomeStringRep, "failures fall back to encoding/json")
+	require.Equal(t, 3, backend.objects)
+	require.Equal(t, 1, backend.arrays)
+
+	err := u.Unmarshal(strings.NewReader(`{"someStringRep": {}}`), m)
+	require.EqualError(t, err, nicejsonpb.UnmarshalString(`{"someStringRep": {}}`, m).Error())
+
+	_, err = u.UnmarshalDynamic(strings.NewReader(`{"someStringRep": ["c"]}`), message3Descriptor(t))
+	require.NoError(t, err)
+	require.Equal(t, 3, backend.arrays)
+}
@@ -0,0 +1,92 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"runtime"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// UnmarshalMany decodes every item into a fresh message obtained from factory. The results are
+// aligned with items: msgs[i] holds the message decoded from items[i], or nil if that failed with
+// errs[i]. errs is nil when all the items could be decoded.
+func (u *Unmarshaler) UnmarshalMany(items []json.RawMessage, factory func() proto.Message) (msgs []proto.Message, errs []error) {
+	msgs = make([]proto.Message, len(items))
+	errs = make([]error, len(items))
+	for i, item := range items {
+		msgs[i] = factory()
+		errs[i] = u.UnmarshalBytes(item, msgs[i])
+	}
+	return batchResults(msgs, errs)
+}
+
+// UnmarshalMany decodes every item into a fresh message obtained from factory, returning the
+// messages and errors aligned with items.
+func UnmarshalMany(items []json.RawMessage, factory func() proto.Message) ([]proto.Message, []error) {
+	return new(Unmarshaler).UnmarshalMany(items, factory)
+}
+
+// batchResults drops the messages that failed to decode, and the errors if there are none.
+func batchResults(msgs []proto.Message, errs []error) ([]proto.Message, []error) {
+	failed := false
+	for i, err := range errs {
+		if err != nil {
+			msgs[i] = nil
+			failed = true
+		}
+	}
+	if !failed {
+		return msgs, nil
+	}
+	return msgs, errs
+}
+
+// BatchDecoder decodes independent JSON documents on several goroutines, which pays off for bulk
+// ingestion as decoding is CPU-bound. The Unmarshaler it uses is shared by all of them, so its
+// OnWarning and UnknownFieldSink callbacks must be safe for concurrent use.
+type BatchDecoder struct {
+	u       *Unmarshaler
+	workers int
+}
+
+// NewBatchDecoder returns a BatchDecoder with the options of u, decoding on the given number of
+// goroutines, or runtime.GOMAXPROCS(0) of them if workers isn't positive.
+func (u *Unmarshaler) NewBatchDecoder(workers int) *BatchDecoder {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &BatchDecoder{u: u, workers: workers}
+}
+
+// NewBatchDecoder returns a BatchDecoder with the default options.
+func NewBatchDecoder(workers int) *BatchDecoder {
+	return new(Unmarshaler).NewBatchDecoder(workers)
+}
+
+// Decode is UnmarshalMany done in parallel. The results are in the order of items all the same.
+// factory is only called from the calling goroutine.
+func (b *BatchDecoder) Decode(items []json.RawMessage, factory func() proto.Message) (msgs []proto.Message, errs []error) {
+	msgs = make([]proto.Message, len(items))
+	errs = make([]error, len(items))
+	for i := range items {
+		msgs[i] = factory()
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers && w < len(items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = b.u.UnmarshalBytes(items[i], msgs[i])
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return batchResults(msgs, errs)
+}
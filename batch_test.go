@@ -0,0 +1,67 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func newMessage3() proto.Message {
+	return &validatortest.Message3{}
+}
+
+func TestUnmarshalMany(t *testing.T) {
+	items := []json.RawMessage{
+		json.RawMessage(`{"someString": "a"}`),
+		json.RawMessage(`{"someInt32": "x"}`),
+		json.RawMessage(`{"someString": "c"}`),
+		json.RawMessage(``),
+	}
+	msgs, errs := nicejsonpb.UnmarshalMany(items, newMessage3)
+	require.Len(t, msgs, 4)
+	require.Len(t, errs, 4)
+	require.Equal(t, "a", msgs[0].(*validatortest.Message3).SomeString)
+	require.NoError(t, errs[0])
+	require.Nil(t, msgs[1])
+	require.EqualError(t, errs[1], "unparsable field SomeInt32: json: cannot unmarshal string into Go value of type int32")
+	require.Equal(t, "c", msgs[2].(*validatortest.Message3).SomeString)
+	require.Error(t, errs[3])
+
+	items = []json.RawMessage{json.RawMessage(`{"someInt32": "7"}`), json.RawMessage(`{}`)}
+	msgs, errs = nicejsonpb.New(nicejsonpb.WithAcceptStringNumbers()).UnmarshalMany(items, newMessage3)
+	require.Nil(t, errs)
+	require.Len(t, msgs, 2)
+	require.Equal(t, int32(7), msgs[0].(*validatortest.Message3).SomeInt32)
+}
+
+func TestBatchDecoder_PreservesOrder(t *testing.T) {
+	items := make([]json.RawMessage, 200)
+	for i := range items {
+		if i%7 == 3 {
+			items[i] = json.RawMessage(fmt.Sprintf(`{"someInt32": %d.5}`, i))
+		} else {
+			items[i] = json.RawMessage(fmt.Sprintf(`{"someInt32": %d}`, i))
+		}
+	}
+	msgs, errs := nicejsonpb.NewBatchDecoder(8).Decode(items, newMessage3)
+	require.Len(t, msgs, len(items))
+	require.Len(t, errs, len(items))
+	for i := range items {
+		if i%7 == 3 {
+			require.Nil(t, msgs[i])
+			require.EqualError(t, errs[i], fmt.Sprintf("unparsable field SomeInt32: value %d.5 is not an integer, expected int32", i))
+			continue
+		}
+		require.NoError(t, errs[i])
+		require.Equal(t, int32(i), msgs[i].(*validatortest.Message3).SomeInt32)
+	}
+
+	msgs, errs = nicejsonpb.New(nicejsonpb.WithCoerceFloatsToInts()).NewBatchDecoder(0).Decode(items, newMessage3)
+	require.Nil(t, errs)
+	require.Equal(t, int32(3), msgs[3].(*validatortest.Message3).SomeInt32)
+}
@@ -0,0 +1,90 @@
+package nicejsonpb
+
+import (
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// hasFieldBehavior reports whether fd is annotated with the given google.api.field_behavior.
+func hasFieldBehavior(fd protoreflect.FieldDescriptor, behavior annotations.FieldBehavior) bool {
+	opts := fd.Options()
+	if opts == nil || !protov2.HasExtension(opts, annotations.E_FieldBehavior) {
+		return false
+	}
+	for _, b := range protov2.GetExtension(opts, annotations.E_FieldBehavior).([]annotations.FieldBehavior) {
+		if b == behavior {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldMissing notes that the field with the given number of the message held in target is absent
+// from the JSON, which is an error if it is a REQUIRED field and EnforceRequiredBehavior is set.
+func (d *decodeState) fieldMissing(target proto.Message, name string, number int) {
+	if !d.EnforceRequiredBehavior {
+		return
+	}
+	fd := proto.MessageReflect(target).Descriptor().Fields().ByNumber(protoreflect.FieldNumber(number))
+	if fd != nil {
+		d.dynamicFieldMissing(fd, name)
+	}
+}
+
+func (d *decodeState) dynamicFieldMissing(fd protoreflect.FieldDescriptor, name string) {
+	if !d.EnforceRequiredBehavior || !hasFieldBehavior(fd, annotations.FieldBehavior_REQUIRED) {
+		return
+	}
+	path := append(append([]string{}, d.path...), name)
+	keys := append(append([]string{}, d.keys...), dynamicJSONName(fd))
+	d.missingRequired = append(d.missingRequired, &fieldError{
+		fieldStack: path,
+		keys:       keys,
+		nestedErr:  codeErrorf(CodeRequiredFieldMissing, "required field %s is missing", dynamicJSONName(fd)),
+	})
+}
+
+// OutputOnlyHandling selects what happens to fields annotated with google.api.field_behavior
+// OUTPUT_ONLY that are set in the JSON, which are managed by the server and not for clients to set.
+type OutputOnlyHandling int
+
+const (
+	// OutputOnlyAccepted decodes OUTPUT_ONLY fields like any other.
+	OutputOnlyAccepted OutputOnlyHandling = iota
+	// OutputOnlyIgnored drops OUTPUT_ONLY fields, leaving them unset.
+	OutputOnlyIgnored
+	// OutputOnlyRejected fails the unmarshal on OUTPUT_ONLY fields.
+	OutputOnlyRejected
+)
+
+// fieldSettable is dynamicFieldSettable for the field with the given number of the message held in
+// target.
+func (d *decodeState) fieldSettable(target proto.Message, key string, number int) (bool, error) {
+	if d.OutputOnly == OutputOnlyAccepted && !d.RejectImmutableUpdates {
+		return true, nil
+	}
+	m := proto.MessageReflect(target)
+	fd := m.Descriptor().Fields().ByNumber(protoreflect.FieldNumber(number))
+	if fd == nil {
+		return true, nil
+	}
+	return d.dynamicFieldSettable(m, fd, key)
+}
+
+// dynamicFieldSettable applies OutputOnly and RejectImmutableUpdates to the field fd of m, which has
+// the given key in the JSON. It returns false if the field is to be skipped, with the error to
+// report if any.
+func (d *decodeState) dynamicFieldSettable(m protoreflect.Message, fd protoreflect.FieldDescriptor, key string) (bool, error) {
+	if d.OutputOnly != OutputOnlyAccepted && hasFieldBehavior(fd, annotations.FieldBehavior_OUTPUT_ONLY) {
+		if d.OutputOnly == OutputOnlyIgnored {
+			return false, nil
+		}
+		return false, fieldErrorAt(goCamelCase(string(fd.Name())), key, newCodedError(CodeFieldBehavior, "field is OUTPUT_ONLY and can't be set"))
+	}
+	if d.RejectImmutableUpdates && d.MergeInto && m.Has(fd) && hasFieldBehavior(fd, annotations.FieldBehavior_IMMUTABLE) {
+		return false, fieldErrorAt(goCamelCase(string(fd.Name())), key, newCodedError(CodeFieldBehavior, "field is IMMUTABLE and can't be changed once set"))
+	}
+	return true, nil
+}
@@ -0,0 +1,59 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_EnforceRequiredBehavior(t *testing.T) {
+	input := `{"name": null, "items": [{"sku": "a"}, {"quantity": 2}], "comment": ""}`
+	require.NoError(t, nicejsonpb.UnmarshalString(input, &validatortest.CreateRequest{}))
+
+	u := &nicejsonpb.Unmarshaler{EnforceRequiredBehavior: true}
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.CreateRequest{})
+	require.EqualError(t, err, "unparsable field Name: required field name is missing; "+
+		"unparsable field Item: required field item is missing; "+
+		"unparsable field Items.[1].Sku: required field sku is missing")
+
+	msg, err := u.UnmarshalDynamic(strings.NewReader(`{"item": {}}`), (&validatortest.CreateRequest{}).ProtoReflect().Descriptor())
+	require.Nil(t, msg)
+	require.EqualError(t, err, "unparsable field Name: required field name is missing; "+
+		"unparsable field Item.Sku: required field sku is missing")
+
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"name": "x", "item": {"sku": "a"}}`), &validatortest.CreateRequest{}))
+}
+
+func TestUnmarshal_OutputOnly(t *testing.T) {
+	input := `{"name": "x", "id": "client-chosen"}`
+	desc := (&validatortest.CreateRequest{}).ProtoReflect().Descriptor()
+
+	ignoring := nicejsonpb.New(nicejsonpb.WithOutputOnly(nicejsonpb.OutputOnlyIgnored))
+	m := &validatortest.CreateRequest{}
+	require.NoError(t, ignoring.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, "x", m.Name)
+	require.Equal(t, "", m.Id)
+	dm, err := ignoring.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.NoError(t, err)
+	require.False(t, dm.ProtoReflect().Has(desc.Fields().ByName("id")))
+
+	rejecting := nicejsonpb.New(nicejsonpb.WithOutputOnly(nicejsonpb.OutputOnlyRejected))
+	err = rejecting.Unmarshal(strings.NewReader(input), &validatortest.CreateRequest{})
+	require.EqualError(t, err, "unparsable field Id: field is OUTPUT_ONLY and can't be set")
+	require.Equal(t, nicejsonpb.CodeFieldBehavior, nicejsonpb.ErrorCode(err))
+	_, err = rejecting.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.EqualError(t, err, "unparsable field Id: field is OUTPUT_ONLY and can't be set")
+}
+
+func TestUnmarshal_RejectImmutableUpdates(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMergeInto(false), nicejsonpb.WithRejectImmutableUpdates())
+	m := &validatortest.CreateRequest{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"region": "eu"}`), m))
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"comment": "c"}`), m))
+	err := u.Unmarshal(strings.NewReader(`{"region": "us"}`), m)
+	require.EqualError(t, err, "unparsable field Region: field is IMMUTABLE and can't be changed once set")
+	require.Equal(t, "eu", m.Region)
+}
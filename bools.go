@@ -0,0 +1,29 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+)
+
+// parseBool decodes raw as a bool, which with CoerceStringBools may also be one of the strings
+// "true", "false", "1" and "0".
+func (d *decodeState) parseBool(raw json.RawMessage) (bool, error) {
+	if len(raw) == 0 || raw[0] != '"' {
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false, err
+	}
+	if !d.CoerceStringBools {
+		return false, codeErrorf(CodeTypeMismatch, "string %s is not a bool, expected true or false", d.echo("%q", "string", s))
+	}
+	switch s {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	}
+	return false, codeErrorf(CodeTypeMismatch, `string %s is not a bool, expected "true", "false", "1" or "0"`, d.echo("%q", "string", s))
+}
@@ -0,0 +1,35 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_CoerceStringBools(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithCoerceStringBools())
+	for input, want := range map[string]bool{`"true"`: true, `"1"`: true, `"false"`: false, `"0"`: false, `true`: true} {
+		m := &validatortest.Message3{SomeBool: !want}
+		require.NoError(t, u.Unmarshal(strings.NewReader(`{"someBool": `+input+`}`), m), input)
+		require.Equal(t, want, m.SomeBool, input)
+
+		dm, err := u.UnmarshalDynamic(strings.NewReader(`{"someBool": `+input+`}`), message3Descriptor(t))
+		require.NoError(t, err, input)
+		require.Equal(t, want, dm.Get(dm.Descriptor().Fields().ByName("some_bool")).Bool(), input)
+	}
+
+	err := u.Unmarshal(strings.NewReader(`{"someBool": "yes"}`), &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeBool: string "yes" is not a bool, expected "true", "false", "1" or "0"`)
+	require.Equal(t, nicejsonpb.CodeTypeMismatch, nicejsonpb.ErrorCode(err))
+}
+
+func TestUnmarshal_StringBoolsRejected(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someBool": "true"}`, &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeBool: string "true" is not a bool, expected true or false`)
+	require.Equal(t, nicejsonpb.CodeTypeMismatch, nicejsonpb.ErrorCode(err))
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"someBool": "true"}`), message3Descriptor(t))
+	require.EqualError(t, err, `unparsable field SomeBool: string "true" is not a bool, expected true or false`)
+}
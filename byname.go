@@ -0,0 +1,49 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// TypeResolver looks up message types, as *protoregistry.Types do, for services that learn them
+// from a schema registry or descriptor sets loaded at run time.
+type TypeResolver interface {
+	FindMessageByName(message protoreflect.FullName) (protoreflect.MessageType, error)
+	FindMessageByURL(url string) (protoreflect.MessageType, error)
+}
+
+// typeResolver returns the TypeResolver of u, protoregistry.GlobalTypes if it has none.
+func (u *Unmarshaler) typeResolver() TypeResolver {
+	if u.TypeResolver != nil {
+		return u.TypeResolver
+	}
+	return protoregistry.GlobalTypes
+}
+
+// UnmarshalByName decodes the JSON document held in data into a fresh message of the type called
+// fullName, such as "my.pkg.Event", looked up with the TypeResolver. It is for generic ingestion
+// services that learn the type of documents from an envelope or a header. Types the TypeResolver
+// doesn't know fail with CodeUnknownType.
+func (u *Unmarshaler) UnmarshalByName(fullName string, data []byte) (proto.Message, error) {
+	mt, err := u.typeResolver().FindMessageByName(protoreflect.FullName(fullName))
+	if errors.Is(err, protoregistry.NotFound) {
+		return nil, codeErrorf(CodeUnknownType, "unknown message type %q", fullName)
+	} else if err != nil {
+		return nil, err
+	}
+	m := mt.New().Interface()
+	if err := u.UnmarshalMessage(bytes.NewReader(data), m); err != nil {
+		return nil, err
+	}
+	return proto.MessageV1(m), nil
+}
+
+// UnmarshalByName decodes the JSON document held in data into a fresh message of the type called
+// fullName, looked up in protoregistry.GlobalTypes.
+func UnmarshalByName(fullName string, data []byte) (proto.Message, error) {
+	return new(Unmarshaler).UnmarshalByName(fullName, data)
+}
@@ -0,0 +1,41 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestUnmarshalByName(t *testing.T) {
+	pb, err := nicejsonpb.UnmarshalByName("validatortest.Message3", []byte(`{"someString": "foo"}`))
+	require.NoError(t, err)
+	require.Equal(t, "foo", pb.(*validatortest.Message3).SomeString)
+
+	_, err = nicejsonpb.UnmarshalByName("validatortest.Message3", []byte(`{"someInt32": "x"}`))
+	require.Equal(t, "/someInt32", nicejsonpb.ErrorPointer(err))
+
+	_, err = nicejsonpb.UnmarshalByName("validatortest.Nope", []byte(`{}`))
+	require.EqualError(t, err, `unknown message type "validatortest.Nope"`)
+	require.Equal(t, nicejsonpb.CodeUnknownType, nicejsonpb.ErrorCode(err))
+}
+
+func TestUnmarshalByName_TypeResolver(t *testing.T) {
+	desc := message3Descriptor(t)
+	types := &protoregistry.Types{}
+	require.NoError(t, types.RegisterMessage(dynamicpb.NewMessageType(desc)))
+	u := nicejsonpb.New(nicejsonpb.WithTypeResolver(types))
+
+	pb, err := u.UnmarshalByName("validatortest.Message3", []byte(`{"someString": "foo"}`))
+	require.NoError(t, err)
+	m := proto.MessageReflect(pb)
+	require.Equal(t, desc, m.Descriptor())
+	require.Equal(t, "foo", m.Get(desc.Fields().ByName("some_string")).String())
+
+	_, err = u.UnmarshalByName("validatortest.Message3", []byte(`{"someEmbedded": {"identifier": 3}}`))
+	require.Equal(t, "/someEmbedded/identifier", nicejsonpb.ErrorPointer(err))
+}
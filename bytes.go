@@ -0,0 +1,88 @@
+package nicejsonpb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// BytesEncoding selects how the strings of bytes fields are decoded.
+type BytesEncoding int
+
+const (
+	// BytesBase64 decodes bytes fields from base64, in either the standard or the URL-safe alphabet and
+	// with or without padding, as the proto3 JSON mapping specifies. This is the default.
+	BytesBase64 BytesEncoding = iota
+	// BytesHex decodes bytes fields from hexadecimal strings, as commonly used for hashes and keys.
+	BytesHex
+	// BytesAuto decodes bytes fields from hex if the string is made only of an even number of hex
+	// digits, and from base64 otherwise. Short base64 strings can look like hex, so this is only for
+	// clients that are known to send hex.
+	BytesAuto
+)
+
+// EncodeBytes returns b as the string of a bytes field that u decodes back to b: in hex if its
+// BytesEncoding, or that of its Profile, takes hex, and in base64 otherwise. It is for documents
+// converted to JSON from formats with binary values of their own, such as the files of forms.
+func (u *Unmarshaler) EncodeBytes(b []byte) string {
+	d := u.newDecodeState()
+	defer d.release()
+	if d.BytesEncoding == BytesBase64 {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// parseBytes decodes raw as the value of a bytes field, a string encoded as set by BytesEncoding.
+func (d *decodeState) parseBytes(raw json.RawMessage) ([]byte, error) {
+	if isNull(raw) {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, correctJsonType(err, reflect.TypeOf([]byte(nil)))
+	}
+	switch d.BytesEncoding {
+	case BytesHex:
+		return d.decodeHex(s)
+	case BytesAuto:
+		if len(s)%2 == 0 && strings.Trim(s, hexDigits) == "" {
+			return d.decodeHex(s)
+		}
+	}
+	return decodeBase64(s)
+}
+
+const hexDigits = "0123456789abcdefABCDEF"
+
+func (d *decodeState) decodeHex(s string) ([]byte, error) {
+	if i := strings.IndexFunc(s, func(r rune) bool { return !strings.ContainsRune(hexDigits, r) }); i >= 0 {
+		if d.RedactValues {
+			return nil, codeErrorf(CodeBadBytes, "invalid hex character at offset %d", i)
+		}
+		return nil, codeErrorf(CodeBadBytes, "invalid hex character %q at offset %d", []rune(s[i:])[0], i)
+	}
+	if len(s)%2 != 0 {
+		return nil, codeErrorf(CodeBadBytes, "invalid hex of odd length %d", len(s))
+	}
+	return hex.DecodeString(s)
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	b, err := enc.DecodeString(s)
+	var corrupt base64.CorruptInputError
+	if errors.As(err, &corrupt) {
+		return nil, codeErrorf(CodeBadBytes, "invalid base64 at offset %d", int64(corrupt))
+	}
+	return b, err
+}
@@ -0,0 +1,51 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_BytesBase64Variants(t *testing.T) {
+	want := []byte{0xfb, 0xff, 0xbf, 0x01}
+	for _, encoded := range []string{"+/+/AQ==", "+/+/AQ", "-_-_AQ==", "-_-_AQ"} {
+		stuff := &validatortest.Message3{}
+		require.NoError(t, nicejsonpb.UnmarshalString(`{"someBytes": "`+encoded+`"}`, stuff), encoded)
+		require.Equal(t, want, stuff.SomeBytes, encoded)
+
+		msg, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"someBytes": "`+encoded+`"}`), message3Descriptor(t))
+		require.NoError(t, err, encoded)
+		require.Equal(t, want, msg.Get(msg.Descriptor().Fields().ByName("some_bytes")).Bytes(), encoded)
+	}
+}
+
+func TestUnmarshal_BytesErrors(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someBytes": "aGVsbG8gd29ybGQ!"}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeBytes: invalid base64 at offset 15")
+	err = nicejsonpb.UnmarshalString(`{"someBytes": 12}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeBytes: json: cannot unmarshal number into Go value of type []uint8")
+}
+
+func TestUnmarshal_BytesEncodingHex(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{BytesEncoding: nicejsonpb.BytesHex}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someBytes": "DEADbeef"}`), stuff))
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, stuff.SomeBytes)
+
+	err := u.Unmarshal(strings.NewReader(`{"someBytes": "deadbee"}`), stuff)
+	require.EqualError(t, err, "unparsable field SomeBytes: invalid hex of odd length 7")
+	err = u.Unmarshal(strings.NewReader(`{"someBytes": "deadbeeg"}`), stuff)
+	require.EqualError(t, err, `unparsable field SomeBytes: invalid hex character 'g' at offset 7`)
+}
+
+func TestUnmarshal_BytesEncodingAuto(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{BytesEncoding: nicejsonpb.BytesAuto}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someBytes": "0a0b"}`), stuff))
+	require.Equal(t, []byte{0x0a, 0x0b}, stuff.SomeBytes)
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someBytes": "aGk="}`), stuff))
+	require.Equal(t, []byte("hi"), stuff.SomeBytes)
+}
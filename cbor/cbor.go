@@ -0,0 +1,85 @@
+// Package cbor decodes CBOR (RFC 8949) documents into protocol buffers, for services that accept
+// application/cbor next to JSON. Documents are converted to JSON and decoded by a
+// nicejsonpb.Unmarshaler, so they are decoded with the same options and fail with the same field
+// errors as the equivalent JSON:
+//
+//	err := cbor.UnmarshalCBOR(r, pb)
+//
+// Byte strings become strings in the encoding the Unmarshaler decodes bytes fields from, date/time
+// tags RFC 3339 strings, bignums numbers, and map keys of other types than strings are formatted the
+// way JSON map keys are. Other tags are ignored, leaving the values they enclose.
+package cbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+)
+
+// decMode decodes values into the types jsonValue knows.
+var decMode, _ = cbor.DecOptions{
+	TimeTagToAny: cbor.TimeTagToRFC3339Nano,
+	BigIntDec:    cbor.BigIntDecodePointer,
+}.DecMode()
+
+// UnmarshalCBOR decodes the CBOR document held in r into pb with the default options.
+func UnmarshalCBOR(r io.Reader, pb proto.Message) error {
+	return UnmarshalCBORWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalCBORWith decodes the CBOR document held in r into pb with the options of u.
+func UnmarshalCBORWith(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	var doc interface{}
+	if err := decMode.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	b, err := json.Marshal(jsonValue(u, doc))
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBytes(b, pb)
+}
+
+// jsonValue converts v, a decoded CBOR value, to one encoding/json marshals the way the JSON mapping
+// of protocol buffers wants it, and u decodes.
+func jsonValue(u *nicejsonpb.Unmarshaler, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			if b, ok := k.(cbor.ByteString); ok {
+				k = string(b)
+			}
+			obj[fmt.Sprint(k)] = jsonValue(u, elem)
+		}
+		return obj
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = jsonValue(u, elem)
+		}
+	case cbor.Tag:
+		return jsonValue(u, v.Content)
+	case *big.Int:
+		return json.Number(v.String())
+	case []byte:
+		return u.EncodeBytes(v)
+	case float32:
+		return jsonValue(u, float64(v))
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN"
+		case math.IsInf(v, 1):
+			return "Infinity"
+		case math.IsInf(v, -1):
+			return "-Infinity"
+		}
+	}
+	return v
+}
@@ -0,0 +1,72 @@
+package cbor_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mwitkow/go-nicejsonpb"
+	nicecbor "github.com/mwitkow/go-nicejsonpb/cbor"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, v interface{}) *bytes.Reader {
+	em, err := cbor.EncOptions{Time: cbor.TimeRFC3339Nano, TimeTag: cbor.EncTagRequired}.EncMode()
+	require.NoError(t, err)
+	b, err := em.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewReader(b)
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	doc := map[string]interface{}{
+		"someString":        "hello",
+		"someUint64":        new(big.Int).SetUint64(math.MaxUint64),
+		"someInt64":         cbor.Tag{Number: 55799, Content: int64(-3)},
+		"someFloat":         float32(math.Inf(-1)),
+		"someBytes":         []byte{0, 1, 2},
+		"someStringRep":     []string{"a", "b"},
+		"someInt32ToString": map[int32]string{-1: "minus one"},
+		"someEmbedded":      map[string]interface{}{"identifier": "x", "children": []interface{}{map[string]interface{}{"someValue": 3}}},
+		"someTimestamp":     time.Date(2024, 5, 1, 10, 0, 0, 5, time.UTC),
+	}
+	m := &validatortest.Message3{}
+	require.NoError(t, nicecbor.UnmarshalCBOR(encode(t, doc), m))
+	require.Equal(t, "hello", m.SomeString)
+	require.Equal(t, uint64(math.MaxUint64), m.SomeUint64)
+	require.Equal(t, int64(-3), m.SomeInt64)
+	require.True(t, math.IsInf(float64(m.SomeFloat), -1))
+	require.Equal(t, []byte{0, 1, 2}, m.SomeBytes)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, map[int32]string{-1: "minus one"}, m.SomeInt32ToString)
+	require.Equal(t, int64(3), m.SomeEmbedded.Children[0].SomeValue)
+	require.Equal(t, int32(5), m.SomeTimestamp.Nanos)
+}
+
+func TestUnmarshalCBOR_FieldErrors(t *testing.T) {
+	doc := map[string]interface{}{"someEmbedded": map[string]interface{}{"children": []interface{}{map[string]interface{}{"someValue": true}}}}
+	err := nicecbor.UnmarshalCBOR(encode(t, doc), &validatortest.Message3{})
+	want := nicejsonpb.UnmarshalString(`{"someEmbedded": {"children": [{"someValue": true}]}}`, &validatortest.Message3{})
+	require.EqualError(t, err, want.Error())
+	require.Equal(t, "/someEmbedded/children/0/someValue", nicejsonpb.ErrorPointer(err))
+
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, nicecbor.UnmarshalCBORWith(u, encode(t, map[string]int{"other": 1}), &validatortest.Message3{}))
+}
+
+func TestUnmarshalCBOR_BytesEncoding(t *testing.T) {
+	for _, u := range []*nicejsonpb.Unmarshaler{
+		{BytesEncoding: nicejsonpb.BytesHex},
+		{BytesEncoding: nicejsonpb.BytesAuto},
+		{Profile: nicejsonpb.ProfileLenient},
+	} {
+		m := &validatortest.Message3{}
+		// In base64, "abcd", which also reads as hex.
+		require.NoError(t, nicecbor.UnmarshalCBORWith(u, encode(t, map[string]interface{}{"someBytes": []byte{0x69, 0xb7, 0x1d}}), m))
+		require.Equal(t, []byte{0x69, 0xb7, 0x1d}, m.SomeBytes)
+	}
+}
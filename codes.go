@@ -0,0 +1,139 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Code classifies the failures of an unmarshal, so that callers can pick HTTP statuses or
+// localized messages without looking at the error messages.
+type Code int
+
+const (
+	// CodeNone is the code of a nil error.
+	CodeNone Code = iota
+	// CodeOther is the code of errors that fit no other class, such as errors from reading the input.
+	CodeOther
+	// CodeSyntax is for input that isn't well-formed JSON, including truncated input.
+	CodeSyntax
+	// CodeUnknownField is for fields that don't exist in the message.
+	CodeUnknownField
+	// CodeUnknownType is for message types that the TypeResolver doesn't know.
+	CodeUnknownType
+	// CodeTypeMismatch is for JSON values of a type the field can't take, such as a string for a bool.
+	CodeTypeMismatch
+	// CodeEnumUnknownValue is for enum value names that don't exist in the enum.
+	CodeEnumUnknownValue
+	// CodeOutOfRange is for numbers that the field can't hold.
+	CodeOutOfRange
+	// CodeBadBytes is for bytes fields that aren't properly encoded.
+	CodeBadBytes
+	// CodeBadString is for string fields holding invalid UTF-8 or control characters, see
+	// RejectInvalidUTF8 and RejectControlChars.
+	CodeBadString
+	// CodeBadMapKey is for map keys that can't be parsed as the key type of the map.
+	CodeBadMapKey
+	// CodeBadTimestamp is for malformed google.protobuf.Timestamp values.
+	CodeBadTimestamp
+	// CodeBadDuration is for malformed google.protobuf.Duration values.
+	CodeBadDuration
+	// CodeBadDate is for malformed google.type.Date and google.type.TimeOfDay values.
+	CodeBadDate
+	// CodeBadMoney is for malformed google.type.Money values, see MoneyStrings.
+	CodeBadMoney
+	// CodeOneofConflict is for more than one member of a oneof being set.
+	CodeOneofConflict
+	// CodeRequiredFieldMissing is for required fields left unset.
+	CodeRequiredFieldMissing
+	// CodeFieldBehavior is for fields that the google.api.field_behavior annotation forbids to
+	// set, see OutputOnly and RejectImmutableUpdates.
+	CodeFieldBehavior
+	// CodeValidation is for messages failing their Validate method.
+	CodeValidation
+	// CodeLimitExceeded is for documents going beyond MaxDepth, MaxNodes or MaxInputBytes.
+	CodeLimitExceeded
+	// CodeUnsupported is for values the unmarshaler doesn't know how to decode.
+	CodeUnsupported
+)
+
+var codeNames = map[Code]string{
+	CodeNone:                 "None",
+	CodeOther:                "Other",
+	CodeSyntax:               "Syntax",
+	CodeUnknownField:         "UnknownField",
+	CodeUnknownType:          "UnknownType",
+	CodeTypeMismatch:         "TypeMismatch",
+	CodeEnumUnknownValue:     "EnumUnknownValue",
+	CodeOutOfRange:           "OutOfRange",
+	CodeBadBytes:             "BadBytes",
+	CodeBadString:            "BadString",
+	CodeBadMapKey:            "BadMapKey",
+	CodeBadTimestamp:         "BadTimestamp",
+	CodeBadDuration:          "BadDuration",
+	CodeBadDate:              "BadDate",
+	CodeBadMoney:             "BadMoney",
+	CodeOneofConflict:        "OneofConflict",
+	CodeRequiredFieldMissing: "RequiredFieldMissing",
+	CodeFieldBehavior:        "FieldBehavior",
+	CodeValidation:           "Validation",
+	CodeLimitExceeded:        "LimitExceeded",
+	CodeUnsupported:          "Unsupported",
+}
+
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Code(%d)", int(c))
+}
+
+// ErrorCode returns the class of err, which may be wrapped. For a MultiError it is that of the
+// first error.
+func ErrorCode(err error) Code {
+	if err == nil {
+		return CodeNone
+	}
+	var cErr *codedError
+	if errors.As(err, &cErr) {
+		return cErr.code
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return CodeSyntax
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return CodeTypeMismatch
+	}
+	return CodeOther
+}
+
+// codedError is an error of a known class. format and args are what its message is made from, for
+// ErrorFormatter.
+type codedError struct {
+	code   Code
+	err    error
+	format string
+	args   []interface{}
+}
+
+func (c *codedError) Error() string {
+	return c.err.Error()
+}
+
+func (c *codedError) Unwrap() error {
+	return c.err
+}
+
+// codeErrorf is fmt.Errorf for an error of the given class.
+func codeErrorf(code Code, format string, a ...interface{}) error {
+	return &codedError{code: code, err: fmt.Errorf(format, a...), format: format, args: a}
+}
+
+// newCodedError is errors.New for an error of the given class.
+func newCodedError(code Code, text string) error {
+	return &codedError{code: code, err: errors.New(text), format: strings.ReplaceAll(text, "%", "%%")}
+}
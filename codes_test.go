@@ -0,0 +1,50 @@
+package nicejsonpb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		code  nicejsonpb.Code
+	}{
+		{`{"someString": "ok"}`, nicejsonpb.CodeNone},
+		{`{"someString": `, nicejsonpb.CodeSyntax},
+		{`{"someString": x}`, nicejsonpb.CodeSyntax},
+		{`{"noSuchField": 1}`, nicejsonpb.CodeUnknownField},
+		{`{"someBool": "yes"}`, nicejsonpb.CodeTypeMismatch},
+		{`{"someInt32": 1.5}`, nicejsonpb.CodeTypeMismatch},
+		{`{"someStatus": "NOPE"}`, nicejsonpb.CodeEnumUnknownValue},
+		{`{"someUint32": -1}`, nicejsonpb.CodeOutOfRange},
+		{`{"someBytes": "!!"}`, nicejsonpb.CodeBadBytes},
+		{`{"someInt32ToString": {"one": "x"}}`, nicejsonpb.CodeBadMapKey},
+		{`{"someTimestamp": "yesterday"}`, nicejsonpb.CodeBadTimestamp},
+		{`{"someDuration": "long"}`, nicejsonpb.CodeBadDuration},
+		{`{"email": "a@b.c", "phone": "123"}`, nicejsonpb.CodeOneofConflict},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{})
+		require.Equal(t, tc.code, nicejsonpb.ErrorCode(err), "%s: %v", tc.input, err)
+
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.Equal(t, tc.code, nicejsonpb.ErrorCode(err), "%s: %v", tc.input, err)
+	}
+}
+
+func TestErrorCode_Limits(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMaxDepth(1))
+	err := u.Unmarshal(strings.NewReader(`{"someEmbedded": {}}`), &validatortest.Message3{})
+	require.Equal(t, nicejsonpb.CodeLimitExceeded, nicejsonpb.ErrorCode(err))
+}
+
+func TestErrorCode_String(t *testing.T) {
+	require.Equal(t, "EnumUnknownValue", nicejsonpb.CodeEnumUnknownValue.String())
+	require.Equal(t, "Code(100)", nicejsonpb.Code(100).String())
+	require.Equal(t, nicejsonpb.CodeOther, nicejsonpb.ErrorCode(errors.New("boom")))
+}
@@ -0,0 +1,102 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Coercer rewrites the JSON values of scalar fields before they are decoded, so that forms the
+// decoder doesn't accept, such as "yes" for true or numbers with thousands separators, can be.
+type Coercer interface {
+	// Coerce returns the JSON value to decode in place of raw, the value of a field of the given
+	// kind at path, named as in field errors. Returning raw leaves it as it is, and returning an
+	// error fails the field with it.
+	Coerce(kind protoreflect.Kind, raw json.RawMessage, path string) (json.RawMessage, error)
+}
+
+// CoercerFunc is a Coercer that is a plain function.
+type CoercerFunc func(kind protoreflect.Kind, raw json.RawMessage, path string) (json.RawMessage, error)
+
+func (f CoercerFunc) Coerce(kind protoreflect.Kind, raw json.RawMessage, path string) (json.RawMessage, error) {
+	return f(kind, raw, path)
+}
+
+// coercesScalars reports whether coerce has anything to do.
+func (d *decodeState) coercesScalars() bool {
+	return d.Coercer != nil || d.EmptyStringAsZero
+}
+
+// coerce rewrites raw, the value of a scalar field of the given kind, with the Coercer and then
+// the options that accept other forms of scalars.
+func (d *decodeState) coerce(kind protoreflect.Kind, raw json.RawMessage) (json.RawMessage, error) {
+	if d.Coercer != nil {
+		out, err := d.Coercer.Coerce(kind, raw, strings.Join(d.path, "."))
+		if err != nil {
+			return nil, err
+		}
+		if len(out) == 0 {
+			return nil, codeErrorf(CodeOther, "Coercer returned no value for %s", kind)
+		}
+		raw = out
+	}
+	if d.EmptyStringAsZero && string(raw) == `""` {
+		switch kind {
+		case protoreflect.StringKind, protoreflect.BytesKind:
+		case protoreflect.BoolKind:
+			return json.RawMessage("false"), nil
+		default:
+			return json.RawMessage("0"), nil
+		}
+	}
+	return raw, nil
+}
+
+// scalarKind returns the kind of the scalar field of Go type goType with the given properties,
+// which may be nil, or false if goType doesn't hold a scalar.
+func scalarKind(goType reflect.Type, prop *proto.Properties) (protoreflect.Kind, bool) {
+	wire := ""
+	if prop != nil {
+		if prop.Enum != "" {
+			return protoreflect.EnumKind, true
+		}
+		wire = prop.Wire
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		return protoreflect.BoolKind, true
+	case reflect.Int32:
+		return pickKind(wire, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind), true
+	case reflect.Int64:
+		return pickKind(wire, protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind), true
+	case reflect.Uint32:
+		return pickKind(wire, protoreflect.Uint32Kind, protoreflect.Uint32Kind, protoreflect.Fixed32Kind), true
+	case reflect.Uint64:
+		return pickKind(wire, protoreflect.Uint64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind), true
+	case reflect.Float32:
+		return protoreflect.FloatKind, true
+	case reflect.Float64:
+		return protoreflect.DoubleKind, true
+	case reflect.String:
+		return protoreflect.StringKind, true
+	case reflect.Slice:
+		if goType.Elem().Kind() == reflect.Uint8 {
+			return protoreflect.BytesKind, true
+		}
+	}
+	return 0, false
+}
+
+// pickKind picks the kind of an integer field from its wire encoding in proto.Properties.
+func pickKind(wire string, varint, zigzag, fixed protoreflect.Kind) protoreflect.Kind {
+	switch wire {
+	case "zigzag32", "zigzag64":
+		return zigzag
+	case "fixed32", "fixed64":
+		return fixed
+	}
+	return varint
+}
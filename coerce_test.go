@@ -0,0 +1,74 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// shopCoercer accepts "yes" and "no" for booleans and numbers with thousands separators.
+var shopCoercer = nicejsonpb.CoercerFunc(func(kind protoreflect.Kind, raw json.RawMessage, path string) (json.RawMessage, error) {
+	switch {
+	case kind == protoreflect.BoolKind && string(raw) == `"yes"`:
+		return json.RawMessage("true"), nil
+	case kind == protoreflect.BoolKind && string(raw) == `"no"`:
+		return json.RawMessage("false"), nil
+	case kind == protoreflect.Int64Kind && strings.Contains(string(raw), ","):
+		return json.RawMessage(strings.ReplaceAll(string(raw), ",", "")), nil
+	case kind == protoreflect.EnumKind && path == "SomeStatus" && string(raw) == `"gone"`:
+		return nil, errors.New("gone is no longer a status")
+	}
+	return raw, nil
+})
+
+func TestUnmarshal_Coercer(t *testing.T) {
+	input := `{"someBool": "yes", "someInt64": "1,234,567", "someStringToInt64": {"a": "2,000"}, "someWrappedInt": "1,000", "someString": "yes"}`
+	u := nicejsonpb.New(nicejsonpb.WithCoercer(shopCoercer))
+
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.True(t, m.SomeBool)
+	require.Equal(t, int64(1234567), m.SomeInt64)
+	require.Equal(t, map[string]int64{"a": 2000}, m.SomeStringToInt64)
+	require.Equal(t, int64(1000), m.SomeWrappedInt.Value)
+	require.Equal(t, "yes", m.SomeString)
+
+	desc := message3Descriptor(t)
+	dm, err := u.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.NoError(t, err)
+	require.True(t, dm.Get(desc.Fields().ByName("some_bool")).Bool())
+	require.Equal(t, int64(1234567), dm.Get(desc.Fields().ByName("some_int64")).Int())
+
+	err = u.Unmarshal(strings.NewReader(`{"someStatus": "gone"}`), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeStatus: gone is no longer a status")
+	_, err = u.UnmarshalDynamic(strings.NewReader(`{"someStatus": "gone"}`), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeStatus: gone is no longer a status")
+
+	err = nicejsonpb.New().Unmarshal(strings.NewReader(`{"someBool": "yes"}`), &validatortest.Message3{})
+	require.Error(t, err)
+}
+
+func TestUnmarshal_EmptyStringAsZero(t *testing.T) {
+	input := `{"someInt32": "", "someUint64": "", "someDouble": "", "someBool": "", "someStatus": "", "someString": "", "someIntRep": ["", "2"], "someOptionalInt32": ""}`
+	u := nicejsonpb.New(nicejsonpb.WithEmptyStringAsZero(), nicejsonpb.WithAcceptStringNumbers())
+
+	m := &validatortest.Message3{SomeInt32: 4, SomeBool: true, SomeStatus: validatortest.Status_STATUS_ACTIVE}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, int32(0), m.SomeInt32)
+	require.False(t, m.SomeBool)
+	require.Equal(t, validatortest.Status_STATUS_UNKNOWN, m.SomeStatus)
+	require.Equal(t, []uint32{0, 2}, m.SomeIntRep)
+	require.Equal(t, int32(0), m.GetSomeOptionalInt32())
+
+	_, err := u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+
+	err = nicejsonpb.New().Unmarshal(strings.NewReader(`{"someInt32": ""}`), &validatortest.Message3{})
+	require.Error(t, err)
+}
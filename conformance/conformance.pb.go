@@ -0,0 +1,641 @@
+// The messages exchanged with the conformance test runner of the protobuf repository, as defined in
+// its conformance/conformance.proto. Only what the testee needs is kept.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: conformance.proto
+
+package main
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WireFormat int32
+
+const (
+	WireFormat_UNSPECIFIED WireFormat = 0
+	WireFormat_PROTOBUF    WireFormat = 1
+	WireFormat_JSON        WireFormat = 2
+	WireFormat_JSPB        WireFormat = 3
+	WireFormat_TEXT_FORMAT WireFormat = 4
+)
+
+// Enum value maps for WireFormat.
+var (
+	WireFormat_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "PROTOBUF",
+		2: "JSON",
+		3: "JSPB",
+		4: "TEXT_FORMAT",
+	}
+	WireFormat_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"PROTOBUF":    1,
+		"JSON":        2,
+		"JSPB":        3,
+		"TEXT_FORMAT": 4,
+	}
+)
+
+func (x WireFormat) Enum() *WireFormat {
+	p := new(WireFormat)
+	*p = x
+	return p
+}
+
+func (x WireFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WireFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_conformance_proto_enumTypes[0].Descriptor()
+}
+
+func (WireFormat) Type() protoreflect.EnumType {
+	return &file_conformance_proto_enumTypes[0]
+}
+
+func (x WireFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WireFormat.Descriptor instead.
+func (WireFormat) EnumDescriptor() ([]byte, []int) {
+	return file_conformance_proto_rawDescGZIP(), []int{0}
+}
+
+type TestCategory int32
+
+const (
+	TestCategory_UNSPECIFIED_TEST                 TestCategory = 0
+	TestCategory_BINARY_TEST                      TestCategory = 1
+	TestCategory_JSON_TEST                        TestCategory = 2
+	TestCategory_JSON_IGNORE_UNKNOWN_PARSING_TEST TestCategory = 3
+	TestCategory_JSPB_TEST                        TestCategory = 4
+	TestCategory_TEXT_FORMAT_TEST                 TestCategory = 5
+)
+
+// Enum value maps for TestCategory.
+var (
+	TestCategory_name = map[int32]string{
+		0: "UNSPECIFIED_TEST",
+		1: "BINARY_TEST",
+		2: "JSON_TEST",
+		3: "JSON_IGNORE_UNKNOWN_PARSING_TEST",
+		4: "JSPB_TEST",
+		5: "TEXT_FORMAT_TEST",
+	}
+	TestCategory_value = map[string]int32{
+		"UNSPECIFIED_TEST":                 0,
+		"BINARY_TEST":                      1,
+		"JSON_TEST":                        2,
+		"JSON_IGNORE_UNKNOWN_PARSING_TEST": 3,
+		"JSPB_TEST":                        4,
+		"TEXT_FORMAT_TEST":                 5,
+	}
+)
+
+func (x TestCategory) Enum() *TestCategory {
+	p := new(TestCategory)
+	*p = x
+	return p
+}
+
+func (x TestCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TestCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_conformance_proto_enumTypes[1].Descriptor()
+}
+
+func (TestCategory) Type() protoreflect.EnumType {
+	return &file_conformance_proto_enumTypes[1]
+}
+
+func (x TestCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TestCategory.Descriptor instead.
+func (TestCategory) EnumDescriptor() ([]byte, []int) {
+	return file_conformance_proto_rawDescGZIP(), []int{1}
+}
+
+// The failures expected by the runner, which the testee is asked for first. The testee expects none.
+type FailureSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Failure       []string               `protobuf:"bytes,1,rep,name=failure,proto3" json:"failure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailureSet) Reset() {
+	*x = FailureSet{}
+	mi := &file_conformance_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailureSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailureSet) ProtoMessage() {}
+
+func (x *FailureSet) ProtoReflect() protoreflect.Message {
+	mi := &file_conformance_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailureSet.ProtoReflect.Descriptor instead.
+func (*FailureSet) Descriptor() ([]byte, []int) {
+	return file_conformance_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FailureSet) GetFailure() []string {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+type ConformanceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ConformanceRequest_ProtobufPayload
+	//	*ConformanceRequest_JsonPayload
+	//	*ConformanceRequest_JspbPayload
+	//	*ConformanceRequest_TextPayload
+	Payload               isConformanceRequest_Payload `protobuf_oneof:"payload"`
+	RequestedOutputFormat WireFormat                   `protobuf:"varint,3,opt,name=requested_output_format,json=requestedOutputFormat,proto3,enum=conformance.WireFormat" json:"requested_output_format,omitempty"`
+	MessageType           string                       `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	TestCategory          TestCategory                 `protobuf:"varint,5,opt,name=test_category,json=testCategory,proto3,enum=conformance.TestCategory" json:"test_category,omitempty"`
+	PrintUnknownFields    bool                         `protobuf:"varint,9,opt,name=print_unknown_fields,json=printUnknownFields,proto3" json:"print_unknown_fields,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ConformanceRequest) Reset() {
+	*x = ConformanceRequest{}
+	mi := &file_conformance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConformanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConformanceRequest) ProtoMessage() {}
+
+func (x *ConformanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_conformance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConformanceRequest.ProtoReflect.Descriptor instead.
+func (*ConformanceRequest) Descriptor() ([]byte, []int) {
+	return file_conformance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConformanceRequest) GetPayload() isConformanceRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ConformanceRequest) GetProtobufPayload() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ConformanceRequest_ProtobufPayload); ok {
+			return x.ProtobufPayload
+		}
+	}
+	return nil
+}
+
+func (x *ConformanceRequest) GetJsonPayload() string {
+	if x != nil {
+		if x, ok := x.Payload.(*ConformanceRequest_JsonPayload); ok {
+			return x.JsonPayload
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceRequest) GetJspbPayload() string {
+	if x != nil {
+		if x, ok := x.Payload.(*ConformanceRequest_JspbPayload); ok {
+			return x.JspbPayload
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceRequest) GetTextPayload() string {
+	if x != nil {
+		if x, ok := x.Payload.(*ConformanceRequest_TextPayload); ok {
+			return x.TextPayload
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceRequest) GetRequestedOutputFormat() WireFormat {
+	if x != nil {
+		return x.RequestedOutputFormat
+	}
+	return WireFormat_UNSPECIFIED
+}
+
+func (x *ConformanceRequest) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *ConformanceRequest) GetTestCategory() TestCategory {
+	if x != nil {
+		return x.TestCategory
+	}
+	return TestCategory_UNSPECIFIED_TEST
+}
+
+func (x *ConformanceRequest) GetPrintUnknownFields() bool {
+	if x != nil {
+		return x.PrintUnknownFields
+	}
+	return false
+}
+
+type isConformanceRequest_Payload interface {
+	isConformanceRequest_Payload()
+}
+
+type ConformanceRequest_ProtobufPayload struct {
+	ProtobufPayload []byte `protobuf:"bytes,1,opt,name=protobuf_payload,json=protobufPayload,proto3,oneof"`
+}
+
+type ConformanceRequest_JsonPayload struct {
+	JsonPayload string `protobuf:"bytes,2,opt,name=json_payload,json=jsonPayload,proto3,oneof"`
+}
+
+type ConformanceRequest_JspbPayload struct {
+	JspbPayload string `protobuf:"bytes,7,opt,name=jspb_payload,json=jspbPayload,proto3,oneof"`
+}
+
+type ConformanceRequest_TextPayload struct {
+	TextPayload string `protobuf:"bytes,8,opt,name=text_payload,json=textPayload,proto3,oneof"`
+}
+
+func (*ConformanceRequest_ProtobufPayload) isConformanceRequest_Payload() {}
+
+func (*ConformanceRequest_JsonPayload) isConformanceRequest_Payload() {}
+
+func (*ConformanceRequest_JspbPayload) isConformanceRequest_Payload() {}
+
+func (*ConformanceRequest_TextPayload) isConformanceRequest_Payload() {}
+
+type ConformanceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ConformanceResponse_ParseError
+	//	*ConformanceResponse_SerializeError
+	//	*ConformanceResponse_TimeoutError
+	//	*ConformanceResponse_RuntimeError
+	//	*ConformanceResponse_ProtobufPayload
+	//	*ConformanceResponse_JsonPayload
+	//	*ConformanceResponse_Skipped
+	//	*ConformanceResponse_JspbPayload
+	//	*ConformanceResponse_TextPayload
+	Result        isConformanceResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConformanceResponse) Reset() {
+	*x = ConformanceResponse{}
+	mi := &file_conformance_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConformanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConformanceResponse) ProtoMessage() {}
+
+func (x *ConformanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_conformance_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConformanceResponse.ProtoReflect.Descriptor instead.
+func (*ConformanceResponse) Descriptor() ([]byte, []int) {
+	return file_conformance_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConformanceResponse) GetResult() isConformanceResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ConformanceResponse) GetParseError() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_ParseError); ok {
+			return x.ParseError
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetSerializeError() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_SerializeError); ok {
+			return x.SerializeError
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetTimeoutError() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_TimeoutError); ok {
+			return x.TimeoutError
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetRuntimeError() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_RuntimeError); ok {
+			return x.RuntimeError
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetProtobufPayload() []byte {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_ProtobufPayload); ok {
+			return x.ProtobufPayload
+		}
+	}
+	return nil
+}
+
+func (x *ConformanceResponse) GetJsonPayload() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_JsonPayload); ok {
+			return x.JsonPayload
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetSkipped() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_Skipped); ok {
+			return x.Skipped
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetJspbPayload() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_JspbPayload); ok {
+			return x.JspbPayload
+		}
+	}
+	return ""
+}
+
+func (x *ConformanceResponse) GetTextPayload() string {
+	if x != nil {
+		if x, ok := x.Result.(*ConformanceResponse_TextPayload); ok {
+			return x.TextPayload
+		}
+	}
+	return ""
+}
+
+type isConformanceResponse_Result interface {
+	isConformanceResponse_Result()
+}
+
+type ConformanceResponse_ParseError struct {
+	ParseError string `protobuf:"bytes,1,opt,name=parse_error,json=parseError,proto3,oneof"`
+}
+
+type ConformanceResponse_SerializeError struct {
+	SerializeError string `protobuf:"bytes,6,opt,name=serialize_error,json=serializeError,proto3,oneof"`
+}
+
+type ConformanceResponse_TimeoutError struct {
+	TimeoutError string `protobuf:"bytes,9,opt,name=timeout_error,json=timeoutError,proto3,oneof"`
+}
+
+type ConformanceResponse_RuntimeError struct {
+	RuntimeError string `protobuf:"bytes,2,opt,name=runtime_error,json=runtimeError,proto3,oneof"`
+}
+
+type ConformanceResponse_ProtobufPayload struct {
+	ProtobufPayload []byte `protobuf:"bytes,3,opt,name=protobuf_payload,json=protobufPayload,proto3,oneof"`
+}
+
+type ConformanceResponse_JsonPayload struct {
+	JsonPayload string `protobuf:"bytes,4,opt,name=json_payload,json=jsonPayload,proto3,oneof"`
+}
+
+type ConformanceResponse_Skipped struct {
+	Skipped string `protobuf:"bytes,5,opt,name=skipped,proto3,oneof"`
+}
+
+type ConformanceResponse_JspbPayload struct {
+	JspbPayload string `protobuf:"bytes,7,opt,name=jspb_payload,json=jspbPayload,proto3,oneof"`
+}
+
+type ConformanceResponse_TextPayload struct {
+	TextPayload string `protobuf:"bytes,8,opt,name=text_payload,json=textPayload,proto3,oneof"`
+}
+
+func (*ConformanceResponse_ParseError) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_SerializeError) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_TimeoutError) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_RuntimeError) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_ProtobufPayload) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_JsonPayload) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_Skipped) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_JspbPayload) isConformanceResponse_Result() {}
+
+func (*ConformanceResponse_TextPayload) isConformanceResponse_Result() {}
+
+var File_conformance_proto protoreflect.FileDescriptor
+
+const file_conformance_proto_rawDesc = "" +
+	"\n" +
+	"\x11conformance.proto\x12\vconformance\"&\n" +
+	"\n" +
+	"FailureSet\x12\x18\n" +
+	"\afailure\x18\x01 \x03(\tR\afailure\"\xa1\x03\n" +
+	"\x12ConformanceRequest\x12+\n" +
+	"\x10protobuf_payload\x18\x01 \x01(\fH\x00R\x0fprotobufPayload\x12#\n" +
+	"\fjson_payload\x18\x02 \x01(\tH\x00R\vjsonPayload\x12#\n" +
+	"\fjspb_payload\x18\a \x01(\tH\x00R\vjspbPayload\x12#\n" +
+	"\ftext_payload\x18\b \x01(\tH\x00R\vtextPayload\x12O\n" +
+	"\x17requested_output_format\x18\x03 \x01(\x0e2\x17.conformance.WireFormatR\x15requestedOutputFormat\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\tR\vmessageType\x12>\n" +
+	"\rtest_category\x18\x05 \x01(\x0e2\x19.conformance.TestCategoryR\ftestCategory\x120\n" +
+	"\x14print_unknown_fields\x18\t \x01(\bR\x12printUnknownFieldsB\t\n" +
+	"\apayload\"\xf3\x02\n" +
+	"\x13ConformanceResponse\x12!\n" +
+	"\vparse_error\x18\x01 \x01(\tH\x00R\n" +
+	"parseError\x12)\n" +
+	"\x0fserialize_error\x18\x06 \x01(\tH\x00R\x0eserializeError\x12%\n" +
+	"\rtimeout_error\x18\t \x01(\tH\x00R\ftimeoutError\x12%\n" +
+	"\rruntime_error\x18\x02 \x01(\tH\x00R\fruntimeError\x12+\n" +
+	"\x10protobuf_payload\x18\x03 \x01(\fH\x00R\x0fprotobufPayload\x12#\n" +
+	"\fjson_payload\x18\x04 \x01(\tH\x00R\vjsonPayload\x12\x1a\n" +
+	"\askipped\x18\x05 \x01(\tH\x00R\askipped\x12#\n" +
+	"\fjspb_payload\x18\a \x01(\tH\x00R\vjspbPayload\x12#\n" +
+	"\ftext_payload\x18\b \x01(\tH\x00R\vtextPayloadB\b\n" +
+	"\x06result*P\n" +
+	"\n" +
+	"WireFormat\x12\x0f\n" +
+	"\vUNSPECIFIED\x10\x00\x12\f\n" +
+	"\bPROTOBUF\x10\x01\x12\b\n" +
+	"\x04JSON\x10\x02\x12\b\n" +
+	"\x04JSPB\x10\x03\x12\x0f\n" +
+	"\vTEXT_FORMAT\x10\x04*\x8f\x01\n" +
+	"\fTestCategory\x12\x14\n" +
+	"\x10UNSPECIFIED_TEST\x10\x00\x12\x0f\n" +
+	"\vBINARY_TEST\x10\x01\x12\r\n" +
+	"\tJSON_TEST\x10\x02\x12$\n" +
+	" JSON_IGNORE_UNKNOWN_PARSING_TEST\x10\x03\x12\r\n" +
+	"\tJSPB_TEST\x10\x04\x12\x14\n" +
+	"\x10TEXT_FORMAT_TEST\x10\x05B3Z1github.com/mwitkow/go-nicejsonpb/conformance;mainb\x06proto3"
+
+var (
+	file_conformance_proto_rawDescOnce sync.Once
+	file_conformance_proto_rawDescData []byte
+)
+
+func file_conformance_proto_rawDescGZIP() []byte {
+	file_conformance_proto_rawDescOnce.Do(func() {
+		file_conformance_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_conformance_proto_rawDesc), len(file_conformance_proto_rawDesc)))
+	})
+	return file_conformance_proto_rawDescData
+}
+
+var file_conformance_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_conformance_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_conformance_proto_goTypes = []any{
+	(WireFormat)(0),             // 0: conformance.WireFormat
+	(TestCategory)(0),           // 1: conformance.TestCategory
+	(*FailureSet)(nil),          // 2: conformance.FailureSet
+	(*ConformanceRequest)(nil),  // 3: conformance.ConformanceRequest
+	(*ConformanceResponse)(nil), // 4: conformance.ConformanceResponse
+}
+var file_conformance_proto_depIdxs = []int32{
+	0, // 0: conformance.ConformanceRequest.requested_output_format:type_name -> conformance.WireFormat
+	1, // 1: conformance.ConformanceRequest.test_category:type_name -> conformance.TestCategory
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_conformance_proto_init() }
+func file_conformance_proto_init() {
+	if File_conformance_proto != nil {
+		return
+	}
+	file_conformance_proto_msgTypes[1].OneofWrappers = []any{
+		(*ConformanceRequest_ProtobufPayload)(nil),
+		(*ConformanceRequest_JsonPayload)(nil),
+		(*ConformanceRequest_JspbPayload)(nil),
+		(*ConformanceRequest_TextPayload)(nil),
+	}
+	file_conformance_proto_msgTypes[2].OneofWrappers = []any{
+		(*ConformanceResponse_ParseError)(nil),
+		(*ConformanceResponse_SerializeError)(nil),
+		(*ConformanceResponse_TimeoutError)(nil),
+		(*ConformanceResponse_RuntimeError)(nil),
+		(*ConformanceResponse_ProtobufPayload)(nil),
+		(*ConformanceResponse_JsonPayload)(nil),
+		(*ConformanceResponse_Skipped)(nil),
+		(*ConformanceResponse_JspbPayload)(nil),
+		(*ConformanceResponse_TextPayload)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conformance_proto_rawDesc), len(file_conformance_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_conformance_proto_goTypes,
+		DependencyIndexes: file_conformance_proto_depIdxs,
+		EnumInfos:         file_conformance_proto_enumTypes,
+		MessageInfos:      file_conformance_proto_msgTypes,
+	}.Build()
+	File_conformance_proto = out.File
+	file_conformance_proto_goTypes = nil
+	file_conformance_proto_depIdxs = nil
+}
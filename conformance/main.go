@@ -0,0 +1,159 @@
+// Command conformance is a testee for the conformance test runner of the protobuf repository,
+// decoding JSON with the ProtoJSON mode of nicejsonpb. It checks that the mode accepts and rejects
+// the same documents as google.golang.org/protobuf/encoding/protojson.
+//
+// The runner doesn't come with the test messages, so their descriptors are loaded at startup from
+// the FileDescriptorSet named by $NICEJSONPB_CONFORMANCE_DESCRIPTORS:
+//
+//	protoc --include_imports --descriptor_set_out=test_messages.pb \
+//		google/protobuf/test_messages_proto2.proto google/protobuf/test_messages_proto3.proto
+//	go build -o nicejsonpb-conformance ./conformance
+//	NICEJSONPB_CONFORMANCE_DESCRIPTORS=test_messages.pb conformance_test_runner ./nicejsonpb-conformance
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func main() {
+	if err := registerDescriptors(os.Getenv("NICEJSONPB_CONFORMANCE_DESCRIPTORS")); err != nil {
+		log.Fatal(err)
+	}
+	for {
+		req := &ConformanceRequest{}
+		if err := readRequest(os.Stdin, req); err == io.EOF {
+			return
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeResponse(os.Stdout, handle(req)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// registerDescriptors adds the messages described in the FileDescriptorSet at path to the global
+// registries, so that they can be found by name and within Any.
+func registerDescriptors(path string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	files, err := nicejsonpb.LoadDescriptorSet(b)
+	if err != nil {
+		return err
+	}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if _, err = protoregistry.GlobalFiles.FindFileByPath(fd.Path()); err == nil {
+			return true // Linked into the binary already.
+		}
+		if err = protoregistry.GlobalFiles.RegisterFile(fd); err != nil {
+			return false
+		}
+		err = registerTypes(fd.Messages(), fd.Enums(), fd.Extensions())
+		return err == nil
+	})
+	return err
+}
+
+func registerTypes(messages protoreflect.MessageDescriptors, enums protoreflect.EnumDescriptors, extensions protoreflect.ExtensionDescriptors) error {
+	for i := 0; i < enums.Len(); i++ {
+		if err := protoregistry.GlobalTypes.RegisterEnum(dynamicpb.NewEnumType(enums.Get(i))); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < extensions.Len(); i++ {
+		if err := protoregistry.GlobalTypes.RegisterExtension(dynamicpb.NewExtensionType(extensions.Get(i))); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if err := protoregistry.GlobalTypes.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+			return err
+		}
+		if err := registerTypes(md.Messages(), md.Enums(), md.Extensions()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handle(req *ConformanceRequest) *ConformanceResponse {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(req.MessageType))
+	if err != nil {
+		return &ConformanceResponse{Result: &ConformanceResponse_RuntimeError{RuntimeError: fmt.Sprintf("unknown message type %q", req.MessageType)}}
+	}
+	m := mt.New().Interface()
+	switch payload := req.Payload.(type) {
+	case *ConformanceRequest_ProtobufPayload:
+		if err := proto.Unmarshal(payload.ProtobufPayload, m); err != nil {
+			return &ConformanceResponse{Result: &ConformanceResponse_ParseError{ParseError: err.Error()}}
+		}
+	case *ConformanceRequest_JsonPayload:
+		u := nicejsonpb.New(nicejsonpb.WithProtoJSON())
+		if req.TestCategory == TestCategory_JSON_IGNORE_UNKNOWN_PARSING_TEST {
+			u = u.With(nicejsonpb.WithAllowUnknownFields())
+		}
+		if err := u.UnmarshalMessage(strings.NewReader(payload.JsonPayload), m); err != nil {
+			return &ConformanceResponse{Result: &ConformanceResponse_ParseError{ParseError: err.Error()}}
+		}
+	default:
+		return &ConformanceResponse{Result: &ConformanceResponse_Skipped{Skipped: "only protobuf and JSON input is supported"}}
+	}
+	switch req.RequestedOutputFormat {
+	case WireFormat_PROTOBUF:
+		b, err := proto.Marshal(m)
+		if err != nil {
+			return &ConformanceResponse{Result: &ConformanceResponse_SerializeError{SerializeError: err.Error()}}
+		}
+		return &ConformanceResponse{Result: &ConformanceResponse_ProtobufPayload{ProtobufPayload: b}}
+	case WireFormat_JSON:
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return &ConformanceResponse{Result: &ConformanceResponse_SerializeError{SerializeError: err.Error()}}
+		}
+		return &ConformanceResponse{Result: &ConformanceResponse_JsonPayload{JsonPayload: string(b)}}
+	}
+	return &ConformanceResponse{Result: &ConformanceResponse_Skipped{Skipped: "only protobuf and JSON output is supported"}}
+}
+
+// readRequest reads a request prefixed by its length as a little-endian uint32, as sent by the runner.
+func readRequest(r io.Reader, req *ConformanceRequest) error {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return err
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, req)
+}
+
+func writeResponse(w io.Writer, res *ConformanceResponse) error {
+	b, err := proto.Marshal(res)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
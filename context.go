@@ -0,0 +1,48 @@
+package nicejsonpb
+
+import (
+	"context"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// UnmarshalContext is Unmarshal, checking ctx regularly while decoding, so that decoding a large
+// document stops soon after ctx is done. ctx.Err() is returned in that case.
+func (u *Unmarshaler) UnmarshalContext(ctx context.Context, r io.Reader, pb proto.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := u.readDocument(r, inputValue); err != nil {
+		return err
+	}
+	d := u.newDecodeState()
+	defer d.release()
+	d.ctx = ctx
+	if err := d.unmarshalDocument(pb, *inputValue); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// UnmarshalContext is Unmarshal, checking ctx regularly while decoding.
+func UnmarshalContext(ctx context.Context, r io.Reader, pb proto.Message) error {
+	return new(Unmarshaler).UnmarshalContext(ctx, r, pb)
+}
+
+// contextCheckInterval is the number of messages, list elements and map entries decoded between two
+// checks of the context.
+const contextCheckInterval = 64
+
+// checkContext returns the error of the context of the unmarshal once that is done.
+func (d *decodeState) checkContext() error {
+	if d.ctx == nil || d.nodes%contextCheckInterval != 0 {
+		return nil
+	}
+	return d.ctx.Err()
+}
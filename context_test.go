@@ -0,0 +1,49 @@
+package nicejsonpb_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelAfter is a context that is done once its Err has been asked for n times.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalContext(context.Background(), strings.NewReader(`{"someString": "x"}`), m))
+	require.Equal(t, "x", m.SomeString)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, nicejsonpb.UnmarshalContext(ctx, strings.NewReader(`{}`), m))
+}
+
+func TestUnmarshalContext_CancelledWhileDecoding(t *testing.T) {
+	elems := make([]string, 1000)
+	for i := range elems {
+		elems[i] = fmt.Sprintf(`{"someValue": %d}`, i)
+	}
+	input := `{"someEmbeddedRep": [` + strings.Join(elems, ",") + `]}`
+	ctx := &cancelAfter{Context: context.Background(), n: 3}
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors())
+	require.Equal(t, context.Canceled, u.UnmarshalContext(ctx, strings.NewReader(input), &validatortest.Message3{}))
+
+	ctx = &cancelAfter{Context: context.Background(), n: 1000}
+	require.NoError(t, u.UnmarshalContext(ctx, strings.NewReader(input), &validatortest.Message3{}))
+}
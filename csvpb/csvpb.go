@@ -0,0 +1,156 @@
+// Package csvpb reads protocol buffers from the rows of CSV documents, as uploaded in bulk. Every
+// column sets the field named by its header, a path such as item.sku in the form taken by
+// nicejsonpb.UnmarshalQuery, and every row is decoded into a message of its own:
+//
+//	dec := csvpb.NewDecoder(r)
+//	for {
+//		m := &pb.Item{}
+//		if err := dec.Decode(m); err == io.EOF {
+//			break
+//		} else if err != nil {
+//			return err
+//		}
+//	}
+//
+// Columns that appear more than once set the elements of repeated fields, and empty cells leave
+// their field unset.
+package csvpb
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+)
+
+// Decoder reads messages from the rows of a CSV document.
+type Decoder struct {
+	// Unmarshaler holds the options the rows are decoded with, the defaults if nil.
+	Unmarshaler *nicejsonpb.Unmarshaler
+	// Columns are the field paths set by the columns of every row. If nil, they are read from the
+	// header, the first row of the document.
+	Columns []string
+
+	r   *csv.Reader
+	row int
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &Decoder{r: cr}
+}
+
+// CSVReader returns the reader of the CSV document, to set its Comma, Comment or LazyQuotes before
+// the first call to Decode.
+func (d *Decoder) CSVReader() *csv.Reader {
+	return d.r
+}
+
+// Decode decodes the next row into pb, which should be a fresh message. It returns io.EOF once the
+// document is exhausted. Errors about rows are returned as a *RowError, or a nicejsonpb.MultiError
+// of them.
+func (d *Decoder) Decode(pb proto.Message) error {
+	if d.Columns == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			return err
+		}
+		d.Columns = header
+	}
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.row, _ = d.r.FieldPos(0)
+	if len(record) > len(d.Columns) {
+		return &RowError{Row: d.row, Err: fmt.Errorf("%d cells but only %d columns", len(record), len(d.Columns))}
+	}
+	values := url.Values{}
+	for i, cell := range record {
+		if cell != "" {
+			values.Add(d.Columns[i], cell)
+		}
+	}
+	u := d.Unmarshaler
+	if u == nil {
+		u = new(nicejsonpb.Unmarshaler)
+	}
+	return d.locate(u.UnmarshalQuery(values, pb))
+}
+
+// Row returns the line of the CSV document the row last read by Decode starts on, counting from 1.
+func (d *Decoder) Row() int {
+	return d.row
+}
+
+// RowError is an error about a row of a CSV document.
+type RowError struct {
+	// Row is the line the row starts on, counting from 1.
+	Row int
+	// Column is the header of the column the error is about, empty if it is about the whole row.
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %s: %s", e.Row, e.Column, e.Err)
+}
+
+// Unwrap gives errors.Is, errors.As and the functions of nicejsonpb, such as ErrorCode and
+// ErrorPointer, access to the error of the row.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// locate wraps err, the error decoding the current row, in a RowError naming the column it is
+// about.
+func (d *Decoder) locate(err error) error {
+	if err == nil {
+		return nil
+	}
+	var multi nicejsonpb.MultiError
+	if errors.As(err, &multi) {
+		located := make(nicejsonpb.MultiError, len(multi))
+		for i, err := range multi {
+			located[i] = d.locate(err)
+		}
+		return located
+	}
+	pointer := nicejsonpb.ErrorPointer(err)
+	column := ""
+	for _, c := range d.Columns {
+		p := columnPointer(c)
+		if (pointer == p || strings.HasPrefix(pointer, p+"/")) && len(c) > len(column) {
+			column = c
+		}
+	}
+	return &RowError{Row: d.row, Column: column, Err: err}
+}
+
+// columnPointer returns the JSON Pointer of the field the column with the given header sets.
+func columnPointer(header string) string {
+	var b strings.Builder
+	for _, name := range strings.Split(header, ".") {
+		key := ""
+		if open := strings.IndexByte(name, '['); open >= 0 && strings.HasSuffix(name, "]") {
+			name, key = name[:open], name[open+1:len(name)-1]
+		}
+		b.WriteString("/" + pointerEscaper.Replace(name))
+		if key != "" {
+			b.WriteString("/" + pointerEscaper.Replace(key))
+		}
+	}
+	return b.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
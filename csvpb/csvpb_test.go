@@ -0,0 +1,54 @@
+package csvpb_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/csvpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+const upload = `name,item.sku,item.quantity,items.sku,comment
+first,A-1,2,,
+second,B-2,,,"multi
+line"
+third,C-3,many,,
+`
+
+func TestDecoder(t *testing.T) {
+	dec := csvpb.NewDecoder(strings.NewReader(upload))
+	m := &validatortest.CreateRequest{}
+	require.NoError(t, dec.Decode(m))
+	require.Equal(t, "first", m.Name)
+	require.Equal(t, "A-1", m.Item.Sku)
+	require.Equal(t, int32(2), m.Item.Quantity)
+	require.Equal(t, 2, dec.Row())
+
+	m = &validatortest.CreateRequest{}
+	require.NoError(t, dec.Decode(m))
+	require.Equal(t, "multi\nline", m.Comment)
+	require.Equal(t, int32(0), m.Item.Quantity)
+
+	err := dec.Decode(&validatortest.CreateRequest{})
+	require.EqualError(t, err, `row 5, column item.quantity: unparsable field Item.Quantity: invalid character 'm' looking for beginning of value while looking for an integer in a string`)
+	var rowErr *csvpb.RowError
+	require.ErrorAs(t, err, &rowErr)
+	require.Equal(t, 5, rowErr.Row)
+	require.Equal(t, "/item/quantity", nicejsonpb.ErrorPointer(err))
+
+	require.Equal(t, io.EOF, dec.Decode(&validatortest.CreateRequest{}))
+}
+
+func TestDecoder_Columns(t *testing.T) {
+	dec := csvpb.NewDecoder(strings.NewReader("x;A;3\ny;B;4;extra\n"))
+	dec.CSVReader().Comma = ';'
+	dec.Columns = []string{"name", "item.sku", "item.quantity"}
+	dec.Unmarshaler = nicejsonpb.New(nicejsonpb.WithAllErrors())
+	m := &validatortest.CreateRequest{}
+	require.NoError(t, dec.Decode(m))
+	require.Equal(t, int32(3), m.Item.Quantity)
+	require.EqualError(t, dec.Decode(&validatortest.CreateRequest{}), "row 2: 4 cells but only 3 columns")
+}
@@ -0,0 +1,38 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// binaryUnmarshaler is implemented by the Go types of fields generated with gogo/protobuf's
+// customtype option, which are given the wire form of the field.
+type binaryUnmarshaler interface {
+	Unmarshal(data []byte) error
+}
+
+// unmarshalCustomType decodes fields of a Go type of their own that isn't a message or an enum, such
+// as those generated with gogo/protobuf's customtype option, with the json.Unmarshaler of the type,
+// or else its Unmarshal method given the decoded form of a bytes field. It returns false for other
+// targets, which include those of gogo's casttype option, decoded by their kind.
+func (d *decodeState) unmarshalCustomType(target reflect.Value, inputValue json.RawMessage, prop *proto.Properties) (bool, error) {
+	if prop != nil && prop.Enum != "" {
+		return false, nil
+	}
+	switch target.Addr().Interface().(type) {
+	case proto.Message, protoreflect.Enum, interface{ EnumDescriptor() ([]byte, []int) }:
+		return false, nil
+	case json.Unmarshaler:
+		return true, target.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(inputValue)
+	case binaryUnmarshaler:
+		b, err := d.parseBytes(inputValue)
+		if err != nil {
+			return true, err
+		}
+		return true, target.Addr().Interface().(binaryUnmarshaler).Unmarshal(b)
+	}
+	return false, nil
+}
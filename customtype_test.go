@@ -0,0 +1,79 @@
+package nicejsonpb_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/stretchr/testify/require"
+)
+
+// customUUID is a gogo/protobuf customtype given the wire form of its bytes field.
+type customUUID [16]byte
+
+func (u *customUUID) Unmarshal(data []byte) error {
+	if len(data) != len(u) {
+		return errors.New("uuid: want 16 bytes")
+	}
+	copy(u[:], data)
+	return nil
+}
+
+func (u customUUID) Marshal() ([]byte, error) { return u[:], nil }
+func (u customUUID) Size() int                { return len(u) }
+
+// customDecimal is a gogo/protobuf customtype with a JSON form of its own, such as "12.34".
+type customDecimal struct {
+	Cents int64
+}
+
+func (c *customDecimal) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return errors.New("decimal: want a string")
+	}
+	cents, err := strconv.ParseInt(strings.Replace(s, ".", "", 1), 10, 64)
+	if err != nil {
+		return errors.New("decimal: bad number " + strconv.Quote(s))
+	}
+	c.Cents = cents
+	return nil
+}
+
+// customCount is a gogo/protobuf casttype.
+type customCount uint32
+
+type customTypeMessage struct {
+	Id     customUUID      `protobuf:"bytes,1,opt,name=id,proto3,customtype=customUUID" json:"id"`
+	Price  *customDecimal  `protobuf:"bytes,2,opt,name=price,proto3,customtype=customDecimal" json:"price,omitempty"`
+	Prices []customDecimal `protobuf:"bytes,3,rep,name=prices,proto3,customtype=customDecimal" json:"prices"`
+	Count  customCount     `protobuf:"varint,4,opt,name=count,proto3,casttype=customCount" json:"count,omitempty"`
+}
+
+func (m *customTypeMessage) Reset()         { *m = customTypeMessage{} }
+func (m *customTypeMessage) String() string { return "" }
+func (*customTypeMessage) ProtoMessage()    {}
+
+func TestUnmarshal_CustomType(t *testing.T) {
+	m := &customTypeMessage{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{
+		"id": "AAECAwQFBgcICQoLDA0ODw==",
+		"price": "12.34",
+		"prices": ["1.00", "0.50"],
+		"count": 7
+	}`, m))
+	require.Equal(t, customUUID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, m.Id)
+	require.Equal(t, int64(1234), m.Price.Cents)
+	require.Equal(t, int64(50), m.Prices[1].Cents)
+	require.Equal(t, customCount(7), m.Count)
+
+	err := nicejsonpb.UnmarshalString(`{"prices": ["1.00", "x"]}`, m)
+	require.EqualError(t, err, `unparsable field Prices.[1]: decimal: bad number "x"`)
+	require.Equal(t, "/prices/1", nicejsonpb.ErrorPointer(err))
+	err = nicejsonpb.UnmarshalString(`{"id": "AAEC"}`, m)
+	require.EqualError(t, err, "unparsable field Id: uuid: want 16 bytes")
+	err = nicejsonpb.UnmarshalString(`{"count": -1}`, m)
+	require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+}
@@ -0,0 +1,97 @@
+// Package decompress undoes the compression of documents before they are decoded, telling gzip,
+// zlib and zstd streams apart by their first bytes:
+//
+//	zr, err := decompress.NewReader(r)
+//	if err != nil {
+//		return err
+//	}
+//	defer zr.Close()
+//	err = u.Unmarshal(zr, pb)
+//
+// Unmarshal holds what it reads, the decompressed document, to MaxInputBytes, so that small
+// compressed documents can't make for huge ones. Front-ends reading other formats get the same
+// protection by reading through the LimitReader of the Unmarshaler.
+package decompress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxWindow bounds the memory a zstd stream may ask to be decompressed with.
+const maxWindow = 8 << 20
+
+// ErrUnknownEncoding is returned, wrapped, by ForEncoding for content codings it doesn't know.
+var ErrUnknownEncoding = errors.New("unknown content coding")
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// NewReader returns a reader of what r holds, decompressed if it is a gzip, zlib or zstd stream and
+// as is otherwise.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(head, zstdMagic):
+		return newZstdReader(br)
+	case isUsualZlib(head):
+		return zlib.NewReader(br)
+	}
+	return io.NopCloser(br), nil
+}
+
+// ForEncoding returns a reader of what r holds, decompressed according to the given HTTP content
+// coding: gzip, deflate, zstd or identity. Deflate bodies are taken both as zlib streams, which the
+// coding stands for, and as raw deflate streams, which some clients send instead.
+func ForEncoding(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return io.NopCloser(r), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "zstd":
+		return newZstdReader(r)
+	case "deflate":
+		br := bufio.NewReader(r)
+		if head, _ := br.Peek(2); isZlib(head) {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	}
+	return nil, fmt.Errorf("%w %q, expected gzip, deflate or zstd", ErrUnknownEncoding, encoding)
+}
+
+// isZlib reports whether head starts with a zlib header for a deflate stream with no preset
+// dictionary.
+func isZlib(head []byte) bool {
+	return len(head) >= 2 && head[0]&0x0f == 8 && head[0]>>4 <= 7 && head[1]&0x20 == 0 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0
+}
+
+// isUsualZlib reports whether head starts with one of the zlib headers that compressors write, with
+// a 32KB window and no preset dictionary. The header checksum alone lets through one in 31 pairs of
+// bytes, including the starts of plain documents such as "x = 1".
+func isUsualZlib(head []byte) bool {
+	return len(head) >= 2 && head[0] == 0x78 && bytes.IndexByte([]byte{0x01, 0x5e, 0x9c, 0xda}, head[1]) >= 0
+}
+
+func newZstdReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true), zstd.WithDecoderMaxWindow(maxWindow))
+	if err != nil {
+		return nil, err
+	}
+	return zr.IOReadCloser(), nil
+}
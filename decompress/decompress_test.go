@@ -0,0 +1,95 @@
+package decompress_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/decompress"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func compress(t *testing.T, format string, s string) []byte {
+	var b bytes.Buffer
+	var w io.WriteCloser
+	switch format {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "zlib":
+		w = zlib.NewWriter(&b)
+	case "deflate":
+		w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+	case "zstd":
+		var err error
+		w, err = zstd.NewWriter(&b)
+		require.NoError(t, err)
+	}
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func TestNewReader(t *testing.T) {
+	doc := `{"someString": "unpacked"}`
+	inputs := map[string][]byte{"plain": []byte(doc)}
+	for _, format := range []string{"gzip", "zlib", "zstd"} {
+		inputs[format] = compress(t, format, doc)
+	}
+	for name, input := range inputs {
+		zr, err := decompress.NewReader(bytes.NewReader(input))
+		require.NoError(t, err, name)
+		m := &validatortest.Message3{}
+		require.NoError(t, nicejsonpb.Unmarshal(zr, m), name)
+		require.NoError(t, zr.Close(), name)
+		require.Equal(t, "unpacked", m.SomeString, name)
+	}
+
+	// Plain documents that pass the checksum of zlib headers.
+	for _, plain := range []string{"x = 1", "80"} {
+		zr, err := decompress.NewReader(strings.NewReader(plain))
+		require.NoError(t, err, plain)
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err, plain)
+		require.Equal(t, plain, string(b))
+	}
+}
+
+func TestForEncoding(t *testing.T) {
+	doc := "some text"
+	for encoding, input := range map[string][]byte{
+		"identity":  []byte(doc),
+		"gzip":      compress(t, "gzip", doc),
+		"deflate":   compress(t, "zlib", doc),
+		" Deflate ": compress(t, "deflate", doc),
+		"zstd":      compress(t, "zstd", doc),
+	} {
+		zr, err := decompress.ForEncoding(encoding, bytes.NewReader(input))
+		require.NoError(t, err, encoding)
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err, encoding)
+		require.Equal(t, doc, string(b), encoding)
+	}
+	_, err := decompress.ForEncoding("br", strings.NewReader(""))
+	require.ErrorIs(t, err, decompress.ErrUnknownEncoding)
+	require.EqualError(t, err, `unknown content coding "br", expected gzip, deflate or zstd`)
+}
+
+func TestNewReader_MaxInputBytes(t *testing.T) {
+	bomb := `{"someString": "` + strings.Repeat("a", 1<<20) + `"}`
+	u := nicejsonpb.New(nicejsonpb.WithMaxInputBytes(1 << 10))
+	for _, format := range []string{"gzip", "zlib", "zstd"} {
+		input := compress(t, format, bomb)
+		require.Less(t, len(input), 1<<12, format)
+		zr, err := decompress.NewReader(bytes.NewReader(input))
+		require.NoError(t, err, format)
+		require.ErrorIs(t, u.Unmarshal(zr, &validatortest.Message3{}), nicejsonpb.ErrInputTooLarge, format)
+	}
+}
@@ -0,0 +1,34 @@
+package nicejsonpb
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// applyDefaults sets all unset fields of m, and of the messages nested in it, that have a declared
+// default to that default.
+func applyDefaults(m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			if fd.HasDefault() {
+				m.Set(fd, fd.Default())
+			}
+			continue
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := m.Get(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				applyDefaults(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			m.Get(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				applyDefaults(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			applyDefaults(m.Get(fd).Message())
+		}
+	}
+}
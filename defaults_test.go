@@ -0,0 +1,26 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_ApplyDefaults(t *testing.T) {
+	input := `{"someDefaultInt": 7, "someEmbedded": {"identifier": "x"}}`
+	stuff := &validatortest.Message2{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Nil(t, stuff.SomeDefaultString)
+	require.Nil(t, stuff.SomeEmbedded.Enabled)
+
+	u := &nicejsonpb.Unmarshaler{ApplyDefaults: true}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, proto.String("hello"), stuff.SomeDefaultString)
+	require.Equal(t, proto.Int32(7), stuff.SomeDefaultInt)
+	require.Equal(t, proto.Bool(true), stuff.SomeEmbedded.Enabled)
+	require.Nil(t, stuff.SomeString)
+}
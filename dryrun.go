@@ -0,0 +1,20 @@
+package nicejsonpb
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Validate decodes the JSON from r the way Unmarshal would decode it into pb, reporting all the
+// field errors found as with AllErrors, but without changing pb: the decoding is done on a copy of
+// it. This suits pre-flight checks of requests that are only applied later.
+func (u *Unmarshaler) Validate(r io.Reader, pb proto.Message) error {
+	return u.With(WithAllErrors()).Unmarshal(r, proto.Clone(pb))
+}
+
+// Validate decodes the JSON from r the way Unmarshal would decode it into pb, reporting all the
+// field errors found without changing pb.
+func Validate(r io.Reader, pb proto.Message) error {
+	return new(Unmarshaler).Validate(r, pb)
+}
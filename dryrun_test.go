@@ -0,0 +1,33 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_LeavesTargetUnchanged(t *testing.T) {
+	m := existingMessage3()
+	want := proto.Clone(m)
+	err := nicejsonpb.Validate(strings.NewReader(`{"someString": "new", "someInt32": "x", "someEmbedded": {"someValue": true}}`), m)
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 2)
+	require.Contains(t, multi[0].Error(), "unparsable field SomeInt32:")
+	require.Contains(t, multi[1].Error(), "unparsable field SomeEmbedded.SomeValue:")
+	require.True(t, proto.Equal(want, m))
+
+	require.NoError(t, nicejsonpb.Validate(strings.NewReader(`{"someString": "new"}`), m))
+	require.True(t, proto.Equal(want, m))
+}
+
+func TestValidate_UsesOptions(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{CheckRequiredFields: true}
+	err := u.Validate(strings.NewReader(`{"items": [{}]}`), &validatortest.Required2{})
+	require.EqualError(t, err, "unparsable field Name: required field is missing; unparsable field Items.[0].Id: required field is missing")
+	require.False(t, u.AllErrors)
+}
@@ -0,0 +1,374 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// LoadDescriptorSet parses a serialized google.protobuf.FileDescriptorSet, as written by
+// `protoc --include_imports --descriptor_set_out`, so that the messages it describes can be decoded
+// with UnmarshalDynamic without any generated Go code.
+func LoadDescriptorSet(b []byte) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, fmt.Errorf("bad FileDescriptorSet: %v", err)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// UnmarshalDynamic unmarshals a JSON object stream into a new dynamic message of the given type.
+func (u *Unmarshaler) UnmarshalDynamic(r io.Reader, md protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	m := dynamicpb.NewMessage(md)
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := u.readDocument(r, inputValue); err != nil {
+		return nil, err
+	}
+	if err := u.unmarshalDynamicDocument(m, *inputValue); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UnmarshalDynamic unmarshals a JSON object stream into a new dynamic message of the given type.
+func UnmarshalDynamic(r io.Reader, md protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	return new(Unmarshaler).UnmarshalDynamic(r, md)
+}
+
+// unmarshalDynamicDocument is the counterpart of unmarshalDocument for dynamic messages.
+func (d *decodeState) unmarshalDynamicDocument(m protoreflect.Message, inputValue json.RawMessage) error {
+	if d.Observer != nil && d.observerCtx == nil {
+		return d.observe(m.Descriptor().FullName(), inputValue, func() error {
+			return d.unmarshalDynamicDocument(m, inputValue)
+		})
+	}
+	if d.ProtoJSON {
+		return d.formatErrors(d.unmarshalProtoJSON(m, inputValue))
+	}
+	if d.rejectNullDocument && isNull(inputValue) {
+		return d.formatErrors(errNullDocument)
+	}
+	if !d.MergeInto {
+		protov2.Reset(m.Interface())
+	}
+	if err := d.collect(d.unmarshalDynamic(m, inputValue)); err != nil {
+		return d.formatErrors(err)
+	}
+	return d.formatErrors(d.finishDocument(m))
+}
+
+// unmarshalDynamic is the descriptor-driven counterpart of unmarshalValue, for messages that aren't
+// backed by a generated Go struct. Field errors are reported with the Go names the fields would have
+// in generated code, so both paths produce the same messages.
+func (d *decodeState) unmarshalDynamic(m protoreflect.Message, inputValue json.RawMessage) error {
+	if handler, ok := wellKnownHandlers[m.Descriptor().FullName()]; ok {
+		return handler(d, inputValue, m)
+	}
+	return d.unmarshalDynamicFields(m, inputValue)
+}
+
+// unmarshalDynamicFields decodes the JSON object inputValue into m field by field, whatever JSON
+// form of its own m has.
+func (d *decodeState) unmarshalDynamicFields(m protoreflect.Message, inputValue json.RawMessage) error {
+	md := m.Descriptor()
+	if err := d.enterMessage(); err != nil {
+		return err
+	}
+	defer d.leaveMessage()
+	jsonFields := getFieldMap()
+	defer putFieldMap(jsonFields)
+	if err := d.tokens().splitObject(inputValue, jsonFields); err != nil {
+		return correctDynamicJsonType(err, "message "+string(md.FullName()))
+	}
+	fields := md.Fields()
+	oneofsSet := map[int]string{}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		raw, key, ok, err := d.consumeDynamicField(jsonFields, fd)
+		if err != nil {
+			if err := d.collect(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !ok {
+			d.dynamicFieldMissing(fd, goCamelCase(string(fd.Name())))
+			continue
+		}
+		if settable, err := d.dynamicFieldSettable(m, fd, key); !settable {
+			if err := d.collect(err); err != nil {
+				return err
+			}
+			continue
+		}
+		d.recordSetField(string(fd.Name()))
+		d.warnIfDeprecatedField(fd, goCamelCase(string(fd.Name())))
+		if isNull(raw) && !dynamicAcceptsNull(fd) {
+			d.dynamicFieldMissing(fd, goCamelCase(string(fd.Name())))
+			if isProto3Optional(fd) {
+				m.Clear(fd)
+				continue
+			}
+			if err := d.collect(d.unmarshalDynamicNullField(goCamelCase(string(fd.Name())), key, m, fd)); err != nil {
+				return err
+			}
+			continue
+		}
+		if oo := fd.ContainingOneof(); oo != nil && !oo.IsSynthetic() {
+			if err := d.checkOneofConflict(oneofsSet, oo.Index(), string(fd.Name()), string(oo.Name())); err != nil {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		err = d.redactingDynamicField(fd, func() error {
+			return d.withinField(goCamelCase(string(fd.Name())), string(fd.Name()), key, raw, func() error {
+				return d.unmarshalDynamicField(m, fd, raw)
+			})
+		})
+		if err := d.collect(err); err != nil {
+			return err
+		}
+	}
+	return d.checkUnknownFields(jsonFields, func() error {
+		return getDynamicFieldMismatchError(jsonFields, md)
+	})
+}
+
+// consumeDynamicField removes the value of fd from jsonFields, accepting both the JSON name and the
+// original proto name. If both are present, the JSON name wins, unless names may not be repeated.
+func (d *decodeState) consumeDynamicField(jsonFields map[string]json.RawMessage, fd protoreflect.FieldDescriptor) (json.RawMessage, string, bool, error) {
+	if !d.fieldAllowed(string(fd.Name())) {
+		return nil, "", false, nil
+	}
+	jsonName := dynamicJSONName(fd)
+	vOrig, okOrig := jsonFields[string(fd.Name())]
+	vCamel, okCamel := jsonFields[jsonName]
+	if !okOrig && !okCamel {
+		return nil, "", false, nil
+	}
+	if okOrig && okCamel && string(fd.Name()) != jsonName {
+		if d.rejectDuplicateKeys {
+			delete(jsonFields, string(fd.Name()))
+			delete(jsonFields, jsonName)
+			return nil, "", false, fieldErrorAt(goCamelCase(string(fd.Name())), jsonName, errDuplicateName(string(fd.Name()), jsonName))
+		}
+		d.warn(WarnDuplicateName, goCamelCase(string(fd.Name())), "both %q and %q are set, using %q", fd.Name(), jsonName, jsonName)
+	}
+	var raw json.RawMessage
+	var key string
+	if okOrig {
+		raw, key = vOrig, string(fd.Name())
+		delete(jsonFields, string(fd.Name()))
+	}
+	if okCamel {
+		raw, key = vCamel, jsonName
+		delete(jsonFields, jsonName)
+	}
+	return raw, key, true, nil
+}
+
+func (d *decodeState) unmarshalDynamicField(m protoreflect.Message, fd protoreflect.FieldDescriptor, raw json.RawMessage) error {
+	// Lists, maps and messages are mutated in place, so start them afresh unless merging.
+	if !d.MergeInto || (fd.IsList() && !d.AppendRepeated) {
+		m.Clear(fd)
+	}
+	switch {
+	case fd.IsList():
+		slc, err := d.splitRepeated(raw)
+		if err != nil {
+			return correctDynamicJsonType(err, "repeated field")
+		}
+		list := m.Mutable(fd).List()
+		for i, elem := range slc {
+			if err := d.step(); err != nil {
+				return err
+			}
+			if isNull(elem) && !dynamicAcceptsNull(fd) {
+				if d.SkipNullElements {
+					continue
+				}
+				if err := d.collect(d.withRaw(FieldError(fmt.Sprintf("[%d]", i), errNullElement), jsonNull)); err != nil {
+					return err
+				}
+				continue
+			}
+			err := d.withinElement(fmt.Sprintf("[%d]", i), strconv.Itoa(i), elem, func() error {
+				v, err := d.dynamicValue(fd, list.NewElement(), elem)
+				if err == nil {
+					list.Append(v)
+				}
+				return err
+			})
+			if err := d.collect(err); err != nil {
+				return err
+			}
+		}
+		return nil
+	case fd.IsMap():
+		mp := map[string]json.RawMessage{}
+		if err := d.tokens().splitObject(raw, mp); err != nil {
+			return correctDynamicJsonType(err, "map field")
+		}
+		mapValue := m.Mutable(fd).Map()
+		for _, ks := range sortedKeys(mp) {
+			if err := d.step(); err != nil {
+				return err
+			}
+			elem := mp[ks]
+			k, err := d.dynamicMapKey(fd, ks)
+			if err != nil {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
+			}
+			if isNull(elem) && !dynamicAcceptsNull(fd.MapValue()) && d.NullHandling != NullAsDefault {
+				if err := d.collect(d.withRaw(fieldErrorAt(fmt.Sprintf("['%s']value", ks), ks, errNullMapValue), jsonNull)); err != nil {
+					return err
+				}
+				continue
+			}
+			err = d.withinElement(fmt.Sprintf("['%s']value", ks), ks, elem, func() error {
+				v, err := d.dynamicValue(fd.MapValue(), mapValue.NewValue(), elem)
+				if err == nil {
+					mapValue.Set(k, v)
+				}
+				return err
+			})
+			if err := d.collect(err); err != nil {
+				return err
+			}
+		}
+		return nil
+	case fd.Message() != nil:
+		return d.unmarshalDynamic(m.Mutable(fd).Message(), raw)
+	}
+	v, err := d.dynamicValue(fd, protoreflect.Value{}, raw)
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// dynamicMapKey parses the JSON object key of an entry of the map field fd.
+func (d *decodeState) dynamicMapKey(fd protoreflect.FieldDescriptor, ks string) (protoreflect.MapKey, error) {
+	k, ok := parseMapKey(fd.MapKey().Kind(), ks)
+	if !ok {
+		return protoreflect.MapKey{}, d.badMapKeyError(ks, fd.MapKey().Kind().String(), dynamicProtoTypeName(fd.MapValue()))
+	}
+	return k.MapKey(), nil
+}
+
+// dynamicValue decodes a single (list element or map value) value of fd. For message values, empty
+// must be a new mutable value to decode into.
+func (d *decodeState) dynamicValue(fd protoreflect.FieldDescriptor, empty protoreflect.Value, raw json.RawMessage) (protoreflect.Value, error) {
+	if fd.Message() != nil {
+		return empty, d.unmarshalDynamic(empty.Message(), raw)
+	}
+	if d.coercesScalars() {
+		var err error
+		if raw, err = d.coerce(fd.Kind(), raw); err != nil {
+			return protoreflect.Value{}, err
+		}
+	}
+	if fd.Enum() != nil {
+		return d.dynamicEnum(fd, raw)
+	}
+	return d.dynamicScalar(fd, raw)
+}
+
+// dynamicScalar decodes the value of a non-message, non-enum field, following the same rules as the
+// struct path.
+func (d *decodeState) dynamicScalar(fd protoreflect.FieldDescriptor, raw json.RawMessage) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		v, err := d.parseBool(raw)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := d.parseInt(raw, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := d.parseUint(raw, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := d.parseInt(raw, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := d.parseUint(raw, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := d.parseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := d.parseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.StringKind:
+		v, err := d.parseString(raw)
+		return protoreflect.ValueOfString(v), err
+	case protoreflect.BytesKind:
+		v, err := d.parseBytes(raw)
+		return protoreflect.ValueOfBytes(v), err
+	}
+	return protoreflect.Value{}, codeErrorf(CodeUnsupported, "unsupported field kind %v", fd.Kind())
+}
+
+// correctDynamicJsonType is the counterpart of correctJsonType for values with no Go type.
+func correctDynamicJsonType(err error, what string) error {
+	if uErr, ok := err.(*json.UnmarshalTypeError); ok {
+		return codeErrorf(CodeTypeMismatch, "json: cannot unmarshal %s into %s", uErr.Value, what)
+	}
+	return err
+}
+
+func getDynamicFieldMismatchError(remainingFields map[string]json.RawMessage, md protoreflect.MessageDescriptor) error {
+	known := map[string]bool{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		known[fieldNames{orig: string(fields.Get(i).Name()), camel: dynamicJSONName(fields.Get(i))}.String()] = true
+	}
+	return fieldMismatchError(remainingFields, known)
+}
+
+// goCamelCase returns the name protoc-gen-go gives to the Go field of a proto field.
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool { return 'a' <= c && c <= 'z' }
+
+func isASCIIDigit(c byte) bool { return '0' <= c && c <= '9' }
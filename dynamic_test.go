@@ -0,0 +1,85 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// message3Descriptor returns the descriptor of validatortest.Message3 as loaded from a descriptor set,
+// with no link to the generated Go code.
+func message3Descriptor(t *testing.T) protoreflect.MessageDescriptor {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range []protoreflect.FileDescriptor{
+		durationpb.File_google_protobuf_duration_proto,
+		timestamppb.File_google_protobuf_timestamp_proto,
+		wrapperspb.File_google_protobuf_wrappers_proto,
+		validatortest.File_nicejsonpb_proto3_proto,
+	} {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	b, err := proto.Marshal(set)
+	require.NoError(t, err)
+	files, err := nicejsonpb.LoadDescriptorSet(b)
+	require.NoError(t, err)
+	desc, err := files.FindDescriptorByName("validatortest.Message3")
+	require.NoError(t, err)
+	return desc.(protoreflect.MessageDescriptor)
+}
+
+func TestUnmarshalDynamic_DecodesWithoutGeneratedCode(t *testing.T) {
+	input := `{
+		"someString": "foo",
+		"some_int64": "12",
+		"someStatus": "STATUS_ACTIVE",
+		"someIntRep": [1, 2],
+		"someEmbedded": {"identifier": "bar", "children": [{"someValue": 3}]},
+		"someStringToStatus": {"a": "STATUS_DISABLED"},
+		"someInt32ToString": {"7": "seven"},
+		"someDuration": "1.5s",
+		"someWrappedInt": "9",
+		"email": "foo@example.com"
+	}`
+	m, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+	stuff := &validatortest.Message3{}
+	require.NoError(t, proto.Unmarshal(b, stuff))
+	require.Equal(t, "foo", stuff.SomeString)
+	require.Equal(t, int64(12), stuff.SomeInt64)
+	require.Equal(t, validatortest.Status_STATUS_ACTIVE, stuff.SomeStatus)
+	require.Equal(t, []uint32{1, 2}, stuff.SomeIntRep)
+	require.Equal(t, "bar", stuff.SomeEmbedded.Identifier)
+	require.Equal(t, int64(3), stuff.SomeEmbedded.Children[0].SomeValue)
+	require.Equal(t, validatortest.Status_STATUS_DISABLED, stuff.SomeStringToStatus["a"])
+	require.Equal(t, "seven", stuff.SomeInt32ToString[7])
+	require.Equal(t, int64(1), stuff.SomeDuration.Seconds)
+	require.Equal(t, int32(5e8), stuff.SomeDuration.Nanos)
+	require.Equal(t, int64(9), stuff.SomeWrappedInt.Value)
+	require.Equal(t, "foo@example.com", stuff.GetEmail())
+}
+
+func TestUnmarshalDynamic_FindsErrorsInNested(t *testing.T) {
+	input := `{"someEmbedded": {"children": [{}, {"identifier": 3.1}]}}`
+	_, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Children.[1].Identifier: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshalMessage_FallsBackToDescriptorsForDynamicMessages(t *testing.T) {
+	stuff := dynamicpb.NewMessage(message3Descriptor(t))
+	err := nicejsonpb.UnmarshalMessage(strings.NewReader(`{"someStatus": "STATUS_NOPE"}`), stuff)
+	require.EqualError(t, err, `unparsable field SomeStatus: unknown value '"STATUS_NOPE"' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]`)
+}
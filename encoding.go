@@ -0,0 +1,134 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// InputEncoding selects how the bytes of a document are turned into text.
+type InputEncoding int
+
+const (
+	// EncodingAuto detects UTF-16 and UTF-32 documents, with or without a byte order mark, and
+	// transcodes them to UTF-8, which is also stripped of its byte order mark. This is the default.
+	EncodingAuto InputEncoding = iota
+	// EncodingUTF8 takes documents as UTF-8 as they are, so that any byte order mark fails the
+	// unmarshal as it does with encoding/json.
+	EncodingUTF8
+)
+
+// textEncoding is an encoding detected by detectEncoding.
+type textEncoding int
+
+const (
+	textUTF8 textEncoding = iota
+	textUTF16BE
+	textUTF16LE
+	textUTF32BE
+	textUTF32LE
+)
+
+// detectEncoding returns the encoding of the document starting with head, of up to 4 bytes, and the
+// length of its byte order mark. Documents without one are recognized by the zero bytes around
+// their first character, which is always ASCII (RFC 4627, section 3).
+func detectEncoding(head []byte) (textEncoding, int) {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}):
+		return textUTF8, 3
+	case bytes.HasPrefix(head, []byte{0x00, 0x00, 0xFE, 0xFF}):
+		return textUTF32BE, 4
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE, 0x00, 0x00}):
+		return textUTF32LE, 4
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		return textUTF16BE, 2
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		return textUTF16LE, 2
+	}
+	if len(head) < 4 {
+		return textUTF8, 0
+	}
+	switch {
+	case head[0] == 0 && head[1] == 0 && head[2] == 0 && head[3] != 0:
+		return textUTF32BE, 0
+	case head[0] != 0 && head[1] == 0 && head[2] == 0 && head[3] == 0:
+		return textUTF32LE, 0
+	case head[0] == 0 && head[1] != 0 && head[2] == 0 && head[3] != 0:
+		return textUTF16BE, 0
+	case head[0] != 0 && head[1] == 0 && head[2] != 0 && head[3] == 0:
+		return textUTF16LE, 0
+	}
+	return textUTF8, 0
+}
+
+// transcode returns b, a document without its byte order mark, in UTF-8. Invalid code units are
+// replaced with U+FFFD.
+func transcode(b []byte, enc textEncoding) ([]byte, error) {
+	var order binary.ByteOrder = binary.BigEndian
+	if enc == textUTF16LE || enc == textUTF32LE {
+		order = binary.LittleEndian
+	}
+	out := make([]byte, 0, len(b))
+	switch enc {
+	case textUTF16BE, textUTF16LE:
+		if len(b)%2 != 0 {
+			return nil, codeErrorf(CodeSyntax, "UTF-16 document of odd length %d", len(b))
+		}
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = order.Uint16(b[2*i:])
+		}
+		for _, r := range utf16.Decode(units) {
+			out = utf8.AppendRune(out, r)
+		}
+	case textUTF32BE, textUTF32LE:
+		if len(b)%4 != 0 {
+			return nil, codeErrorf(CodeSyntax, "UTF-32 document of length %d, not a multiple of 4", len(b))
+		}
+		for i := 0; i < len(b); i += 4 {
+			r := rune(order.Uint32(b[i:]))
+			if !utf8.ValidRune(r) {
+				r = utf8.RuneError
+			}
+			out = utf8.AppendRune(out, r)
+		}
+	default:
+		return b, nil
+	}
+	return out, nil
+}
+
+// textReader returns a reader giving the document held in r in UTF-8, as EncodingAuto does.
+// Documents in UTF-8 are read as they go, others are read whole to be transcoded.
+func textReader(r io.Reader) (io.Reader, error) {
+	var head [4]byte
+	n, err := io.ReadFull(r, head[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	enc, bom := detectEncoding(head[:n])
+	if enc == textUTF8 {
+		return io.MultiReader(bytes.NewReader(head[bom:n]), r), nil
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b, err := transcode(append(head[bom:n:n], rest...), enc)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// textBytes is textReader for a document held in memory.
+func textBytes(b []byte) ([]byte, error) {
+	head := b
+	if len(head) > 4 {
+		head = head[:4]
+	}
+	enc, bom := detectEncoding(head)
+	return transcode(b[bom:], enc)
+}
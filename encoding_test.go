@@ -0,0 +1,68 @@
+package nicejsonpb_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+const encodedInput = `{"someString": "grüße 😀", "someInt32": 3}`
+
+func utf16Bytes(s string, order binary.AppendByteOrder) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = order.AppendUint16(b, u)
+	}
+	return b
+}
+
+func utf32Bytes(s string, order binary.AppendByteOrder) []byte {
+	var b []byte
+	for _, r := range s {
+		b = order.AppendUint32(b, uint32(r))
+	}
+	return b
+}
+
+func TestUnmarshal_InputEncodings(t *testing.T) {
+	for name, input := range map[string][]byte{
+		"UTF-8 BOM":      append([]byte{0xEF, 0xBB, 0xBF}, encodedInput...),
+		"UTF-16BE BOM":   append([]byte{0xFE, 0xFF}, utf16Bytes(encodedInput, binary.BigEndian)...),
+		"UTF-16LE BOM":   append([]byte{0xFF, 0xFE}, utf16Bytes(encodedInput, binary.LittleEndian)...),
+		"UTF-16LE":       utf16Bytes(encodedInput, binary.LittleEndian),
+		"UTF-16BE":       utf16Bytes(encodedInput, binary.BigEndian),
+		"UTF-32LE BOM":   append([]byte{0xFF, 0xFE, 0x00, 0x00}, utf32Bytes(encodedInput, binary.LittleEndian)...),
+		"UTF-32BE":       utf32Bytes(encodedInput, binary.BigEndian),
+		"UTF-8 no BOM":   []byte(encodedInput),
+		"short document": []byte(`{}`),
+	} {
+		m := &validatortest.Message3{}
+		require.NoError(t, nicejsonpb.Unmarshal(bytes.NewReader(input), m), name)
+		if name != "short document" {
+			require.Equal(t, "grüße 😀", m.SomeString, name)
+			require.Equal(t, int32(3), m.SomeInt32, name)
+		}
+
+		m = &validatortest.Message3{}
+		require.NoError(t, nicejsonpb.UnmarshalBytes(input, m), name)
+		if name != "short document" {
+			require.Equal(t, "grüße 😀", m.SomeString, name)
+		}
+	}
+}
+
+func TestUnmarshal_InputEncodingUTF8(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithInputEncoding(nicejsonpb.EncodingUTF8))
+	input := append([]byte{0xEF, 0xBB, 0xBF}, encodedInput...)
+	err := u.Unmarshal(bytes.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, `invalid character '\ufeff' looking for beginning of value`)
+
+	err = nicejsonpb.Unmarshal(strings.NewReader("\xFF\xFE{"), &validatortest.Message3{})
+	require.EqualError(t, err, "UTF-16 document of odd length 1")
+}
@@ -0,0 +1,91 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// unknownEnumName deals with a JSON string that isn't the name of any value of the enum called
+// enumName, whose values are given by vmap. With LenientEnumNames it may still match one loosely, with
+// IgnoreUnknownEnumValues it decodes as the zero value, otherwise it fails listing the valid names.
+func (d *decodeState) unknownEnumName(s string, enumName string, vmap map[string]int32) (int32, error) {
+	if d.LenientEnumNames {
+		if n, ok := matchEnumName(s, enumName, vmap); ok {
+			return n, nil
+		}
+	}
+	if d.IgnoreUnknownEnumValues {
+		d.warn(WarnUnknownEnumValue, "", "unknown value %s for enum %s ignored", d.echo("%q", "string", s), enumName)
+		return 0, nil
+	}
+	names := make([]string, 0, len(vmap))
+	for name := range vmap {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if vmap[names[i]] != vmap[names[j]] {
+			return vmap[names[i]] < vmap[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return 0, codeErrorf(CodeEnumUnknownValue, "unknown value '%s' for enum %s, expected one of %v", d.echo("%q", "string", s), enumName, names)
+}
+
+// matchEnumName looks s up in vmap ignoring case, and allowing the prefix derived from the enum type
+// name to be left out, so that "active" matches STATUS_ACTIVE of enum Status. It only matches if
+// exactly one value fits.
+func matchEnumName(s string, enumName string, vmap map[string]int32) (int32, bool) {
+	typeName := enumName[strings.LastIndex(enumName, ".")+1:]
+	typeName = typeName[strings.LastIndex(typeName, "_")+1:]
+	prefix := upperSnakeCase(typeName) + "_"
+	var match int32
+	matches := 0
+	for name, n := range vmap {
+		if strings.EqualFold(name, s) || strings.EqualFold(name, prefix+s) {
+			match = n
+			matches++
+		}
+	}
+	return match, matches == 1
+}
+
+// upperSnakeCase converts a CamelCase name to UPPER_SNAKE_CASE, the convention for enum value
+// prefixes.
+func upperSnakeCase(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if i > 0 && 'A' <= c && c <= 'Z' && !('A' <= s[i-1] && s[i-1] <= 'Z') {
+			b.WriteByte('_')
+		}
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// dynamicEnum decodes the value of the enum field fd, given either as a name or as a number.
+func (d *decodeState) dynamicEnum(fd protoreflect.FieldDescriptor, raw json.RawMessage) (protoreflect.Value, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		s := string(raw[1 : len(raw)-1])
+		ev := fd.Enum().Values().ByName(protoreflect.Name(s))
+		if ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		values := fd.Enum().Values()
+		vmap := make(map[string]int32, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			vmap[string(values.Get(i).Name())] = int32(values.Get(i).Number())
+		}
+		n, err := d.unknownEnumName(s, string(fd.Enum().FullName()), vmap)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+	}
+	var n int32
+	err := json.Unmarshal(raw, &n)
+	return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+}
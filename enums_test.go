@@ -0,0 +1,49 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_UnknownEnumValueListsValidNames(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someStatus": "STATUS_NOPE"}`, &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeStatus: unknown value '"STATUS_NOPE"' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]`)
+}
+
+func TestUnmarshal_IgnoreUnknownEnumValues(t *testing.T) {
+	warnings := []string{}
+	u := &nicejsonpb.Unmarshaler{
+		IgnoreUnknownEnumValues: true,
+		OnWarning: func(w nicejsonpb.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	}
+	input := `{"someStatus": "STATUS_NEW", "someStatusRep": ["STATUS_ACTIVE", "STATUS_NEW", 7]}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, validatortest.Status_STATUS_UNKNOWN, stuff.SomeStatus)
+	require.Equal(t, []validatortest.Status{validatortest.Status_STATUS_ACTIVE, validatortest.Status_STATUS_UNKNOWN, 7}, stuff.SomeStatusRep)
+	require.Equal(t, []string{
+		`field SomeStatus: unknown value "STATUS_NEW" for enum validatortest.Status ignored`,
+		`field SomeStatusRep.[1]: unknown value "STATUS_NEW" for enum validatortest.Status ignored`,
+	}, warnings)
+}
+
+func TestUnmarshal_LenientEnumNames(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{LenientEnumNames: true}
+	for _, name := range []string{"active", "ACTIVE", "Status_Active", "STATUS_ACTIVE"} {
+		stuff := &validatortest.Message3{}
+		require.NoError(t, u.Unmarshal(strings.NewReader(`{"someStatus": "`+name+`"}`), stuff), name)
+		require.Equal(t, validatortest.Status_STATUS_ACTIVE, stuff.SomeStatus, name)
+
+		msg, err := u.UnmarshalDynamic(strings.NewReader(`{"someStatus": "`+name+`"}`), message3Descriptor(t))
+		require.NoError(t, err, name)
+		require.EqualValues(t, validatortest.Status_STATUS_ACTIVE, msg.Get(msg.Descriptor().Fields().ByName("some_status")).Enum(), name)
+	}
+	err := u.Unmarshal(strings.NewReader(`{"someStatus": "activ"}`), &validatortest.Message3{})
+	require.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// EnvelopeDecoder decodes envelopes, JSON objects that name the type of the message they carry in
+// one field and hold it in another, as event buses send them:
+//
+//	{"type": "order.created", "payload": {"orderId": "42"}}
+//
+// Types are registered under the names envelopes give them. The other fields of envelopes are
+// ignored.
+type EnvelopeDecoder struct {
+	// TypeField is the key of the field naming the type of the message, "type" if empty.
+	TypeField string
+	// PayloadField is the key of the field holding the message, "payload" if empty. Envelopes
+	// without it carry an empty message.
+	PayloadField string
+	// ResolveTypes looks up the names that aren't registered as full names of message types, with
+	// the TypeResolver of the Unmarshaler.
+	ResolveTypes bool
+
+	u     *Unmarshaler
+	types map[string]func() proto.Message
+}
+
+// NewEnvelopeDecoder returns an EnvelopeDecoder decoding messages with the options of u.
+func (u *Unmarshaler) NewEnvelopeDecoder() *EnvelopeDecoder {
+	return &EnvelopeDecoder{u: u, types: map[string]func() proto.Message{}}
+}
+
+// NewEnvelopeDecoder returns an EnvelopeDecoder with the default options.
+func NewEnvelopeDecoder() *EnvelopeDecoder {
+	return new(Unmarshaler).NewEnvelopeDecoder()
+}
+
+// Register makes envelopes naming the type name carry messages obtained from factory.
+func (e *EnvelopeDecoder) Register(name string, factory func() proto.Message) {
+	e.types[name] = factory
+}
+
+// Decode decodes the envelope held in data, returning the message it carries and the name of its
+// type. Errors about the message are field errors of the payload field, so that their paths and
+// pointers locate them in the envelope.
+func (e *EnvelopeDecoder) Decode(data []byte) (proto.Message, string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, "", err
+	}
+	typeField, payloadField := e.fieldNames()
+	rawName, ok := fields[typeField]
+	if !ok {
+		return nil, "", codeErrorf(CodeRequiredFieldMissing, "envelope has no %q field", typeField)
+	}
+	var name string
+	if err := json.Unmarshal(rawName, &name); err != nil {
+		return nil, "", fieldErrorAt(typeField, typeField, codeErrorf(CodeTypeMismatch, "type name must be a string, got %s", rawName))
+	}
+	payload, ok := fields[payloadField]
+	if !ok || isNull(payload) {
+		payload = json.RawMessage("{}")
+	}
+	pb, err := e.decodePayload(name, payload)
+	if err != nil {
+		return nil, name, e.locate(typeField, payloadField, err)
+	}
+	return pb, name, nil
+}
+
+// DecodeNext decodes the next envelope of a stream of JSON documents, returning io.EOF at its end.
+func (e *EnvelopeDecoder) DecodeNext(dec *json.Decoder) (proto.Message, string, error) {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return nil, "", err
+	}
+	return e.Decode(raw)
+}
+
+func (e *EnvelopeDecoder) fieldNames() (string, string) {
+	typeField, payloadField := e.TypeField, e.PayloadField
+	if typeField == "" {
+		typeField = "type"
+	}
+	if payloadField == "" {
+		payloadField = "payload"
+	}
+	return typeField, payloadField
+}
+
+// errUnknownType marks the failure to find the type named by an envelope, which is about its type
+// field rather than its payload.
+type errUnknownType struct {
+	error
+}
+
+func (e *EnvelopeDecoder) decodePayload(name string, payload json.RawMessage) (proto.Message, error) {
+	if factory, ok := e.types[name]; ok {
+		pb := factory()
+		return pb, e.u.UnmarshalBytes(payload, pb)
+	}
+	if !e.ResolveTypes {
+		return nil, errUnknownType{codeErrorf(CodeUnknownType, "unknown message type %q", name)}
+	}
+	pb, err := e.u.UnmarshalByName(name, payload)
+	if ErrorCode(err) == CodeUnknownType {
+		return nil, errUnknownType{err}
+	}
+	return pb, err
+}
+
+// locate makes err, about the type or the payload of an envelope, a field error of its field.
+func (e *EnvelopeDecoder) locate(typeField, payloadField string, err error) error {
+	if unknown, ok := err.(errUnknownType); ok {
+		return fieldErrorAt(typeField, typeField, unknown.error)
+	}
+	if multi, ok := err.(MultiError); ok {
+		located := make(MultiError, len(multi))
+		for i, err := range multi {
+			located[i] = e.locate(typeField, payloadField, err)
+		}
+		return located
+	}
+	if _, ok := err.(*MoreErrors); ok {
+		return err
+	}
+	return fieldErrorAt(payloadField, payloadField, err)
+}
@@ -0,0 +1,80 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeDecoder(t *testing.T) {
+	e := nicejsonpb.NewEnvelopeDecoder()
+	e.Register("message.created", func() proto.Message { return &validatortest.Message3{} })
+	e.Register("item.created", func() proto.Message { return &validatortest.CreateRequest_Item{} })
+
+	pb, name, err := e.Decode([]byte(`{"id": 1, "type": "message.created", "payload": {"someString": "foo"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "message.created", name)
+	require.Equal(t, "foo", pb.(*validatortest.Message3).SomeString)
+
+	pb, name, err = e.Decode([]byte(`{"type": "item.created"}`))
+	require.NoError(t, err)
+	require.Equal(t, "item.created", name)
+	require.True(t, proto.Equal(&validatortest.CreateRequest_Item{}, pb))
+
+	_, _, err = e.Decode([]byte(`{"type": "message.created", "payload": {"someEmbedded": {"identifier": 3}}}`))
+	require.EqualError(t, err, "unparsable field payload.SomeEmbedded.Identifier: json: cannot unmarshal number into Go value of type string")
+	require.Equal(t, "/payload/someEmbedded/identifier", nicejsonpb.ErrorPointer(err))
+}
+
+func TestEnvelopeDecoder_Errors(t *testing.T) {
+	e := nicejsonpb.New(nicejsonpb.WithAllErrors()).NewEnvelopeDecoder()
+	e.TypeField, e.PayloadField = "kind", "data"
+	e.Register("m", func() proto.Message { return &validatortest.Message3{} })
+
+	_, _, err := e.Decode([]byte(`{"data": {}}`))
+	require.EqualError(t, err, `envelope has no "kind" field`)
+	require.Equal(t, nicejsonpb.CodeRequiredFieldMissing, nicejsonpb.ErrorCode(err))
+
+	_, _, err = e.Decode([]byte(`{"kind": 3}`))
+	require.EqualError(t, err, "unparsable field kind: type name must be a string, got 3")
+
+	_, name, err := e.Decode([]byte(`{"kind": "validatortest.Message3", "data": {}}`))
+	require.Equal(t, "validatortest.Message3", name)
+	require.EqualError(t, err, `unparsable field kind: unknown message type "validatortest.Message3"`)
+	require.Equal(t, nicejsonpb.CodeUnknownType, nicejsonpb.ErrorCode(err))
+
+	e.ResolveTypes = true
+	pb, _, err := e.Decode([]byte(`{"kind": "validatortest.Message3", "data": {"someInt32": 3}}`))
+	require.NoError(t, err)
+	require.Equal(t, int32(3), pb.(*validatortest.Message3).SomeInt32)
+
+	_, _, err = e.Decode([]byte(`{"kind": "m", "data": {"someInt32": "x", "someBool": 1}}`))
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 2)
+	for _, err := range multi {
+		require.True(t, strings.HasPrefix(nicejsonpb.ErrorPointer(err), "/data/"), err.Error())
+	}
+}
+
+func TestEnvelopeDecoder_DecodeNext(t *testing.T) {
+	e := nicejsonpb.NewEnvelopeDecoder()
+	e.Register("m", func() proto.Message { return &validatortest.Message3{} })
+	dec := json.NewDecoder(strings.NewReader(`{"type": "m", "payload": {"someString": "a"}} {"type": "m", "payload": {"someString": "b"}}`))
+	var got []string
+	for {
+		pb, _, err := e.DecodeNext(dec)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, pb.(*validatortest.Message3).SomeString)
+	}
+	require.Equal(t, []string{"a", "b"}, got)
+}
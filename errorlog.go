@@ -0,0 +1,83 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+)
+
+// LogValue logs field errors with log/slog as a group of the path to the field, also as a JSON
+// Pointer, the JSON key of the field itself, the code of the error and the reason it failed, so that
+// logs can be aggregated by field.
+func (f *fieldError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("path", f.fieldStack),
+		slog.String("pointer", f.Pointer()),
+		slog.String("key", f.key()),
+		slog.String("code", ErrorCode(f).String()),
+		slog.String("reason", f.nestedErr.Error()),
+	)
+}
+
+// MarshalJSON encodes field errors as an object holding the same fields as LogValue.
+func (f *fieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path    []string `json:"path"`
+		Pointer string   `json:"pointer"`
+		Key     string   `json:"key"`
+		Code    string   `json:"code"`
+		Reason  string   `json:"reason"`
+	}{f.fieldStack, f.Pointer(), f.key(), ErrorCode(f).String(), f.nestedErr.Error()})
+}
+
+// key returns the last element of the path of f, the field that failed.
+func (f *fieldError) key() string {
+	if len(f.fieldStack) == 0 {
+		return ""
+	}
+	return f.fieldStack[len(f.fieldStack)-1]
+}
+
+// LogValue logs all the errors of m in a group keyed by their index, the field errors among them
+// structured as they are on their own.
+func (m MultiError) LogValue() slog.Value {
+	attrs := make([]slog.Attr, len(m))
+	for i, err := range m {
+		attrs[i] = slog.Any(strconv.Itoa(i), err)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// MarshalJSON encodes m as an array of its errors, field errors encoded as they are on their own
+// and other errors as an object holding only their reason.
+func (m MultiError) MarshalJSON() ([]byte, error) {
+	errs := make([]interface{}, len(m))
+	for i, err := range m {
+		if _, ok := err.(json.Marshaler); ok {
+			errs[i] = err
+		} else {
+			errs[i] = struct {
+				Reason string `json:"reason"`
+			}{err.Error()}
+		}
+	}
+	return json.Marshal(errs)
+}
+
+// LogValue logs formatted errors the way the error they format is logged.
+func (f *formattedError) LogValue() slog.Value {
+	if lv, ok := f.err.(slog.LogValuer); ok {
+		return lv.LogValue()
+	}
+	return slog.StringValue(f.err.Error())
+}
+
+// MarshalJSON encodes formatted errors the way the error they format is encoded.
+func (f *formattedError) MarshalJSON() ([]byte, error) {
+	if m, ok := f.err.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	return json.Marshal(struct {
+		Reason string `json:"reason"`
+	}{f.err.Error()})
+}
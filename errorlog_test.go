@@ -0,0 +1,52 @@
+package nicejsonpb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldError_LogsStructured(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someEmbedded": {"someValue": true}}`, &validatortest.Message3{})
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTime})).Error("bad request", "err", err)
+	require.JSONEq(t, `{"level": "ERROR", "msg": "bad request", "err": {
+		"path": ["SomeEmbedded", "SomeValue"],
+		"pointer": "/someEmbedded/someValue",
+		"key": "SomeValue",
+		"code": "TypeMismatch",
+		"reason": "json: cannot unmarshal bool into Go value of type int64"
+	}}`, buf.String())
+}
+
+func TestMultiError_MarshalsJSON(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors())
+	err := u.Unmarshal(bytes.NewReader([]byte(`{"someInt32": "x", "someUint32": 1.5}`)), &validatortest.Message3{})
+	require.Error(t, err)
+
+	out, mErr := json.Marshal(err)
+	require.NoError(t, mErr)
+	require.JSONEq(t, `[
+		{"path": ["SomeInt32"], "pointer": "/someInt32", "key": "SomeInt32", "code": "TypeMismatch", "reason": "json: cannot unmarshal string into Go value of type int32"},
+		{"path": ["SomeUint32"], "pointer": "/someUint32", "key": "SomeUint32", "code": "TypeMismatch", "reason": "value 1.5 is not an integer, expected uint32"}
+	]`, string(out))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTime})).Error("bad request", "err", err)
+	require.Contains(t, buf.String(), "err.0.key=SomeInt32")
+	require.Contains(t, buf.String(), "err.1.key=SomeUint32")
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
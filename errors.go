@@ -4,31 +4,78 @@ import (
 	"strings"
 	"reflect"
 	"encoding/json"
+	"errors"
 	"github.com/golang/protobuf/proto"
-	"fmt"
+	"sort"
 )
 
 type fieldError struct {
 	fieldStack []string
-	nestedErr  error
+	// keys are the object keys and array indexes in the JSON of the elements
+	// of fieldStack, the reference tokens of Pointer. If they don't match
+	// fieldStack they are derived from it.
+	keys      []string
+	nestedErr error
+	// raw is the JSON value the error is about, if known.
+	raw json.RawMessage
 }
 
 func (f *fieldError) Error() string {
 	return "unparsable field " + strings.Join(f.fieldStack, ".") + ": " + f.nestedErr.Error()
 }
 
+// Raw returns the JSON value the error is about, or nil if it isn't known.
+func (f *fieldError) Raw() json.RawMessage {
+	return f.raw
+}
+
+// ErrorRaw returns the JSON value that err, which may be wrapped, is about: the value of the field
+// that failed to decode. It is nil if err isn't about a single value. For a MultiError it is that of
+// the first error.
+func ErrorRaw(err error) json.RawMessage {
+	var rErr interface{ Raw() json.RawMessage }
+	if errors.As(err, &rErr) {
+		return rErr.Raw()
+	}
+	return nil
+}
+
+// Unwrap gives errors.Is, errors.As and ErrorCode access to the error of the field.
+func (f *fieldError) Unwrap() error {
+	return f.nestedErr
+}
+
 // FieldError wraps a given error providing a message call stack.
 func FieldError(fieldName string, err error) error {
 	if fErr, ok := err.(*fieldError); ok {
+		fErr.keys = append([]string{pointerKey(fieldName)}, fErr.pointerKeys()...)
 		fErr.fieldStack = append([]string{fieldName}, fErr.fieldStack...)
 		return err
 	}
 	return &fieldError{
 		fieldStack: []string{fieldName},
+		keys:       []string{pointerKey(fieldName)},
 		nestedErr:  err,
 	}
 }
 
+// fieldErrorAt is FieldError for the field called name that has the given key in the JSON.
+func fieldErrorAt(name string, key string, err error) error {
+	fErr := FieldError(name, err).(*fieldError)
+	fErr.keys[0] = key
+	return fErr
+}
+
+// sortedNames returns the keys of a set of names in order.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // correctJsonType gets rid of the dredded json.RawMessage errors and casts them to the right type.
 func correctJsonType(err error, realType reflect.Type) error {
 	if uErr, ok := err.(*json.UnmarshalTypeError); ok {
@@ -38,15 +85,31 @@ func correctJsonType(err error, realType reflect.Type) error {
 	return err
 }
 
-func getFieldMismatchError(remainingFields map[string]json.RawMessage, structProps *proto.StructProperties) error {
-	remaining := []string{}
-	for k, _ := range remainingFields {
-		remaining = append(remaining, k)
-	}
-	known := []string{}
+// getFieldMismatchError reports the fields of a JSON object left over after decoding all the known
+// fields of the message pb, with structProps, into it.
+func getFieldMismatchError(remainingFields map[string]json.RawMessage, structProps *proto.StructProperties, pb proto.Message) error {
+	known := map[string]bool{}
 	for _, prop := range structProps.Prop {
+		// XXX_ fields and the fields holding oneofs have no tag.
+		if prop.Tag == 0 {
+			continue
+		}
 		jsonNames := acceptedJSONFieldNames(prop)
-		known = append(known, jsonNames.camel)
+		known[jsonNames.String()] = true
+	}
+	for _, oop := range structProps.OneofTypes {
+		known[acceptedJSONFieldNames(oop.Prop).String()] = true
 	}
-	return fmt.Errorf("fields %v do not exist in set of known fields %v", remaining, known)
-}
\ No newline at end of file
+	if pb != nil {
+		for _, desc := range proto.RegisteredExtensions(pb) {
+			known["["+desc.Name+"]"] = true
+		}
+	}
+	return fieldMismatchError(remainingFields, known)
+}
+
+// fieldMismatchError reports the fields of a JSON object left over after decoding all known ones,
+// listing both in order. known holds an entry per field, naming it as fieldNames.String does.
+func fieldMismatchError(remainingFields map[string]json.RawMessage, known map[string]bool) error {
+	return codeErrorf(CodeUnknownField, "fields %v do not exist in set of known fields %v", sortedKeys(remainingFields), sortedNames(known))
+}
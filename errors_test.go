@@ -0,0 +1,78 @@
+package nicejsonpb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorRaw(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		raw   string
+	}{
+		{`{"someString": "ok"}`, ``},
+		{`{"someUint32": -1}`, `-1`},
+		{`{"someEmbedded": {"someValue": "x1", "identifier": "id"}}`, `"x1"`},
+		{`{"someIntRep": [1, true]}`, `true`},
+		{`{"someStringToStatus": {"a": "NOPE"}}`, `"NOPE"`},
+		{`{"someEmbeddedRep": [{"children": [{"someValue": {}}]}]}`, `{}`},
+		{`{"noSuchField": 1}`, ``},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{})
+		require.Equal(t, tc.raw, string(nicejsonpb.ErrorRaw(err)), "%s: %v", tc.input, err)
+
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.Equal(t, tc.raw, string(nicejsonpb.ErrorRaw(err)), "%s: %v", tc.input, err)
+	}
+}
+
+func TestErrorRaw_AllErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors(), nicejsonpb.WithNullHandling(nicejsonpb.NullIsError))
+	err := u.Unmarshal(strings.NewReader(`{"someString": null, "someEmbedded": {"someValue": 1.5}}`), &validatortest.Message3{})
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 2)
+	require.Equal(t, `null`, string(nicejsonpb.ErrorRaw(multi[0])))
+	require.Equal(t, `1.5`, string(nicejsonpb.ErrorRaw(multi[1])))
+}
+
+func TestErrorPointer(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		pointer string
+	}{
+		{`{"someString": "ok"}`, ``},
+		{`{"noSuchField": 1}`, ``},
+		{`{"someEmbedded": {"some_value": "x1"}}`, `/someEmbedded/some_value`},
+		{`{"some_embedded_rep": [{}, {"children": [{"someValue": {}}]}]}`, `/some_embedded_rep/1/children/0/someValue`},
+		{`{"someStringToStatus": {"a/b~c": "NOPE"}}`, `/someStringToStatus/a~1b~0c`},
+		{`{"someEmbedded": {"wat": 1}}`, `/someEmbedded`},
+		{`{"someIntRep": [1, null]}`, `/someIntRep/1`},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{})
+		require.Equal(t, tc.pointer, nicejsonpb.ErrorPointer(err), "%s: %v", tc.input, err)
+
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.Equal(t, tc.pointer, nicejsonpb.ErrorPointer(err), "%s: %v", tc.input, err)
+	}
+}
+
+func TestErrorPointer_AllErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors(), nicejsonpb.WithNullHandling(nicejsonpb.NullIsError))
+	err := u.Unmarshal(strings.NewReader(`{"some_string": null, "someEmbedded": {"some_value": 1.5}}`), &validatortest.Message3{})
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 2)
+	require.Equal(t, `/some_string`, nicejsonpb.ErrorPointer(multi[0]))
+	require.Equal(t, `/someEmbedded/some_value`, nicejsonpb.ErrorPointer(multi[1]))
+}
+
+func TestFieldError_PointerDerivedFromPath(t *testing.T) {
+	err := nicejsonpb.FieldError("SomeEmbeddedRep", nicejsonpb.FieldError("[2]", errors.New("bad")))
+	require.Equal(t, `/someEmbeddedRep/2`, nicejsonpb.ErrorPointer(err))
+}
@@ -0,0 +1,66 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// isExtensionKey reports whether a JSON object key names an extension, e.g. "[my.pkg.my_extension]".
+func isExtensionKey(key string) bool {
+	return len(key) > 2 && strings.HasPrefix(key, "[") && strings.HasSuffix(key, "]")
+}
+
+// unmarshalExtensions consumes the extension keys of jsonFields that resolve to extensions registered
+// for the message held in target, and sets them on it. Keys that don't resolve are left in place to be
+// reported as unknown fields.
+func (d *decodeState) unmarshalExtensions(target reflect.Value, jsonFields map[string]json.RawMessage) error {
+	keys := []string{}
+	for key := range jsonFields {
+		if isExtensionKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	pb, ok := target.Addr().Interface().(proto.Message)
+	if !ok {
+		return nil
+	}
+	byName := map[string]*proto.ExtensionDesc{}
+	for _, desc := range proto.RegisteredExtensions(pb) {
+		byName[desc.Name] = desc
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		desc, ok := byName[key[1:len(key)-1]]
+		if !ok || !d.fieldAllowed(key) {
+			continue
+		}
+		raw := jsonFields[key]
+		delete(jsonFields, key)
+		d.countField()
+
+		var prop proto.Properties
+		prop.Parse(desc.Tag)
+		value := reflect.New(reflect.TypeOf(desc.ExtensionType)).Elem()
+		if isNull(raw) && !acceptsNull(value.Type(), &prop) {
+			set, err := d.unmarshalNullField(key, key, value)
+			if err != nil || !set {
+				return err
+			}
+		} else if err := d.redactingDynamicField(desc.TypeDescriptor(), func() error {
+			return d.unmarshalElement(key, key, value, raw, &prop)
+		}); err != nil {
+			return err
+		}
+		if err := proto.SetExtension(pb, desc, value.Interface()); err != nil {
+			return FieldError(key, err)
+		}
+	}
+	return nil
+}
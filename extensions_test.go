@@ -0,0 +1,46 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_DecodesExtensions(t *testing.T) {
+	input := `{
+		"someString": "foo",
+		"[validatortest.some_ext_int]": "42",
+		"[validatortest.some_ext_embedded]": {"someValue": 3},
+		"[validatortest.some_ext_strings]": ["a", "b"]
+	}`
+	stuff := &validatortest.Message2{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Equal(t, "foo", stuff.GetSomeString())
+
+	extInt, err := proto.GetExtension(stuff, validatortest.E_SomeExtInt)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), *extInt.(*int64))
+	extEmbedded, err := proto.GetExtension(stuff, validatortest.E_SomeExtEmbedded)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), extEmbedded.(*validatortest.Message2_Embedded).GetSomeValue())
+	extStrings, err := proto.GetExtension(stuff, validatortest.E_SomeExtStrings)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, extStrings)
+}
+
+func TestUnmarshal_FindsErrorsInExtensions(t *testing.T) {
+	input := `{"[validatortest.some_ext_embedded]": {"identifier": 3.1}}`
+	stuff := &validatortest.Message2{}
+	err := nicejsonpb.UnmarshalString(input, stuff)
+	require.EqualError(t, err, "unparsable field [validatortest.some_ext_embedded].Identifier: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshal_UnregisteredExtensionIsUnknown(t *testing.T) {
+	input := `{"[validatortest.not_an_extension]": 1}`
+	stuff := &validatortest.Message2{}
+	err := nicejsonpb.UnmarshalString(input, stuff)
+	require.EqualError(t, err, "fields [[validatortest.not_an_extension]] do not exist in set of known fields [[validatortest.some_ext_embedded] [validatortest.some_ext_int] [validatortest.some_ext_strings] some_default_int/someDefaultInt some_default_string/someDefaultString some_embedded/someEmbedded some_int/someInt some_string/someString]")
+}
@@ -0,0 +1,30 @@
+package nicejsonpb
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// AllowFields returns a FieldFilter allowing only the fields in mask to be set, along with the
+// fields nested in them and the messages leading to them.
+func AllowFields(mask *fieldmaskpb.FieldMask) func(path string) bool {
+	paths := append([]string{}, mask.GetPaths()...)
+	return func(path string) bool {
+		for _, p := range paths {
+			if p == path || strings.HasPrefix(path, p+".") || strings.HasPrefix(p, path+".") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// fieldAllowed reports whether the field of the message being decoded with the given proto name may
+// be set, according to the FieldFilter.
+func (d *decodeState) fieldAllowed(origName string) bool {
+	if d.FieldFilter == nil {
+		return true
+	}
+	return d.FieldFilter(strings.Join(append(d.protoPath, origName), "."))
+}
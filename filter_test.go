@@ -0,0 +1,44 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestUnmarshal_AllowedFields(t *testing.T) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"some_string", "some_embedded.some_value", "some_embedded_rep"}}
+	u := nicejsonpb.New(nicejsonpb.WithAllowedFields(mask))
+
+	input := `{"someString": "a", "someEmbedded": {"someValue": 1}, "someEmbeddedRep": [{"identifier": "x"}]}`
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, "x", m.SomeEmbeddedRep[0].Identifier)
+	_, err := u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+
+	for _, input := range []string{
+		`{"someInt32": 1}`,
+		`{"someEmbedded": {"identifier": "server-id"}}`,
+	} {
+		err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+		require.Equal(t, nicejsonpb.CodeUnknownField, nicejsonpb.ErrorCode(err), input)
+		_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+		require.Equal(t, nicejsonpb.CodeUnknownField, nicejsonpb.ErrorCode(err), input)
+	}
+}
+
+func TestUnmarshal_FieldFilterDropsWithAllowUnknownFields(t *testing.T) {
+	u := nicejsonpb.New(
+		nicejsonpb.WithAllowUnknownFields(),
+		nicejsonpb.WithFieldFilter(func(path string) bool { return path != "some_embedded.identifier" }),
+	)
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someEmbedded": {"identifier": "server-id", "someValue": 3}}`), m))
+	require.Equal(t, "", m.SomeEmbedded.Identifier)
+	require.Equal(t, int64(3), m.SomeEmbedded.SomeValue)
+}
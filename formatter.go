@@ -0,0 +1,82 @@
+package nicejsonpb
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorFormatter produces the messages of the errors of an unmarshal, for example in the language
+// of the user.
+type ErrorFormatter interface {
+	// FormatError returns the whole message of an error of class code for the field at path, which
+	// is empty for errors about the document as a whole.
+	FormatError(code Code, path []string, params ErrorParams) string
+}
+
+// ErrorParams describes what went wrong in an error given to an ErrorFormatter.
+type ErrorParams struct {
+	// Message is the English message of the error, without the field path.
+	Message string
+	// Format is the English format string, in fmt syntax, Message is made from with Args. It
+	// doesn't change from one error of the same kind to another, so it can be used to look up
+	// translations.
+	Format string
+	Args   []interface{}
+}
+
+// DefaultErrorFormatter produces the English messages errors have when no ErrorFormatter is set.
+var DefaultErrorFormatter ErrorFormatter = defaultErrorFormatter{}
+
+type defaultErrorFormatter struct{}
+
+func (defaultErrorFormatter) FormatError(code Code, path []string, params ErrorParams) string {
+	if len(path) == 0 {
+		return params.Message
+	}
+	return "unparsable field " + strings.Join(path, ".") + ": " + params.Message
+}
+
+// errorParams returns the ErrorParams of err, the error of a field.
+func errorParams(err error) ErrorParams {
+	if mErr, ok := err.(*MoreErrors); ok {
+		return ErrorParams{Message: err.Error(), Format: "and %d more errors", Args: []interface{}{mErr.Count}}
+	}
+	var cErr *codedError
+	if errors.As(err, &cErr) && cErr.format != "" {
+		return ErrorParams{Message: err.Error(), Format: cErr.format, Args: cErr.args}
+	}
+	return ErrorParams{Message: err.Error(), Format: "%v", Args: []interface{}{err}}
+}
+
+// formattedError is an error with a message produced by an ErrorFormatter.
+type formattedError struct {
+	err error
+	msg string
+}
+
+func (f *formattedError) Error() string {
+	return f.msg
+}
+
+func (f *formattedError) Unwrap() error {
+	return f.err
+}
+
+// formatErrors gives err, and all the errors of a MultiError, the messages produced by the
+// ErrorFormatter if there is one.
+func (d *decodeState) formatErrors(err error) error {
+	if err == nil || d.ErrorFormatter == nil {
+		return err
+	}
+	switch err := err.(type) {
+	case MultiError:
+		formatted := make(MultiError, len(err))
+		for i, e := range err {
+			formatted[i] = d.formatErrors(e)
+		}
+		return formatted
+	case *fieldError:
+		return &formattedError{err: err, msg: d.ErrorFormatter.FormatError(ErrorCode(err), err.fieldStack, errorParams(err.nestedErr))}
+	}
+	return &formattedError{err: err, msg: d.ErrorFormatter.FormatError(ErrorCode(err), nil, errorParams(err))}
+}
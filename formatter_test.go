@@ -0,0 +1,72 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+// germanFormatter translates the errors it knows by their format, leaving the others to the default.
+type germanFormatter struct{}
+
+func (germanFormatter) FormatError(code nicejsonpb.Code, path []string, params nicejsonpb.ErrorParams) string {
+	switch params.Format {
+	case "value %s out of range for %v":
+		return fmt.Sprintf("Feld %s: Wert %s außerhalb des Bereichs von %v", strings.Join(path, "."), params.Args[0], params.Args[1])
+	}
+	return nicejsonpb.DefaultErrorFormatter.FormatError(code, path, params)
+}
+
+func TestUnmarshal_ErrorFormatter(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithErrorFormatter(germanFormatter{}), nicejsonpb.WithAllErrors())
+	input := `{"someEmbedded": {"someValue": 9223372036854775808}, "someStatus": "NOPE"}`
+	expected := "unparsable field SomeStatus: unknown value '\"NOPE\"' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]; " +
+		"Feld SomeEmbedded.SomeValue: Wert 9223372036854775808 außerhalb des Bereichs von int64"
+
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, expected)
+	require.Equal(t, nicejsonpb.CodeEnumUnknownValue, nicejsonpb.ErrorCode(err))
+
+	_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, expected)
+}
+
+func TestDefaultErrorFormatter_MatchesErrors(t *testing.T) {
+	input := `{"someBool": "yes", "noSuchField": 1}`
+	plain := nicejsonpb.UnmarshalString(input, &validatortest.Message3{})
+	require.Error(t, plain)
+
+	u := nicejsonpb.New(nicejsonpb.WithErrorFormatter(nicejsonpb.DefaultErrorFormatter))
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, plain.Error())
+}
+
+func TestErrorFormatter_Streams(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithErrorFormatter(germanFormatter{}))
+	doc := `{"someEmbedded": {"someValue": 9223372036854775808}}`
+	translated := "Feld %s: Wert 9223372036854775808 außerhalb des Bereichs von int64"
+
+	err := u.DecodeArray(json.NewDecoder(strings.NewReader(`[{}, `+doc+`]`)), func() proto.Message { return &validatortest.Message3{} }, func(proto.Message) error { return nil })
+	require.EqualError(t, err, fmt.Sprintf(translated, "[1].SomeEmbedded.SomeValue"))
+	require.Equal(t, "/1/someEmbedded/someValue", nicejsonpb.ErrorPointer(err))
+
+	for _, err := range nicejsonpb.Decode[*validatortest.Message3](strings.NewReader(`[`+doc+`]`), nicejsonpb.WithErrorFormatter(germanFormatter{})) {
+		require.EqualError(t, err, fmt.Sprintf(translated, "[0].SomeEmbedded.SomeValue"))
+		require.Equal(t, "/0/someEmbedded/someValue", nicejsonpb.ErrorPointer(err))
+	}
+
+	err = u.UnmarshalNext(json.NewDecoder(strings.NewReader(doc)), &validatortest.Message3{})
+	require.EqualError(t, err, "document 1: "+fmt.Sprintf(translated, "SomeEmbedded.SomeValue"))
+	require.Equal(t, "/someEmbedded/someValue", nicejsonpb.ErrorPointer(err))
+
+	dec := u.NewStreamDecoder(strings.NewReader("\n" + doc))
+	err = dec.Decode(&validatortest.Message3{})
+	require.EqualError(t, err, "line 2: "+fmt.Sprintf(translated, "SomeEmbedded.SomeValue"))
+	require.Equal(t, "/someEmbedded/someValue", nicejsonpb.ErrorPointer(err))
+}
@@ -0,0 +1,36 @@
+package nicejsonpb
+
+import (
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// UnmarshalT decodes the JSON document held in data into a newly allocated message of type T, which
+// is a pointer to a generated message type, using an Unmarshaler configured by opts:
+//
+//	req, err := nicejsonpb.UnmarshalT[*pb.CreateRequest](body, nicejsonpb.WithAllErrors())
+func UnmarshalT[T proto.Message](data []byte, opts ...Option) (T, error) {
+	m := newMessage[T]()
+	if err := New(opts...).UnmarshalBytes(data, m); err != nil {
+		var zero T
+		return zero, err
+	}
+	return m, nil
+}
+
+// newMessage allocates a message of type T, a pointer to a generated message type.
+func newMessage[T proto.Message]() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
+
+// MustUnmarshalT is like UnmarshalT but panics if the JSON can't be decoded. It is meant for
+// documents known to be valid, such as those embedded in tests or in the program itself.
+func MustUnmarshalT[T proto.Message](data []byte, opts ...Option) T {
+	m, err := UnmarshalT[T](data, opts...)
+	if err != nil {
+		panic("nicejsonpb: " + err.Error())
+	}
+	return m
+}
@@ -0,0 +1,32 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalT(t *testing.T) {
+	m, err := nicejsonpb.UnmarshalT[*validatortest.Message3]([]byte(`{"someString": "x", "someInt32": 3}`))
+	require.NoError(t, err)
+	require.Equal(t, "x", m.SomeString)
+	require.Equal(t, int32(3), m.SomeInt32)
+
+	m, err = nicejsonpb.UnmarshalT[*validatortest.Message3]([]byte(`{"someInt32": "x", "unknown": 1}`), nicejsonpb.WithAllErrors())
+	require.Nil(t, m)
+	require.ErrorAs(t, err, new(nicejsonpb.MultiError))
+
+	m, err = nicejsonpb.UnmarshalT[*validatortest.Message3]([]byte(`{"unknown": 1}`), nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, err)
+	require.NotNil(t, m)
+}
+
+func TestMustUnmarshalT(t *testing.T) {
+	m := nicejsonpb.MustUnmarshalT[*validatortest.Message2]([]byte(`{"someString": "x"}`))
+	require.Equal(t, "x", m.GetSomeString())
+	require.PanicsWithValue(t, "nicejsonpb: unparsable field SomeInt: invalid character 'x' looking for beginning of value while looking for an integer in a string", func() {
+		nicejsonpb.MustUnmarshalT[*validatortest.Message2]([]byte(`{"someInt": "x"}`))
+	})
+}
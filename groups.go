@@ -0,0 +1,35 @@
+package nicejsonpb
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Proto2 groups declare their message type in place, as in "optional group SomeGroup = 1 { ... }",
+// which makes a field of the lowercased name "somegroup". The JSON of C++ and Java names the field
+// after the group instead, so "SomeGroup" is the JSON name of the field, with "somegroup" accepted
+// too as its proto name.
+
+// isGroup reports whether prop is of a proto2 group field.
+func isGroup(prop *proto.Properties) bool {
+	return prop.Wire == "group"
+}
+
+// protoFieldName returns the proto name of the field of prop, which for groups is the lowercased
+// name of the group.
+func protoFieldName(prop *proto.Properties) string {
+	if isGroup(prop) {
+		return strings.ToLower(prop.OrigName)
+	}
+	return prop.OrigName
+}
+
+// dynamicJSONName returns the JSON name of fd, which for groups is the name of the group.
+func dynamicJSONName(fd protoreflect.FieldDescriptor) string {
+	if fd.Kind() == protoreflect.GroupKind {
+		return fd.TextName()
+	}
+	return fd.JSONName()
+}
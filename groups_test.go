@@ -0,0 +1,43 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_Groups(t *testing.T) {
+	for _, doc := range []string{
+		`{"SomeGroup": {"label": "foo", "someValue": 3}, "Item": [{"id": 1}, {"id": 2}]}`,
+		`{"somegroup": {"label": "foo", "some_value": 3}, "item": [{"id": 1}, {"id": 2}]}`,
+	} {
+		m := &validatortest.Grouped2{}
+		require.NoError(t, nicejsonpb.UnmarshalString(doc, m), doc)
+		require.Equal(t, "foo", m.GetSomegroup().GetLabel(), doc)
+		require.Equal(t, int32(3), m.GetSomegroup().GetSomeValue(), doc)
+		require.Len(t, m.GetItem(), 2, doc)
+		require.Equal(t, int32(2), m.GetItem()[1].GetId(), doc)
+	}
+
+	err := nicejsonpb.UnmarshalString(`{"SomeGroup": {"someValue": "x"}}`, &validatortest.Grouped2{})
+	require.Equal(t, "/SomeGroup/someValue", nicejsonpb.ErrorPointer(err))
+	require.Equal(t, "SomeGroup.someValue", nicejsonpb.NewProblem(err).InvalidParams[0].Field)
+	err = nicejsonpb.UnmarshalString(`{"someGroup": {}}`, &validatortest.Grouped2{})
+	require.Contains(t, err.Error(), "somegroup/SomeGroup")
+}
+
+func TestUnmarshalDynamic_Groups(t *testing.T) {
+	desc := validatortest.File_nicejsonpb_proto2_proto.Messages().ByName("Grouped2")
+	m, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"SomeGroup": {"label": "foo"}, "item": [{"id": 1}]}`), desc)
+	require.NoError(t, err)
+	group := m.Get(desc.Fields().ByName("somegroup")).Message()
+	require.Equal(t, "foo", group.Get(group.Descriptor().Fields().ByName("label")).String())
+	require.Equal(t, 1, m.Get(desc.Fields().ByName("item")).List().Len())
+
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"SomeGroup": {"someValue": "x"}}`), desc)
+	require.Equal(t, "/SomeGroup/someValue", nicejsonpb.ErrorPointer(err))
+	require.Equal(t, "SomeGroup.someValue", nicejsonpb.NewProblem(err).InvalidParams[0].Field)
+}
@@ -0,0 +1,154 @@
+// Package grpccodec lets gRPC servers accept JSON, as sent by grpcurl or web clients, decoding it
+// with nicejsonpb. Importing the package registers a codec for the "json" content subtype, so
+// requests sent as application/grpc+json are decoded with the default options:
+//
+//	import _ "github.com/mwitkow/go-nicejsonpb/grpccodec"
+//
+// gRPC fails requests its codec can't decode as INTERNAL, without looking at the error. Services
+// registered through ServiceDesc fail them as INVALID_ARGUMENT instead, with a
+// google.rpc.BadRequest detail listing the fields at fault:
+//
+//	s.RegisterService(grpccodec.ServiceDesc(&pb.Greeter_ServiceDesc), impl)
+package grpccodec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Name is the content subtype of the codec.
+const Name = "json"
+
+func init() {
+	encoding.RegisterCodec(Codec{})
+}
+
+// Codec is a gRPC codec of the "json" content subtype, marshaling messages with protojson and
+// unmarshaling them with nicejsonpb. Registering a Codec with other options replaces the one
+// registered by the package.
+type Codec struct {
+	// Unmarshaler holds the options messages are decoded with, the defaults if nil.
+	Unmarshaler *nicejsonpb.Unmarshaler
+}
+
+// Name returns the content subtype of the codec.
+func (Codec) Name() string {
+	return Name
+}
+
+// Marshal encodes v, a protocol buffer, as JSON.
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	pb, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("grpccodec: %T is not a protocol buffer", v)
+	}
+	return protojson.Marshal(proto.MessageV2(pb))
+}
+
+// Unmarshal decodes the JSON held in data into v, a protocol buffer. It fails with a status error
+// of code INVALID_ARGUMENT.
+func (c Codec) Unmarshal(data []byte, v interface{}) error {
+	pb, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("grpccodec: %T is not a protocol buffer", v)
+	}
+	u := c.Unmarshaler
+	if u == nil {
+		u = new(nicejsonpb.Unmarshaler)
+	}
+	err := u.UnmarshalBytes(data, pb)
+	if err == nil {
+		return nil
+	}
+	st := Status(err)
+	if _, ok := awaited.Load(v); ok {
+		awaited.Store(v, st)
+	}
+	return st.Err()
+}
+
+// Status converts an error of nicejsonpb into a status of code INVALID_ARGUMENT, with a
+// google.rpc.BadRequest detail holding a violation per field error, named as in the invalid-params
+// of nicejsonpb.NewProblem.
+func Status(err error) *status.Status {
+	st := status.New(codes.InvalidArgument, err.Error())
+	errs := []error{err}
+	var multi nicejsonpb.MultiError
+	if errors.As(err, &multi) {
+		errs = multi
+	}
+	br := &errdetails.BadRequest{}
+	for _, err := range errs {
+		for _, param := range nicejsonpb.NewProblem(err).InvalidParams {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       param.Field,
+				Description: param.Reason,
+			})
+		}
+	}
+	if len(br.FieldViolations) == 0 {
+		return st
+	}
+	if detailed, err := st.WithDetails(br); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// awaited holds the messages being decoded for a handler wrapped by ServiceDesc, and the status of
+// their failure once Codec.Unmarshal fails.
+var awaited sync.Map
+
+// ServiceDesc returns a copy of desc whose handlers fail requests that the Codec can't decode with
+// its status, INVALID_ARGUMENT, rather than INTERNAL as gRPC does.
+func ServiceDesc(desc *grpc.ServiceDesc) *grpc.ServiceDesc {
+	wrapped := *desc
+	wrapped.Methods = make([]grpc.MethodDesc, len(desc.Methods))
+	for i, m := range desc.Methods {
+		handler := m.Handler
+		m.Handler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			return handler(srv, ctx, func(v interface{}) error {
+				return decode(v, dec)
+			}, interceptor)
+		}
+		wrapped.Methods[i] = m
+	}
+	wrapped.Streams = make([]grpc.StreamDesc, len(desc.Streams))
+	for i, s := range desc.Streams {
+		handler := s.Handler
+		s.Handler = func(srv interface{}, stream grpc.ServerStream) error {
+			return handler(srv, decodingStream{stream})
+		}
+		wrapped.Streams[i] = s
+	}
+	return &wrapped
+}
+
+// decode decodes a request into v with dec, replacing the error of the Codec by its status.
+func decode(v interface{}, dec func(interface{}) error) error {
+	awaited.Store(v, nil)
+	err := dec(v)
+	if st, _ := awaited.LoadAndDelete(v); st != nil && err != nil {
+		return st.(*status.Status).Err()
+	}
+	return err
+}
+
+type decodingStream struct {
+	grpc.ServerStream
+}
+
+func (s decodingStream) RecvMsg(m interface{}) error {
+	return decode(m, s.ServerStream.RecvMsg)
+}
@@ -0,0 +1,72 @@
+package grpccodec_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb/grpccodec"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// rawCodec sends requests as the JSON they are given, and decodes responses with the Codec.
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return grpccodec.Name }
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) { return []byte(*v.(*string)), nil }
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	return grpccodec.Codec{}.Unmarshal(data, v)
+}
+
+func dial(t *testing.T, desc *grpc.ServiceDesc) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	s.RegisterService(desc, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func check(conn *grpc.ClientConn, request string) (*healthpb.HealthCheckResponse, error) {
+	resp := &healthpb.HealthCheckResponse{}
+	return resp, conn.Invoke(context.Background(), "/grpc.health.v1.Health/Check", &request, resp)
+}
+
+func TestCodec(t *testing.T) {
+	conn := dial(t, grpccodec.ServiceDesc(&healthpb.Health_ServiceDesc))
+	resp, err := check(conn, `{"service": ""}`)
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	_, err = check(conn, `{"service": 3}`)
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "unparsable field Service: json: cannot unmarshal number into Go value of type string", st.Message())
+	require.Len(t, st.Details(), 1)
+	br := st.Details()[0].(*errdetails.BadRequest)
+	require.Equal(t, "service", br.FieldViolations[0].Field)
+	require.Equal(t, "json: cannot unmarshal number into Go value of type string", br.FieldViolations[0].Description)
+}
+
+func TestCodec_WithoutServiceDesc(t *testing.T) {
+	conn := dial(t, &healthpb.Health_ServiceDesc)
+	_, err := check(conn, `{"service": 3}`)
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "unparsable field Service")
+}
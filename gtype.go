@@ -0,0 +1,234 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The common google.type messages take strings besides their fields: dates such as "2024-03-01",
+// times of day such as "13:45:00" and, with MoneyStrings, amounts such as "12.99 USD". Their fields
+// are checked to be in range either way.
+func init() {
+	wellKnownHandlers["google.type.Date"] = (*decodeState).unmarshalDate
+	wellKnownHandlers["google.type.TimeOfDay"] = (*decodeState).unmarshalTimeOfDay
+	wellKnownHandlers["google.type.Money"] = (*decodeState).unmarshalMoney
+}
+
+// unmarshalDate decodes a google.type.Date from a "YYYY-MM-DD" string or its fields.
+func (d *decodeState) unmarshalDate(inputValue json.RawMessage, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	if inputValue[0] != '"' {
+		if err := d.unmarshalDynamicFields(m, inputValue); err != nil {
+			return err
+		}
+		return checkDate(m.Get(fields.ByName("year")).Int(), m.Get(fields.ByName("month")).Int(), m.Get(fields.ByName("day")).Int())
+	}
+	unq, err := strconv.Unquote(string(inputValue))
+	if err != nil {
+		return err
+	}
+	if len(unq) != len("2006-01-02") || unq[4] != '-' || unq[7] != '-' || !isDigits(unq[:4]+unq[5:7]+unq[8:]) {
+		return codeErrorf(CodeBadDate, "bad Date: %s isn't a date in the form YYYY-MM-DD", d.echo("%q", "string", unq))
+	}
+	t, err := time.Parse("2006-01-02", unq)
+	if err != nil || t.Year() == 0 {
+		return codeErrorf(CodeOutOfRange, "bad Date: %s is out of range, there is no such day", d.echo("%q", "string", unq))
+	}
+	m.Set(fields.ByName("year"), protoreflect.ValueOfInt32(int32(t.Year())))
+	m.Set(fields.ByName("month"), protoreflect.ValueOfInt32(int32(t.Month())))
+	m.Set(fields.ByName("day"), protoreflect.ValueOfInt32(int32(t.Day())))
+	return nil
+}
+
+// checkDate checks the fields of a google.type.Date, of which year, or month and day, may be 0 for
+// dates missing them.
+func checkDate(year, month, day int64) error {
+	switch {
+	case year < 0 || year > 9999:
+		return fieldErrorAt("Year", "year", codeErrorf(CodeOutOfRange, "year %d is out of range, expected 1 to 9999, or 0 for no year", year))
+	case month < 0 || month > 12:
+		return fieldErrorAt("Month", "month", codeErrorf(CodeOutOfRange, "month %d is out of range, expected 1 to 12, or 0 for no month", month))
+	case month == 0 && day != 0:
+		return fieldErrorAt("Day", "day", codeErrorf(CodeBadDate, "day %d is set without a month", day))
+	case month == 0 && year == 0:
+		return codeErrorf(CodeBadDate, "bad Date: neither year nor month is set")
+	}
+	if month == 0 {
+		return nil
+	}
+	// Dates with no year may be on February 29th.
+	leapYear := year
+	if year == 0 {
+		leapYear = 2000
+	}
+	days := int64(time.Date(int(leapYear), time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day())
+	if day < 0 || day > days {
+		return fieldErrorAt("Day", "day", codeErrorf(CodeOutOfRange, "day %d is out of range, expected 1 to %d, or 0 for no day", day, days))
+	}
+	return nil
+}
+
+// unmarshalTimeOfDay decodes a google.type.TimeOfDay from a "HH:MM", "HH:MM:SS" or
+// "HH:MM:SS.fffffffff" string, or its fields.
+func (d *decodeState) unmarshalTimeOfDay(inputValue json.RawMessage, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	if inputValue[0] != '"' {
+		if err := d.unmarshalDynamicFields(m, inputValue); err != nil {
+			return err
+		}
+		return checkTimeOfDay(m.Get(fields.ByName("hours")).Int(), m.Get(fields.ByName("minutes")).Int(), m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int())
+	}
+	unq, err := strconv.Unquote(string(inputValue))
+	if err != nil {
+		return err
+	}
+	hours, minutes, seconds, nanos, ok := parseTimeOfDay(unq)
+	if !ok {
+		return codeErrorf(CodeBadDate, "bad TimeOfDay: %s isn't a time in the form HH:MM:SS", d.echo("%q", "string", unq))
+	}
+	if err := checkTimeOfDay(hours, minutes, seconds, nanos); err != nil {
+		return codeErrorf(CodeOutOfRange, "bad TimeOfDay: %s is out of range, expected 00:00:00 to 23:59:59, or 24:00:00", d.echo("%q", "string", unq))
+	}
+	m.Set(fields.ByName("hours"), protoreflect.ValueOfInt32(int32(hours)))
+	m.Set(fields.ByName("minutes"), protoreflect.ValueOfInt32(int32(minutes)))
+	m.Set(fields.ByName("seconds"), protoreflect.ValueOfInt32(int32(seconds)))
+	m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(nanos)))
+	return nil
+}
+
+// parseTimeOfDay splits a "HH:MM", "HH:MM:SS" or "HH:MM:SS.fffffffff" string, with 1 to 9
+// fractional digits, into its parts.
+func parseTimeOfDay(s string) (hours, minutes, seconds, nanos int64, ok bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, 0, false
+	}
+	if len(parts) == 3 {
+		if dot := strings.IndexByte(parts[2], '.'); dot >= 0 {
+			frac := parts[2][dot+1:]
+			if len(frac) == 0 || len(frac) > 9 || !isDigits(frac) {
+				return 0, 0, 0, 0, false
+			}
+			nanos, _ = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+			parts[2] = parts[2][:dot]
+		}
+	} else {
+		parts = append(parts, "00")
+	}
+	var values [3]int64
+	for i, part := range parts {
+		if len(part) != 2 || !isDigits(part) {
+			return 0, 0, 0, 0, false
+		}
+		values[i], _ = strconv.ParseInt(part, 10, 64)
+	}
+	return values[0], values[1], values[2], nanos, true
+}
+
+// checkTimeOfDay checks the fields of a google.type.TimeOfDay, which may be 24:00:00 for closing
+// times.
+func checkTimeOfDay(hours, minutes, seconds, nanos int64) error {
+	if hours == 24 && minutes == 0 && seconds == 0 && nanos == 0 {
+		return nil
+	}
+	switch {
+	case hours < 0 || hours > 23:
+		return fieldErrorAt("Hours", "hours", codeErrorf(CodeOutOfRange, "hours %d are out of range, expected 0 to 23", hours))
+	case minutes < 0 || minutes > 59:
+		return fieldErrorAt("Minutes", "minutes", codeErrorf(CodeOutOfRange, "minutes %d are out of range, expected 0 to 59", minutes))
+	case seconds < 0 || seconds > 59:
+		return fieldErrorAt("Seconds", "seconds", codeErrorf(CodeOutOfRange, "seconds %d are out of range, expected 0 to 59", seconds))
+	case nanos < 0 || nanos > 999999999:
+		return fieldErrorAt("Nanos", "nanos", codeErrorf(CodeOutOfRange, "nanos %d are out of range, expected 0 to 999999999", nanos))
+	}
+	return nil
+}
+
+// unmarshalMoney decodes a google.type.Money from its fields or, with MoneyStrings, a string such as
+// "12.99 USD".
+func (d *decodeState) unmarshalMoney(inputValue json.RawMessage, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	if inputValue[0] != '"' || !d.MoneyStrings {
+		if err := d.unmarshalDynamicFields(m, inputValue); err != nil {
+			return err
+		}
+		return checkMoney(m.Get(fields.ByName("currency_code")).String(), m.Get(fields.ByName("units")).Int(), m.Get(fields.ByName("nanos")).Int())
+	}
+	unq, err := strconv.Unquote(string(inputValue))
+	if err != nil {
+		return err
+	}
+	currency, units, nanos, ok := parseMoney(unq)
+	if !ok {
+		return codeErrorf(CodeBadMoney, "bad Money: %s isn't an amount followed by a currency code, such as \"12.99 USD\"", d.echo("%q", "string", unq))
+	}
+	if err := checkMoney(currency, units, nanos); err != nil {
+		// The string has no fields for the error to be about.
+		return err.(*fieldError).nestedErr
+	}
+	m.Set(fields.ByName("currency_code"), protoreflect.ValueOfString(currency))
+	m.Set(fields.ByName("units"), protoreflect.ValueOfInt64(units))
+	m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(nanos)))
+	return nil
+}
+
+// parseMoney splits a string such as "-12.99 USD", with up to 9 fractional digits, into its parts.
+func parseMoney(s string) (currency string, units, nanos int64, ok bool) {
+	amount, currency, found := strings.Cut(s, " ")
+	if !found {
+		return "", 0, 0, false
+	}
+	negative := strings.HasPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	if !isDigits(whole) || len(frac) > 9 || (frac != "" && !isDigits(frac)) {
+		return "", 0, 0, false
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	if frac != "" {
+		nanos, _ = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	}
+	if negative {
+		units, nanos = -units, -nanos
+	}
+	return currency, units, nanos, true
+}
+
+// checkMoney checks the fields of a google.type.Money: a currency code of three capital letters,
+// and nanos in range and of the same sign as units.
+func checkMoney(currency string, units, nanos int64) error {
+	if len(currency) != 3 || !isCapitals(currency) {
+		return fieldErrorAt("CurrencyCode", "currencyCode", codeErrorf(CodeBadMoney, "currency code %q isn't three capital letters, as in ISO 4217", currency))
+	}
+	switch {
+	case nanos < -999999999 || nanos > 999999999:
+		return fieldErrorAt("Nanos", "nanos", codeErrorf(CodeOutOfRange, "nanos %d are out of range, expected -999999999 to 999999999", nanos))
+	case units > 0 && nanos < 0, units < 0 && nanos > 0:
+		return fieldErrorAt("Nanos", "nanos", codeErrorf(CodeBadMoney, "nanos %d are of the opposite sign to units %d", nanos, units))
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isASCIIDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isCapitals(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/genproto/googleapis/type/timeofday"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestUnmarshal_Date(t *testing.T) {
+	d := &date.Date{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`"2024-02-29"`, d))
+	require.Equal(t, []int32{2024, 2, 29}, []int32{d.Year, d.Month, d.Day})
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"month": 2, "day": 29}`, d))
+	require.Equal(t, []int32{0, 2, 29}, []int32{d.Year, d.Month, d.Day})
+
+	for doc, want := range map[string]string{
+		`"2024-3-01"`:                           `bad Date: "2024-3-01" isn't a date in the form YYYY-MM-DD`,
+		`"2023-02-29"`:                          `bad Date: "2023-02-29" is out of range, there is no such day`,
+		`{"year": 2023, "month": 2, "day": 29}`: "unparsable field Day: day 29 is out of range, expected 1 to 28, or 0 for no day",
+		`{"year": 2023, "month": 13}`:           "unparsable field Month: month 13 is out of range, expected 1 to 12, or 0 for no month",
+		`{"day": 3}`:                            "unparsable field Day: day 3 is set without a month",
+	} {
+		require.EqualError(t, nicejsonpb.UnmarshalString(doc, &date.Date{}), want, doc)
+	}
+}
+
+func TestUnmarshal_TimeOfDay(t *testing.T) {
+	tod := &timeofday.TimeOfDay{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`"13:45:07.25"`, tod))
+	require.Equal(t, []int32{13, 45, 7, 250000000}, []int32{tod.Hours, tod.Minutes, tod.Seconds, tod.Nanos})
+	require.NoError(t, nicejsonpb.UnmarshalString(`"24:00"`, tod))
+	require.Equal(t, int32(24), tod.Hours)
+
+	err := nicejsonpb.UnmarshalString(`"1:45"`, tod)
+	require.EqualError(t, err, `bad TimeOfDay: "1:45" isn't a time in the form HH:MM:SS`)
+	require.Equal(t, nicejsonpb.CodeBadDate, nicejsonpb.ErrorCode(err))
+	err = nicejsonpb.UnmarshalString(`"13:60:00"`, tod)
+	require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+	err = nicejsonpb.UnmarshalString(`{"hours": 12, "nanos": -1}`, tod)
+	require.Equal(t, "/nanos", nicejsonpb.ErrorPointer(err))
+}
+
+func TestUnmarshal_Money(t *testing.T) {
+	m := &money.Money{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"currencyCode": "USD", "units": "12", "nanos": 990000000}`, m))
+	require.Equal(t, "USD", m.CurrencyCode)
+	require.Equal(t, int64(12), m.Units)
+	require.Equal(t, int32(990000000), m.Nanos)
+
+	err := nicejsonpb.UnmarshalString(`"12.99 USD"`, m)
+	require.Equal(t, nicejsonpb.CodeTypeMismatch, nicejsonpb.ErrorCode(err))
+
+	u := nicejsonpb.New(nicejsonpb.WithMoneyStrings())
+	require.NoError(t, u.UnmarshalBytes([]byte(`"-0.5 EUR"`), m))
+	require.Equal(t, "EUR", m.CurrencyCode)
+	require.Equal(t, int64(0), m.Units)
+	require.Equal(t, int32(-500000000), m.Nanos)
+
+	for doc, want := range map[string]string{
+		`"12,99 USD"`: `bad Money: "12,99 USD" isn't an amount followed by a currency code, such as "12.99 USD"`,
+		`"12.99 usd"`: `currency code "usd" isn't three capital letters, as in ISO 4217`,
+		`{"currencyCode": "USD", "units": "1", "nanos": -5}`: "unparsable field Nanos: nanos -5 are of the opposite sign to units 1",
+		`{"currencyCode": "USD", "nanos": 1000000000}`:       "unparsable field Nanos: nanos 1000000000 are out of range, expected -999999999 to 999999999",
+	} {
+		require.EqualError(t, u.UnmarshalBytes([]byte(doc), &money.Money{}), want, doc)
+	}
+}
+
+func TestUnmarshal_AnyOfDate(t *testing.T) {
+	for _, doc := range []string{
+		`{"@type": "type.googleapis.com/google.type.Date", "value": "2024-03-01"}`,
+		`{"@type": "type.googleapis.com/google.type.Date", "year": 2024, "month": 3, "day": 1}`,
+	} {
+		a := &anypb.Any{}
+		require.NoError(t, nicejsonpb.UnmarshalString(doc, a), doc)
+		held, err := a.UnmarshalNew()
+		require.NoError(t, err)
+		require.Equal(t, int32(3), held.(*date.Date).Month, doc)
+	}
+}
@@ -0,0 +1,170 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// ErrUnsupportedMediaType is returned, wrapped, by DecodeRequest when the request body is declared as
+// something other than JSON or a form.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// maxFormMemory is how much of a multipart form DecodeRequest holds in memory, the rest of its files
+// being stored on disk, as with http.Request.FormValue.
+const maxFormMemory = 32 << 20
+
+// DecodeRequest unmarshals the body of an HTTP request into a protocol buffer. Requests without a
+// Content-Type are assumed to carry JSON. Forms, both application/x-www-form-urlencoded and
+// multipart/form-data, are decoded as by UnmarshalQuery, with the content of the files of multipart
+// forms setting bytes fields.
+func (u *Unmarshaler) DecodeRequest(r *http.Request, pb proto.Message) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		switch {
+		case err != nil:
+			return fmt.Errorf("%w %q, expected application/json or a form", ErrUnsupportedMediaType, ct)
+		case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+			return u.decodeForm(r, pb)
+		case mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json"):
+			return fmt.Errorf("%w %q, expected application/json or a form", ErrUnsupportedMediaType, ct)
+		}
+	}
+	if r.Body == nil {
+		return errors.New("request has no body")
+	}
+	return u.Unmarshal(r.Body, pb)
+}
+
+// decodeForm decodes the form posted in r into pb. The body, files included, is held to
+// MaxInputBytes.
+func (u *Unmarshaler) decodeForm(r *http.Request, pb proto.Message) error {
+	if r.Body != nil {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{u.LimitReader(r.Body), r.Body}
+	}
+	// ParseMultipartForm drops the errors of ParseForm for forms that aren't multipart.
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil && err != http.ErrNotMultipart {
+		return err
+	}
+	if r.MultipartForm != nil {
+		// Files beyond maxFormMemory are stored on disk until the form is removed.
+		defer r.MultipartForm.RemoveAll()
+	}
+	values := url.Values{}
+	for name, vals := range r.PostForm {
+		values[name] = append(values[name], vals...)
+	}
+	if r.MultipartForm != nil {
+		for name, files := range r.MultipartForm.File {
+			for _, fh := range files {
+				content, err := u.readFormFile(fh)
+				if err != nil {
+					return err
+				}
+				values[name] = append(values[name], u.EncodeBytes(content))
+			}
+		}
+	}
+	return u.UnmarshalQuery(values, pb)
+}
+
+// readFormFile reads the content of a file of a multipart form, of up to MaxInputBytes.
+func (u *Unmarshaler) readFormFile(fh *multipart.FileHeader) ([]byte, error) {
+	if u.MaxInputBytes > 0 && fh.Size > u.MaxInputBytes {
+		return nil, inputTooLarge(u.MaxInputBytes)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(u.LimitReader(f))
+}
+
+// DecodeRequest unmarshals the body of an HTTP request into a protocol buffer.
+func DecodeRequest(r *http.Request, pb proto.Message) error {
+	return new(Unmarshaler).DecodeRequest(r, pb)
+}
+
+// Problem is an RFC 7807 problem details object describing why a request couldn't be decoded.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam names a single field of the request that failed to decode.
+type InvalidParam struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// NewProblem converts an error returned by DecodeRequest into problem details, listing every field
+// error, such as each of a MultiError, in its invalid-params. Fields are named by the path of their
+// keys in the JSON, as in someEmbedded.identifier.
+func NewProblem(err error) *Problem {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		status = http.StatusUnsupportedMediaType
+	} else if errors.Is(err, ErrInputTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+	for _, fErr := range fieldErrors(err) {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{
+			Field:  strings.Join(fErr.pointerKeys(), "."),
+			Reason: fErr.nestedErr.Error(),
+		})
+	}
+	return p
+}
+
+// fieldErrors returns the field errors in err, which may be wrapped or hold several.
+func fieldErrors(err error) []*fieldError {
+	switch e := err.(type) {
+	case *fieldError:
+		return []*fieldError{e}
+	case interface{ Unwrap() []error }:
+		var fErrs []*fieldError
+		for _, err := range e.Unwrap() {
+			fErrs = append(fErrs, fieldErrors(err)...)
+		}
+		return fErrs
+	case interface{ Unwrap() error }:
+		return fieldErrors(e.Unwrap())
+	}
+	return nil
+}
+
+// WriteProblem writes err as an application/problem+json response.
+func WriteProblem(w http.ResponseWriter, err error) {
+	p := NewProblem(err)
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
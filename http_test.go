@@ -0,0 +1,145 @@
+package nicejsonpb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAndWriteProblem(contentType string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	if err := nicejsonpb.DecodeRequest(req, &validatortest.ValidatorMessage3{}); err != nil {
+		nicejsonpb.WriteProblem(rec, err)
+	}
+	return rec
+}
+
+func TestDecodeRequest_DecodesBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"someString": "foo"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	stuff := &validatortest.ValidatorMessage3{}
+	require.NoError(t, nicejsonpb.DecodeRequest(req, stuff))
+	require.Equal(t, "foo", stuff.SomeString)
+}
+
+func TestWriteProblem_ListsInvalidParams(t *testing.T) {
+	rec := decodeAndWriteProblem("application/json", `{"someEmbedded": {"identifier": 3.1}}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	problem := &nicejsonpb.Problem{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), problem))
+	require.Equal(t, []nicejsonpb.InvalidParam{
+		{Field: "someEmbedded.identifier", Reason: "json: cannot unmarshal number into Go value of type string"},
+	}, problem.InvalidParams)
+}
+
+func TestWriteProblem_UnsupportedMediaType(t *testing.T) {
+	rec := decodeAndWriteProblem("text/plain", `{}`)
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	problem := &nicejsonpb.Problem{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), problem))
+	require.Empty(t, problem.InvalidParams)
+}
+
+func TestDecodeRequest_URLEncodedForm(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?someString=ignored", strings.NewReader("someString=foo&someEmbedded.identifier=x&someStringRep=a&someStringRep=b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.DecodeRequest(req, m))
+	require.Equal(t, "foo", m.SomeString)
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+}
+
+func TestDecodeRequest_MultipartForm(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	require.NoError(t, w.WriteField("someInt32", "42"))
+	fw, err := w.CreateFormFile("someBytes", "upload.bin")
+	require.NoError(t, err)
+	fw.Write([]byte{0, 1, 2, 0xff})
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.DecodeRequest(req, m))
+	require.Equal(t, int32(42), m.SomeInt32)
+	require.Equal(t, []byte{0, 1, 2, 0xff}, m.SomeBytes)
+}
+
+func TestDecodeRequest_MultipartFormBytesEncoding(t *testing.T) {
+	for _, u := range []*nicejsonpb.Unmarshaler{
+		{BytesEncoding: nicejsonpb.BytesHex},
+		{BytesEncoding: nicejsonpb.BytesAuto},
+		{Profile: nicejsonpb.ProfileLenient},
+	} {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		fw, err := w.CreateFormFile("someBytes", "upload.bin")
+		require.NoError(t, err)
+		// In base64, "abcd", which also reads as hex.
+		fw.Write([]byte{0x69, 0xb7, 0x1d})
+		require.NoError(t, w.Close())
+
+		req := httptest.NewRequest("POST", "/", &body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		m := &validatortest.Message3{}
+		require.NoError(t, u.DecodeRequest(req, m))
+		require.Equal(t, []byte{0x69, 0xb7, 0x1d}, m.SomeBytes)
+	}
+}
+
+func TestWriteProblem_FormInvalidParams(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", strings.NewReader("someInt32=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err := nicejsonpb.DecodeRequest(req, &validatortest.Message3{})
+	require.Error(t, err)
+	nicejsonpb.WriteProblem(rec, err)
+	problem := &nicejsonpb.Problem{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), problem))
+	require.Len(t, problem.InvalidParams, 1)
+	require.Equal(t, "someInt32", problem.InvalidParams[0].Field)
+}
+
+func TestDecodeRequest_FormMaxInputBytes(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMaxInputBytes(1 << 10))
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fw, err := w.CreateFormFile("someBytes", "upload.bin")
+	require.NoError(t, err)
+	fw.Write(bytes.Repeat([]byte{0xff}, 4<<10))
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	err = u.DecodeRequest(req, &validatortest.Message3{})
+	require.ErrorIs(t, err, nicejsonpb.ErrInputTooLarge)
+	require.Equal(t, http.StatusRequestEntityTooLarge, nicejsonpb.NewProblem(err).Status)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("someString="+strings.Repeat("x", 4<<10)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	require.ErrorIs(t, u.DecodeRequest(req, &validatortest.Message3{}), nicejsonpb.ErrInputTooLarge)
+}
+
+func TestNewProblem_ListsAllFieldErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors())
+	err := u.Unmarshal(strings.NewReader(`{"someInt32": "x", "someEmbedded": {"someValue": true}, "someStringToInt64": {"a": "y"}}`), &validatortest.Message3{})
+	require.Error(t, err)
+	var fields []string
+	for _, param := range nicejsonpb.NewProblem(err).InvalidParams {
+		fields = append(fields, param.Field)
+	}
+	require.ElementsMatch(t, []string{"someInt32", "someEmbedded.someValue", "someStringToInt64.a"}, fields)
+}
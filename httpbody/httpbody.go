@@ -0,0 +1,111 @@
+// Package httpbody binds the body of an HTTP request, in any of the formats nicejsonpb decodes, to a
+// protocol buffer with one call:
+//
+//	if err := httpbody.DecodeBody(r, pb); err != nil {
+//		nicejsonpb.WriteProblem(w, err)
+//		return
+//	}
+//
+// The format is picked by the Content-Type of the request: JSON, which requests without one are
+// assumed to carry, NDJSON holding a single message, MessagePack, CBOR, binary protocol buffers and
+// forms. Bodies sent with a gzip, deflate or zstd Content-Encoding are decompressed, and
+// MaxInputBytes of the Unmarshaler limits the size of the body once decompressed.
+package httpbody
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/cbor"
+	"github.com/mwitkow/go-nicejsonpb/decompress"
+	"github.com/mwitkow/go-nicejsonpb/msgpack"
+)
+
+// DecodeBody decodes the body of r into pb with the default options.
+func DecodeBody(r *http.Request, pb proto.Message) error {
+	return DecodeBodyWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// DecodeBodyWith decodes the body of r into pb with the options of u. Bodies of an unknown media
+// type or content coding fail with nicejsonpb.ErrUnsupportedMediaType, and bodies larger than
+// MaxInputBytes with nicejsonpb.ErrInputTooLarge, both wrapped, for nicejsonpb.WriteProblem to
+// answer with the right status. Binary protocol buffers are decoded as by proto.Unmarshal, without
+// the options of u.
+func DecodeBodyWith(u *nicejsonpb.Unmarshaler, r *http.Request, pb proto.Message) error {
+	if r.Body == nil {
+		return errors.New("request has no body")
+	}
+	mediaType := "application/json"
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+			return unsupported(ct)
+		}
+	}
+	body, err := decompressBody(r)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	limited := u.LimitReader(body)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return u.Unmarshal(limited, pb)
+	case mediaType == "application/x-ndjson" || mediaType == "application/jsonl" || mediaType == "application/json-lines":
+		return decodeNDJSON(u, limited, pb)
+	case mediaType == "application/msgpack" || mediaType == "application/x-msgpack" || mediaType == "application/vnd.msgpack":
+		return msgpack.UnmarshalMsgpackWith(u, limited, pb)
+	case mediaType == "application/cbor":
+		return cbor.UnmarshalCBORWith(u, limited, pb)
+	case mediaType == "application/x-protobuf" || mediaType == "application/protobuf" || mediaType == "application/vnd.google.protobuf":
+		b, err := io.ReadAll(limited)
+		if err != nil {
+			return err
+		}
+		return proto.Unmarshal(b, pb)
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		form := r.Clone(r.Context())
+		form.Body = io.NopCloser(limited)
+		form.Header.Del("Content-Encoding")
+		return u.DecodeRequest(form, pb)
+	}
+	return unsupported(r.Header.Get("Content-Type"))
+}
+
+func unsupported(contentType string) error {
+	return fmt.Errorf("%w %q, expected JSON, NDJSON, MessagePack, CBOR, protobuf or a form", nicejsonpb.ErrUnsupportedMediaType, contentType)
+}
+
+// decompressBody returns the body of r, decompressed according to its Content-Encoding.
+func decompressBody(r *http.Request) (io.ReadCloser, error) {
+	body, err := decompress.ForEncoding(r.Header.Get("Content-Encoding"), r.Body)
+	if errors.Is(err, decompress.ErrUnknownEncoding) {
+		return nil, fmt.Errorf("%w: %v", nicejsonpb.ErrUnsupportedMediaType, err)
+	}
+	return body, err
+}
+
+// decodeNDJSON decodes the single message an NDJSON body holds into pb.
+func decodeNDJSON(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	dec := u.NewStreamDecoder(r)
+	if err := dec.Decode(pb); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	switch err := dec.Decode(proto.Clone(pb)); err {
+	case io.EOF:
+		return nil
+	case nil:
+		return fmt.Errorf("line %d: more than one message", dec.Line())
+	default:
+		return err
+	}
+}
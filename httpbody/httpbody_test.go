@@ -0,0 +1,95 @@
+package httpbody_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/httpbody"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func request(contentType, contentEncoding string, body io.Reader) *http.Request {
+	req := httptest.NewRequest("POST", "/", body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	return req
+}
+
+func gzipped(t *testing.T, s string) io.Reader {
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return &b
+}
+
+func TestDecodeBody(t *testing.T) {
+	packed, err := msgpack.Marshal(map[string]interface{}{"someString": "packed"})
+	require.NoError(t, err)
+	binary, err := proto.Marshal(&validatortest.Message3{SomeString: "binary"})
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name string
+		req  *http.Request
+		want string
+	}{
+		{"no content type", request("", "", strings.NewReader(`{"someString": "json"}`)), "json"},
+		{"json", request("application/json; charset=utf-8", "", strings.NewReader(`{"someString": "json"}`)), "json"},
+		{"gzipped json", request("application/json", "gzip", gzipped(t, `{"someString": "gzipped"}`)), "gzipped"},
+		{"ndjson", request("application/x-ndjson", "", strings.NewReader("\n{\"someString\": \"line\"}\n\n")), "line"},
+		{"msgpack", request("application/msgpack", "", bytes.NewReader(packed)), "packed"},
+		{"cbor", request("application/cbor", "", bytes.NewReader([]byte("\xa1\x6asomeString\x64cbor"))), "cbor"},
+		{"protobuf", request("application/x-protobuf", "", bytes.NewReader(binary)), "binary"},
+		{"form", request("application/x-www-form-urlencoded", "gzip", gzipped(t, "someString=form")), "form"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &validatortest.Message3{}
+			require.NoError(t, httpbody.DecodeBody(tc.req, m))
+			require.Equal(t, tc.want, m.SomeString)
+		})
+	}
+}
+
+func TestDecodeBody_Errors(t *testing.T) {
+	err := httpbody.DecodeBody(request("text/plain", "", strings.NewReader("x")), &validatortest.Message3{})
+	require.ErrorIs(t, err, nicejsonpb.ErrUnsupportedMediaType)
+	require.EqualError(t, err, `unsupported media type "text/plain", expected JSON, NDJSON, MessagePack, CBOR, protobuf or a form`)
+
+	err = httpbody.DecodeBody(request("application/json", "br", strings.NewReader("x")), &validatortest.Message3{})
+	require.ErrorIs(t, err, nicejsonpb.ErrUnsupportedMediaType)
+	require.EqualError(t, err, `unsupported media type: unknown content coding "br", expected gzip, deflate or zstd`)
+
+	err = httpbody.DecodeBody(request("application/x-ndjson", "", strings.NewReader("{}\n{}\n")), &validatortest.Message3{})
+	require.EqualError(t, err, "line 2: more than one message")
+
+	err = httpbody.DecodeBody(request("application/json", "", strings.NewReader(`{"someEmbedded": {"identifier": 3}}`)), &validatortest.Message3{})
+	require.Equal(t, "/someEmbedded/identifier", nicejsonpb.ErrorPointer(err))
+}
+
+func TestDecodeBody_MaxInputBytes(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMaxInputBytes(64))
+	bomb := `{"someString": "` + strings.Repeat("a", 1000) + `"}`
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded", "application/x-protobuf"} {
+		err := httpbody.DecodeBodyWith(u, request(contentType, "gzip", gzipped(t, bomb)), &validatortest.Message3{})
+		require.ErrorIs(t, err, nicejsonpb.ErrInputTooLarge, contentType)
+		require.Equal(t, nicejsonpb.CodeLimitExceeded, nicejsonpb.ErrorCode(err), contentType)
+	}
+	rec := httptest.NewRecorder()
+	nicejsonpb.WriteProblem(rec, httpbody.DecodeBodyWith(u, request("", "", strings.NewReader(bomb)), &validatortest.Message3{}))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.NoError(t, httpbody.DecodeBodyWith(u, request("", "gzip", gzipped(t, `{"someString": "small"}`)), &validatortest.Message3{}))
+}
@@ -0,0 +1,89 @@
+package nicejsonpb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// errStopDecoding ends DecodeArray when the consumer of Decode stops iterating.
+var errStopDecoding = errors.New("decoding stopped")
+
+// Decode lazily decodes the messages of r, which holds either a JSON array of messages or
+// newline-delimited JSON, using an Unmarshaler configured by opts. Messages are read as the loop
+// asks for them, so inputs of any size can be processed:
+//
+//	for msg, err := range nicejsonpb.Decode[*pb.Event](r) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An error is yielded once, after which the sequence ends. Errors are prefixed with the index of the
+// element or the number of the line, like with DecodeArray and StreamDecoder.
+func Decode[T proto.Message](r io.Reader, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		u := New(opts...)
+		br := bufio.NewReader(r)
+		var zero T
+		first, lines, err := peekNonSpace(br)
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			yield(zero, err)
+			return
+		}
+		if first == '[' {
+			err := u.DecodeArray(json.NewDecoder(br), func() proto.Message { return newMessage[T]() }, func(m proto.Message) error {
+				if !yield(m.(T), nil) {
+					return errStopDecoding
+				}
+				return nil
+			})
+			if err != nil && err != errStopDecoding {
+				yield(zero, err)
+			}
+			return
+		}
+		dec := u.NewStreamDecoder(br)
+		dec.line = lines
+		for {
+			m := newMessage[T]()
+			if err := dec.Decode(m); err == io.EOF {
+				return
+			} else if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+// peekNonSpace skips the JSON whitespace at the start of br and returns the byte that follows,
+// without consuming it, and the number of lines skipped.
+func peekNonSpace(br *bufio.Reader) (byte, int, error) {
+	lines := 0
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, lines, err
+		}
+		switch b[0] {
+		case '\n':
+			lines++
+			fallthrough
+		case ' ', '\t', '\r':
+			br.ReadByte()
+		default:
+			return b[0], lines, nil
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func collectDecoded(input string, opts ...nicejsonpb.Option) ([]string, error) {
+	var names []string
+	for m, err := range nicejsonpb.Decode[*validatortest.Message3](strings.NewReader(input), opts...) {
+		if err != nil {
+			return names, err
+		}
+		names = append(names, m.SomeString)
+	}
+	return names, nil
+}
+
+func TestDecode_Array(t *testing.T) {
+	names, err := collectDecoded(` [{"someString": "a"}, {"someString": "b"}]`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+
+	names, err = collectDecoded(`[{"someString": "a"}, {"someInt64": "x"}, {"someString": "c"}]`)
+	require.Equal(t, []string{"a"}, names)
+	require.EqualError(t, err, "unparsable field [1].SomeInt64: invalid character 'x' looking for beginning of value while looking for an integer in a string")
+}
+
+func TestDecode_NDJSON(t *testing.T) {
+	names, err := collectDecoded("\n{\"someString\": \"a\"}\n\n{\"someString\": \"b\"}\n")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+
+	_, err = collectDecoded("\n{\"someString\": \"a\"}\n{\"unknown\": 1}\n")
+	require.ErrorContains(t, err, "line 3: ")
+	names, err = collectDecoded("{\"someString\": \"a\"}\n{\"unknown\": 1}\n", nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", ""}, names)
+}
+
+func TestDecode_StopsEarly(t *testing.T) {
+	input := `[{"someString": "a"}, {"someString": "b"}, {"someInt32": "x"}]`
+	for m, err := range nicejsonpb.Decode[*validatortest.Message3](strings.NewReader(input)) {
+		require.NoError(t, err)
+		require.Equal(t, "a", m.SomeString)
+		break
+	}
+	for range nicejsonpb.Decode[*validatortest.Message3](strings.NewReader("  ")) {
+		t.Fatal("empty input yields nothing")
+	}
+}
@@ -0,0 +1,494 @@
+// Package json5 decodes JSON5 (https://json5.org) documents, as hand-written configuration files
+// often are, into protocol buffers. Documents are converted to plain JSON and decoded by a
+// nicejsonpb.Unmarshaler, so fields are decoded and errors reported exactly as for JSON:
+//
+//	err := json5.UnmarshalJSON5(r, pb)
+//
+// JSON5 adds comments, unquoted keys, single-quoted and multi-line strings, hexadecimal numbers,
+// numbers with a leading plus sign or a leading or trailing decimal point, Infinity and NaN, and
+// trailing commas. Infinity and NaN become the strings "Infinity", "-Infinity" and "NaN", which is
+// how float and double fields hold them in JSON.
+package json5
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+)
+
+// UnmarshalJSON5 decodes the JSON5 document held in r into pb with the default options.
+func UnmarshalJSON5(r io.Reader, pb proto.Message) error {
+	return UnmarshalJSON5With(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalJSON5With decodes the JSON5 document held in r into pb with the options of u.
+func UnmarshalJSON5With(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	doc, err := ToJSON(b)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBytes(doc, pb)
+}
+
+// SyntaxError is returned for documents that aren't valid JSON5.
+type SyntaxError struct {
+	// Line and Column, both starting at 1, locate the problem. Columns count characters.
+	Line, Column int
+	Msg          string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("json5: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ToJSON converts the JSON5 document b to JSON.
+func ToJSON(b []byte) ([]byte, error) {
+	c := &converter{in: b}
+	if err := c.value(); err != nil {
+		return nil, err
+	}
+	if err := c.space(); err != nil {
+		return nil, err
+	}
+	if c.pos < len(c.in) {
+		return nil, c.errorf("unexpected %s after the document", c.describe())
+	}
+	return c.out.Bytes(), nil
+}
+
+// converter converts a JSON5 document to JSON as it goes through it.
+type converter struct {
+	in  []byte
+	pos int
+	out bytes.Buffer
+}
+
+func (c *converter) errorf(format string, args ...interface{}) error {
+	line, col := 1, 1
+	for _, r := range string(c.in[:c.pos]) {
+		if r == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describe names what is at the current position, for errors.
+func (c *converter) describe() string {
+	if c.pos >= len(c.in) {
+		return "end of document"
+	}
+	r, _ := utf8.DecodeRune(c.in[c.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (c *converter) peek() rune {
+	if c.pos >= len(c.in) {
+		return -1
+	}
+	r, _ := utf8.DecodeRune(c.in[c.pos:])
+	return r
+}
+
+func (c *converter) next() rune {
+	r, size := utf8.DecodeRune(c.in[c.pos:])
+	c.pos += size
+	return r
+}
+
+// space skips whitespace and comments.
+func (c *converter) space() error {
+	for c.pos < len(c.in) {
+		switch r := c.peek(); {
+		case r == '/' && bytes.HasPrefix(c.in[c.pos:], []byte("//")):
+			for c.pos < len(c.in) && !isLineTerminator(c.peek()) {
+				c.next()
+			}
+		case r == '/' && bytes.HasPrefix(c.in[c.pos:], []byte("/*")):
+			end := bytes.Index(c.in[c.pos+2:], []byte("*/"))
+			if end < 0 {
+				return c.errorf("unterminated comment")
+			}
+			c.pos += end + 4
+		case r == '\ufeff' || unicode.Is(unicode.Zs, r) || strings.ContainsRune("\t\n\v\f\r", r) || isLineTerminator(r):
+			c.next()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func isLineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+func (c *converter) value() error {
+	if err := c.space(); err != nil {
+		return err
+	}
+	switch r := c.peek(); {
+	case r == '{':
+		return c.object()
+	case r == '[':
+		return c.array()
+	case r == '"' || r == '\'':
+		s, err := c.str()
+		if err != nil {
+			return err
+		}
+		c.writeString(s)
+		return nil
+	case r == '-' || r == '+' || r == '.' || r == 'I' || r == 'N' || ('0' <= r && r <= '9'):
+		return c.number()
+	case isIdentifierStart(r):
+		start := c.pos
+		word, err := c.identifier()
+		if err != nil {
+			return err
+		}
+		if word != "true" && word != "false" && word != "null" {
+			c.pos = start
+			return c.errorf("unexpected identifier %q", word)
+		}
+		c.out.WriteString(word)
+		return nil
+	}
+	return c.errorf("unexpected %s looking for a value", c.describe())
+}
+
+func (c *converter) object() error {
+	c.next()
+	c.out.WriteByte('{')
+	for first := true; ; first = false {
+		if err := c.space(); err != nil {
+			return err
+		}
+		if c.peek() == '}' {
+			c.next()
+			c.out.WriteByte('}')
+			return nil
+		}
+		if !first {
+			c.out.WriteByte(',')
+		}
+		var key string
+		var err error
+		if r := c.peek(); r == '"' || r == '\'' {
+			key, err = c.str()
+		} else if isIdentifierStart(r) || r == '\\' {
+			key, err = c.identifier()
+		} else {
+			err = c.errorf("unexpected %s looking for an object key", c.describe())
+		}
+		if err != nil {
+			return err
+		}
+		c.writeString(key)
+		if err := c.space(); err != nil {
+			return err
+		}
+		if c.peek() != ':' {
+			return c.errorf("unexpected %s after object key", c.describe())
+		}
+		c.next()
+		c.out.WriteByte(':')
+		if err := c.value(); err != nil {
+			return err
+		}
+		if err := c.space(); err != nil {
+			return err
+		}
+		switch c.peek() {
+		case ',':
+			c.next()
+		case '}':
+		default:
+			return c.errorf("unexpected %s after object value", c.describe())
+		}
+	}
+}
+
+func (c *converter) array() error {
+	c.next()
+	c.out.WriteByte('[')
+	for first := true; ; first = false {
+		if err := c.space(); err != nil {
+			return err
+		}
+		if c.peek() == ']' {
+			c.next()
+			c.out.WriteByte(']')
+			return nil
+		}
+		if !first {
+			c.out.WriteByte(',')
+		}
+		if err := c.value(); err != nil {
+			return err
+		}
+		if err := c.space(); err != nil {
+			return err
+		}
+		switch c.peek() {
+		case ',':
+			c.next()
+		case ']':
+		default:
+			return c.errorf("unexpected %s after array element", c.describe())
+		}
+	}
+}
+
+// writeString writes s as a JSON string.
+func (c *converter) writeString(s string) {
+	enc := json.NewEncoder(&c.out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	c.out.Truncate(c.out.Len() - 1) // Encode ends with a newline.
+}
+
+// str reads a single- or double-quoted string.
+func (c *converter) str() (string, error) {
+	start := c.pos
+	quote := c.next()
+	var sb strings.Builder
+	for {
+		if c.pos >= len(c.in) {
+			c.pos = start
+			return "", c.errorf("unterminated string")
+		}
+		r := c.next()
+		switch {
+		case r == quote:
+			return sb.String(), nil
+		case r == '\n' || r == '\r':
+			c.pos--
+			return "", c.errorf("line break in string, escape it with a backslash")
+		case r == '\\':
+			if err := c.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// escape reads the escape sequence after a backslash in a string into sb.
+func (c *converter) escape(sb *strings.Builder) error {
+	if c.pos >= len(c.in) {
+		return c.errorf("unterminated string")
+	}
+	r := c.next()
+	switch r {
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '0':
+		if p := c.peek(); '0' <= p && p <= '9' {
+			return c.errorf("octal escapes are not allowed")
+		}
+		sb.WriteByte(0)
+	case 'x':
+		n, err := c.hex(2)
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(rune(n))
+	case 'u':
+		n, err := c.hex(4)
+		if err != nil {
+			return err
+		}
+		if 0xD800 <= n && n < 0xDC00 && bytes.HasPrefix(c.in[c.pos:], []byte(`\u`)) {
+			save := c.pos
+			c.pos += 2
+			if low, err := c.hex(4); err == nil && 0xDC00 <= low && low < 0xE000 {
+				n = 0x10000 + (n-0xD800)<<10 + (low - 0xDC00)
+			} else {
+				c.pos = save
+			}
+		}
+		sb.WriteRune(rune(n))
+	case '\r':
+		// A line continuation, which adds nothing to the string.
+		if c.peek() == '\n' {
+			c.next()
+		}
+	case '\n', '\u2028', '\u2029':
+	default:
+		if '1' <= r && r <= '9' {
+			return c.errorf("octal escapes are not allowed")
+		}
+		sb.WriteRune(r)
+	}
+	return nil
+}
+
+// hex reads n hexadecimal digits.
+func (c *converter) hex(n int) (int, error) {
+	if c.pos+n > len(c.in) {
+		return 0, c.errorf("truncated escape sequence")
+	}
+	v, err := strconv.ParseUint(string(c.in[c.pos:c.pos+n]), 16, 32)
+	if err != nil {
+		return 0, c.errorf("bad escape sequence")
+	}
+	c.pos += n
+	return int(v), nil
+}
+
+// number reads a number, converting it to the JSON form of the same value.
+func (c *converter) number() error {
+	start := c.pos
+	sign := ""
+	switch c.peek() {
+	case '-':
+		sign = "-"
+		c.next()
+	case '+':
+		c.next()
+	}
+	rest := c.in[c.pos:]
+	for _, word := range []string{"Infinity", "NaN"} {
+		if bytes.HasPrefix(rest, []byte(word)) {
+			c.pos += len(word)
+			if word == "NaN" {
+				sign = ""
+			}
+			c.writeString(sign + word)
+			return c.endOfNumber(start)
+		}
+	}
+	if bytes.HasPrefix(rest, []byte("0x")) || bytes.HasPrefix(rest, []byte("0X")) {
+		c.pos += 2
+		digits := c.pos
+		for c.pos < len(c.in) && isHexDigit(c.in[c.pos]) {
+			c.pos++
+		}
+		n, ok := new(big.Int).SetString(string(c.in[digits:c.pos]), 16)
+		if !ok {
+			return c.errorf("bad hexadecimal number")
+		}
+		c.out.WriteString(sign + n.String())
+		return c.endOfNumber(start)
+	}
+	intPart := c.digits()
+	frac := ""
+	if c.peek() == '.' {
+		c.next()
+		frac = c.digits()
+	}
+	if intPart == "" && frac == "" {
+		c.pos = start
+		return c.errorf("bad number")
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if len(intPart) > 1 && intPart[0] == '0' {
+		c.pos = start
+		return c.errorf("numbers can't have leading zeros")
+	}
+	c.out.WriteString(sign + intPart)
+	if frac != "" {
+		c.out.WriteString("." + frac)
+	}
+	if p := c.peek(); p == 'e' || p == 'E' {
+		c.next()
+		exp := "e"
+		if p := c.peek(); p == '+' || p == '-' {
+			exp += string(c.next())
+		}
+		digits := c.digits()
+		if digits == "" {
+			return c.errorf("bad exponent")
+		}
+		c.out.WriteString(exp + digits)
+	}
+	return c.endOfNumber(start)
+}
+
+// endOfNumber checks that the number that started at start isn't followed by more of a word.
+func (c *converter) endOfNumber(start int) error {
+	if r := c.peek(); isIdentifierPart(r) || r == '.' {
+		c.pos = start
+		return c.errorf("bad number")
+	}
+	return nil
+}
+
+func (c *converter) digits() string {
+	start := c.pos
+	for c.pos < len(c.in) && '0' <= c.in[c.pos] && c.in[c.pos] <= '9' {
+		c.pos++
+	}
+	return string(c.in[start:c.pos])
+}
+
+func isHexDigit(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F'
+}
+
+// identifier reads an ECMAScript 5.1 IdentifierName, such as an unquoted key.
+func (c *converter) identifier() (string, error) {
+	var sb strings.Builder
+	for first := true; c.pos < len(c.in); first = false {
+		r := c.peek()
+		if r == '\\' {
+			c.next()
+			if c.peek() != 'u' {
+				return "", c.errorf("bad escape sequence in identifier")
+			}
+			c.next()
+			n, err := c.hex(4)
+			if err != nil {
+				return "", err
+			}
+			r = rune(n)
+			if !(first && isIdentifierStart(r) || !first && isIdentifierPart(r)) {
+				return "", c.errorf("escape of %q in identifier", r)
+			}
+			sb.WriteRune(r)
+			continue
+		}
+		if first && !isIdentifierStart(r) || !first && !isIdentifierPart(r) {
+			break
+		}
+		sb.WriteRune(c.next())
+	}
+	return sb.String(), nil
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) || r == '\u200c' || r == '\u200d'
+}
@@ -0,0 +1,73 @@
+package json5_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/json5"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `// Written by hand.
+{
+	someString: 'it\'s "quoted" \
+and continued',
+	some_int32: +0x1F,
+	someDouble: .5,
+	someFloat: -Infinity,
+	"someStringRep": ['a', "b",],
+	someEmbedded: {identifier: 'x', someValue: 5.,}, /* trailing */
+}
+`
+
+func TestUnmarshalJSON5(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, json5.UnmarshalJSON5(strings.NewReader(config), m))
+	require.Equal(t, `it's "quoted" and continued`, m.SomeString)
+	require.Equal(t, int32(31), m.SomeInt32)
+	require.Equal(t, 0.5, m.SomeDouble)
+	require.True(t, m.SomeFloat < 0 && m.SomeFloat*2 == m.SomeFloat)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Equal(t, int64(5), m.SomeEmbedded.SomeValue)
+}
+
+func TestUnmarshalJSON5_FieldErrors(t *testing.T) {
+	err := json5.UnmarshalJSON5(strings.NewReader(`{someEmbedded: {someValue: 'x'}}`), &validatortest.Message3{})
+	want := nicejsonpb.UnmarshalString(`{"someEmbedded": {"someValue": "x"}}`, &validatortest.Message3{})
+	require.EqualError(t, err, want.Error())
+
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, json5.UnmarshalJSON5With(u, strings.NewReader(`{unknown: 1}`), &validatortest.Message3{}))
+}
+
+func TestToJSON_SyntaxErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		"{a: 1,\n b: 'x}":    "json5: line 2, column 5: unterminated string",
+		"{a: 01}":            "json5: line 1, column 5: numbers can't have leading zeros",
+		"[1 2]":              "json5: line 1, column 4: unexpected '2' after array element",
+		"{a: 1} x":           "json5: line 1, column 8: unexpected 'x' after the document",
+		"{a: undefined}":     `json5: line 1, column 5: unexpected identifier "undefined"`,
+		"/* never closed {}": "json5: line 1, column 1: unterminated comment",
+		"{'a\n': 1}":         "json5: line 1, column 4: line break in string, escape it with a backslash",
+	} {
+		_, err := json5.ToJSON([]byte(input))
+		require.EqualError(t, err, want, input)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	for input, want := range map[string]string{
+		`{$a_1: NaN, 'b': +Infinity}`: `{"$a_1":"NaN","b":"Infinity"}`,
+		`[0xFFFFFFFFFFFFFFFF, -0x10]`: `[18446744073709551615,-16]`,
+		`['\x41é😀']`:                  `["Aé😀"]`,
+		`[1.5e+3, 2E-1, 0, null]`:     `[1.5e+3,2e-1,0,null]`,
+		"\ufeff {\n}\u2028":           `{}`,
+	} {
+		got, err := json5.ToJSON([]byte(input))
+		require.NoError(t, err, input)
+		require.Equal(t, want, string(got), input)
+	}
+}
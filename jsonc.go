@@ -0,0 +1,101 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// allowsJSONC reports whether documents need blankJSONC before being decoded.
+func (u *Unmarshaler) allowsJSONC() bool {
+	return u.AllowComments || u.AllowTrailingCommas
+}
+
+// jsoncReader is blankJSONC for a document held in r, which it reads whole.
+func (u *Unmarshaler) jsoncReader(r io.Reader) (io.Reader, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = u.blankJSONC(b); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// blankJSONC overwrites the comments and trailing commas of b that AllowComments and
+// AllowTrailingCommas let through with spaces, turning it into plain JSON with all of its bytes
+// still at the same offsets, and its lines at the same line numbers, for error messages. b is
+// modified in place and returned.
+func (u *Unmarshaler) blankJSONC(b []byte) ([]byte, error) {
+	if u.AllowComments {
+		if err := blankComments(b); err != nil {
+			return nil, err
+		}
+	}
+	if u.AllowTrailingCommas {
+		blankTrailingCommas(b)
+	}
+	return b, nil
+}
+
+// blankComments overwrites the // and /* */ comments of b with spaces, keeping line breaks. It
+// fails on a /* comment that is never closed.
+func blankComments(b []byte) error {
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '"':
+			i = stringEnd(b, i)
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			start := i
+			end := bytes.Index(b[i+2:], []byte("*/"))
+			if end < 0 {
+				return codeErrorf(CodeSyntax, "unterminated comment at offset %d", start)
+			}
+			for end = i + 2 + end + 2; i < end; i++ {
+				if b[i] != '\n' && b[i] != '\r' {
+					b[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return nil
+}
+
+// blankTrailingCommas overwrites with a space every comma of b that is followed by nothing but
+// whitespace before the end of an array or object.
+func blankTrailingCommas(b []byte) {
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '"':
+			i = stringEnd(b, i)
+		case ',':
+			j := i + 1
+			for j < len(b) && strings.IndexByte(jsonWhitespace, b[j]) >= 0 {
+				j++
+			}
+			if j < len(b) && (b[j] == ']' || b[j] == '}') {
+				b[i] = ' '
+			}
+		}
+	}
+}
+
+// stringEnd returns the offset of the closing quote of the JSON string starting at offset start of
+// b, or the last offset of b if it has none.
+func stringEnd(b []byte, start int) int {
+	for i := start + 1; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(b) - 1
+}
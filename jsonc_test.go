@@ -0,0 +1,58 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+const jsoncInput = `{
+	// The name, with a "quoted" // that isn't a comment.
+	"someString": "a // b /* c */", /* inline */
+	"someStringRep": [
+		"x",
+		"y", // last
+	],
+	"someInt32": 3,
+}`
+
+func TestUnmarshal_AllowCommentsAndTrailingCommas(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllowComments(), nicejsonpb.WithAllowTrailingCommas())
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(jsoncInput), m))
+	require.Equal(t, "a // b /* c */", m.SomeString)
+	require.Equal(t, []string{"x", "y"}, m.SomeStringRep)
+	require.Equal(t, int32(3), m.SomeInt32)
+
+	input := []byte(jsoncInput)
+	require.NoError(t, u.UnmarshalBytes(input, &validatortest.Message3{}))
+	require.Equal(t, jsoncInput, string(input))
+
+	_, err := u.UnmarshalDynamic(strings.NewReader(jsoncInput), message3Descriptor(t))
+	require.NoError(t, err)
+
+	require.Error(t, nicejsonpb.UnmarshalString(jsoncInput, &validatortest.Message3{}))
+	err = nicejsonpb.New(nicejsonpb.WithAllowComments()).Unmarshal(strings.NewReader(jsoncInput), &validatortest.Message3{})
+	require.EqualError(t, err, "invalid character ']' looking for beginning of value")
+}
+
+func TestUnmarshal_CommentsKeepOffsets(t *testing.T) {
+	input := "/* header */ {\"someInt32\": 1 2}"
+	err := nicejsonpb.New(nicejsonpb.WithAllowComments()).Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	var syntaxErr *json.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, int64(strings.LastIndex(input, "2")+1), syntaxErr.Offset)
+}
+
+func TestUnmarshal_UnterminatedComment(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllowComments())
+	input := `{"someString": "a"} /* unterminated`
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, "unterminated comment at offset 20")
+	require.Equal(t, nicejsonpb.CodeSyntax, nicejsonpb.ErrorCode(err))
+	require.EqualError(t, u.UnmarshalBytes([]byte(input), &validatortest.Message3{}), "unterminated comment at offset 20")
+}
@@ -0,0 +1,28 @@
+// Package jsoniterbackend provides a nicejsonpb.Backend built on github.com/json-iterator/go, which
+// splits JSON objects and arrays several times faster than encoding/json:
+//
+//	u := nicejsonpb.New(nicejsonpb.WithBackend(jsoniterbackend.Backend))
+package jsoniterbackend
+
+import (
+	"encoding/json"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Backend splits JSON with json-iterator, configured to match encoding/json.
+var Backend = backend{api: jsoniter.ConfigCompatibleWithStandardLibrary}
+
+type backend struct {
+	api jsoniter.API
+}
+
+func (b backend) SplitObject(raw []byte, fields map[string]json.RawMessage) error {
+	return b.api.Unmarshal(raw, &fields)
+}
+
+func (b backend) SplitArray(raw []byte) ([]json.RawMessage, error) {
+	var slc []json.RawMessage
+	err := b.api.Unmarshal(raw, &slc)
+	return slc, err
+}
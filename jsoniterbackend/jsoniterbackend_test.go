@@ -0,0 +1,35 @@
+package jsoniterbackend_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/jsoniterbackend"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_MatchesEncodingJSON(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithBackend(jsoniterbackend.Backend))
+	for _, input := range []string{
+		`{"someString": "aé", "someStringRep": ["x", "y"], "someEmbedded": {"children": [{"identifier": "c"}]}}`,
+		`{"someStringToInt64": {"a": "1", "b\"q": 2}, "someInt32ToString": {"-1": "x"}}`,
+		`{"someString": "a", "someString": "b"}`,
+		`{"someStringRep": "x"}`,
+		`{"someEmbedded": [1]}`,
+		`{"someStringToInt64": {"a": true}}`,
+		`{"unknown": 1}`,
+	} {
+		want, got := &validatortest.Message3{}, &validatortest.Message3{}
+		wantErr := nicejsonpb.UnmarshalString(input, want)
+		err := u.Unmarshal(strings.NewReader(input), got)
+		if wantErr != nil {
+			require.EqualError(t, err, wantErr.Error(), input)
+			continue
+		}
+		require.NoError(t, err, input)
+		require.True(t, proto.Equal(want, got), input)
+	}
+}
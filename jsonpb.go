@@ -32,42 +32,427 @@
 package nicejsonpb
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // Unmarshaler is a configurable object for converting from a JSON
 // representation to a protocol buffer object.
+//
+// An Unmarshaler is safe for concurrent use by multiple goroutines: the state
+// of every call is kept apart, in pooled buffers. Its fields must not be
+// changed, nor type handlers registered, while it is in use though; derive
+// variants with With instead.
 type Unmarshaler struct {
 	// Whether to allow messages to contain unknown fields, as opposed to
 	// failing to unmarshal.
 	AllowUnknownFields bool
+
+	// UnknownFieldSink, if set, receives all unknown fields instead of them
+	// failing the unmarshal or being dropped. path is the path of the message
+	// holding the field, as used in field errors ("" at the top level), key
+	// is its JSON key and raw its undecoded value.
+	UnknownFieldSink func(path string, key string, raw json.RawMessage)
+
+	// FieldFilter, if set, limits the fields that may be set from the JSON
+	// to those it returns true for, the others being treated as unknown
+	// fields. path is the proto names of the field and the fields leading to
+	// it joined by dots, as in a FieldMask, for example
+	// "some_embedded.identifier", with extensions named in brackets. Lists
+	// and maps are passed through, so the fields of their messages have the
+	// path of the list or map as prefix. See AllowFields.
+	FieldFilter func(path string) bool
+
+	// UnknownFieldPolicy, if set, decides what happens to every unknown
+	// field instead of AllowUnknownFields and UnknownFieldSink alone, so
+	// that for example unknown fields are only tolerated in some subtree.
+	// path and key are as for UnknownFieldSink.
+	UnknownFieldPolicy func(path string, key string) UnknownFieldAction
+
+	// OnWarning, if set, is called for conditions that are tolerated but
+	// suspicious, such as unknown fields being let through or deprecated
+	// fields being set.
+	OnWarning func(Warning)
+
+	// NullHandling controls how fields set to JSON null are treated. By
+	// default they are left unset.
+	NullHandling NullHandling
+
+	// MergeInto preserves the fields already set in the target message,
+	// overwriting only those present in the JSON: nested messages are merged
+	// and map entries added. Otherwise the message is Reset first.
+	MergeInto bool
+	// AppendRepeated makes MergeInto append the elements of repeated fields
+	// to the existing ones instead of replacing them.
+	AppendRepeated bool
+
+	// IgnoreUnknownEnumValues decodes enum names that aren't known, for
+	// example ones added in a newer version of the schema, as the zero value
+	// of the enum instead of failing. Unknown numeric values are always
+	// accepted, as proto3 enums are open.
+	IgnoreUnknownEnumValues bool
+	// LenientEnumNames matches enum names ignoring case, and with the
+	// prefix shared by the values of the enum being optional: "active",
+	// "ACTIVE" and "STATUS_ACTIVE" all decode as STATUS_ACTIVE of enum
+	// Status.
+	LenientEnumNames bool
+
+	// CoerceFloatsToInts truncates fractional numbers sent for integer
+	// fields towards zero, reporting a warning, instead of failing.
+	CoerceFloatsToInts bool
+
+	// RejectNonFiniteFloats fails the unmarshal for the "NaN", "Infinity"
+	// and "-Infinity" values of float and double fields.
+	RejectNonFiniteFloats bool
+
+	// AcceptStringNumbers accepts numbers encoded as strings for all numeric
+	// fields, as sent by form-based frontends, and not only for 64-bit
+	// integers as the proto3 JSON mapping does.
+	AcceptStringNumbers bool
+
+	// CoerceStringBools accepts the strings "true", "false", "1" and "0"
+	// for bool fields, as sent in query strings, and not only the JSON
+	// literals true and false.
+	CoerceStringBools bool
+
+	// EmptyStringAsZero decodes "" sent for numeric, bool and enum fields as
+	// their zero value instead of failing, as HTML forms send it for inputs
+	// left untouched.
+	EmptyStringAsZero bool
+
+	// ExactNumbers fails the unmarshal for numbers that float and double
+	// fields can't hold without rounding them to fewer significant digits,
+	// such as 9007199254740993 for a double. Integer fields always take
+	// numbers exactly, or fail.
+	ExactNumbers bool
+
+	// RejectInvalidUTF8 fails the unmarshal for string fields holding
+	// invalid UTF-8, which encoding/json otherwise replaces with U+FFFD
+	// silently. Escapes of lone UTF-16 surrogates count as invalid.
+	RejectInvalidUTF8 bool
+	// RepairInvalidUTF8 replaces invalid UTF-8 with U+FFFD, reporting a
+	// warning, even when RejectInvalidUTF8 is set, for pipelines that
+	// rather keep a damaged string than lose the document.
+	RepairInvalidUTF8 bool
+
+	// RejectControlChars fails the unmarshal for string fields holding NUL
+	// or any other C0 control character but tab, line feed and carriage
+	// return, which break many databases and are almost always the result
+	// of a bug in the client.
+	RejectControlChars bool
+
+	// BytesEncoding selects how bytes fields are decoded, base64 by default.
+	BytesEncoding BytesEncoding
+
+	// WrapSingleValues decodes a value that isn't an array, sent for a
+	// repeated field, as its only element, the way legacy APIs and
+	// documents converted from XML send lists of one.
+	WrapSingleValues bool
+
+	// SkipNullElements drops null elements of repeated fields instead of
+	// failing the unmarshal.
+	SkipNullElements bool
+
+	// AllowOneofConflicts lets JSON set several members of the same oneof,
+	// the one with the highest field number winning, instead of failing.
+	AllowOneofConflicts bool
+
+	// CheckRequiredFields fails the unmarshal with a MultiError listing all
+	// the proto2 required fields left unset, rather than leaving them to be
+	// found when the message is marshaled.
+	CheckRequiredFields bool
+
+	// ApplyDefaults sets the proto2 fields absent from the JSON that have a
+	// declared default to that value, as they would read through their
+	// getters, so that they are present in the message.
+	ApplyDefaults bool
+
+	// EnforceRequiredBehavior fails the unmarshal with a MultiError listing
+	// all the fields annotated with google.api.field_behavior REQUIRED that
+	// are absent from the JSON or null.
+	EnforceRequiredBehavior bool
+
+	// OutputOnly selects what happens to fields annotated with
+	// google.api.field_behavior OUTPUT_ONLY that are set in the JSON.
+	OutputOnly OutputOnlyHandling
+
+	// RejectImmutableUpdates fails the unmarshal, when used with MergeInto
+	// to apply an update, if the JSON sets a field annotated with
+	// google.api.field_behavior IMMUTABLE that is already set in the
+	// message.
+	RejectImmutableUpdates bool
+
+	// MaxDepth limits how deeply messages may be nested in the JSON, failing
+	// the unmarshal beyond it. Zero means no limit.
+	MaxDepth int
+
+	// MaxNodes limits the total number of messages, list elements and map
+	// entries in the JSON, failing the unmarshal beyond it. Together with
+	// MaxDepth it bounds the memory taken by hostile documents. Zero means
+	// no limit.
+	MaxNodes int
+
+	// MaxInputBytes limits the size of the document, failing the unmarshal
+	// with ErrInputTooLarge beyond it. It applies to what Unmarshal reads,
+	// once decoded from UTF-16 or UTF-32, and to what UnmarshalBytes is given.
+	// Zero means no limit.
+	MaxInputBytes int64
+
+	// AllErrors keeps decoding past fields that fail, reporting all their
+	// errors at once in a MultiError instead of stopping at the first one.
+	AllErrors bool
+
+	// MaxErrors limits how many errors AllErrors reports, the ones beyond
+	// it being counted by a MoreErrors ending the MultiError instead. Zero
+	// means no limit.
+	MaxErrors int
+
+	// DisallowTrailingData fails the unmarshal when anything but whitespace
+	// follows the JSON document, such as a second document. It doesn't
+	// apply to UnmarshalNext, which reads from a stream of documents.
+	DisallowTrailingData bool
+
+	// AllowComments and AllowTrailingCommas accept documents with // and
+	// /* */ comments, and with commas after the last element of arrays and
+	// objects, as in hand-written configuration files. They are blanked
+	// out before decoding, so offsets in syntax errors still point into the
+	// document as it was written. They don't apply to UnmarshalNext.
+	AllowComments       bool
+	AllowTrailingCommas bool
+
+	// InputEncoding selects how the bytes of documents are turned into
+	// text. By default UTF-16 and UTF-32 documents, as often written on
+	// Windows, are transcoded, and byte order marks are dropped. It doesn't
+	// apply to UnmarshalNext, which reads from a json.Decoder.
+	InputEncoding InputEncoding
+
+	// ErrorFormatter, if set, produces the messages of the errors of the
+	// unmarshal instead of DefaultErrorFormatter, for example to present
+	// them in the language of the user.
+	ErrorFormatter ErrorFormatter
+
+	// RedactValues replaces the values from the JSON that errors and
+	// warnings would quote, such as unknown enum names or numbers out of
+	// range, with a placeholder giving only their kind and length, so that
+	// errors can be returned to clients or logged without leaking
+	// credentials or personal data. Field paths, including map keys, are
+	// kept as they are.
+	RedactValues bool
+
+	// SensitiveFields picks the fields, besides those annotated with the
+	// debug_redact field option, whose values are redacted the way
+	// RedactValues redacts all values, along with everything nested in
+	// them. Their values are also left out of ErrorRaw and handed to
+	// UnknownFieldSink as nil. SensitiveExtension builds one for a custom
+	// field option.
+	SensitiveFields func(fd protoreflect.FieldDescriptor) bool
+
+	// Coercer, if set, rewrites the values of scalar fields before they are
+	// decoded, to accept forms of them beyond those the options above do.
+	Coercer Coercer
+
+	// TypeResolver, if set, looks up the message types named by
+	// UnmarshalByName and by the "@type" of Anys, instead of
+	// protoregistry.GlobalTypes.
+	TypeResolver TypeResolver
+
+	// CheckStructAnys checks the objects with an "@type" member found in
+	// google.protobuf.Struct and Value fields the way the Anys they stand
+	// for would be decoded, reporting unknown types and bad fields. The
+	// objects are still kept in the Struct as they are.
+	CheckStructAnys bool
+
+	// MoneyStrings accepts google.type.Money values written as an amount
+	// followed by a currency code, such as "12.99 USD", besides their fields.
+	MoneyStrings bool
+
+	// TimestampOffsets controls how Timestamps written with an offset from
+	// UTC are treated. By default they are decoded to the instant they stand
+	// for.
+	TimestampOffsets TimestampOffsets
+
+	// EpochTimestamps makes Timestamps also take numbers of seconds or
+	// milliseconds since the Unix epoch. By default only RFC 3339 strings
+	// are taken.
+	EpochTimestamps EpochTimestamps
+
+	// DurationNumbers makes Durations also take numbers of seconds, such as
+	// 90 or 1.5.
+	DurationNumbers bool
+
+	// ExtendedDurations makes Durations take days ("d") and weeks ("w"),
+	// always 24 hours and 7 days long, besides the units of
+	// time.ParseDuration, with no bound on their length short of the
+	// 10,000 years a Duration holds, such as "1d12h" or "9000h". Without it,
+	// ProfileStrictConformance only takes Durations in seconds.
+	ExtendedDurations bool
+
+	// ProtoJSON makes the unmarshal accept exactly the documents that
+	// google.golang.org/protobuf/encoding/protojson does, decoding them to
+	// the same messages, while still reporting field errors for those it
+	// rejects wherever possible. Of the other options only
+	// AllowUnknownFields and MergeInto, which merges the way proto.Merge
+	// does, are honoured.
+	ProtoJSON bool
+
+	// Profile selects a named set of the options above. The options set on
+	// the Unmarshaler are applied on top of it.
+	Profile Profile
+
+	// Observer, if set, is told about every document decoded.
+	Observer Observer
+
+	// Backend, if set, replaces encoding/json for splitting JSON objects and
+	// arrays, with no change to what is accepted or to the errors.
+	Backend Backend
+
+	// typeHandlers override the decoding of messages, keyed by their full name.
+	typeHandlers map[string]TypeHandler
+
+	// documents counts the documents read by UnmarshalNext, see DocumentCount.
+	// It is guarded by documentsMu, as copies of u read it.
+	documents int64
+}
+
+// TypeHandler decodes the raw JSON value of a message into target, taking over
+// from the Unmarshaler completely.
+type TypeHandler func(raw json.RawMessage, target proto.Message) error
+
+// RegisterTypeHandler overrides the decoding of all messages with the given
+// full name (e.g. "google.protobuf.Duration"), including well-known types and
+// messages implementing JSONPBUnmarshaler. This is meant for generated types
+// that can't be given an UnmarshalJSONPB method. Errors returned by fn are
+// prefixed with the path of the field being decoded.
+func (u *Unmarshaler) RegisterTypeHandler(name string, fn TypeHandler) {
+	if u.typeHandlers == nil {
+		u.typeHandlers = make(map[string]TypeHandler)
+	}
+	u.typeHandlers[name] = fn
+}
+
+// JSONPBUnmarshaler is implemented by protobuf messages that customize the way
+// they are unmarshaled from JSON. The Unmarshaler calls it with the raw JSON of
+// the message instead of decoding it field by field, and prefixes any error it
+// returns with the path of the field being decoded.
+type JSONPBUnmarshaler interface {
+	UnmarshalJSONPB(*Unmarshaler, []byte) error
 }
 
 // UnmarshalNext unmarshals the next protocol buffer from a JSON object stream.
 // This function is lenient and will decode any options permutations of the
-// related Marshaler.
+// related Marshaler. Errors are returned as a *DocumentError giving the
+// ordinal of the document, except for io.EOF at the end of the stream.
 func (u *Unmarshaler) UnmarshalNext(dec *json.Decoder, pb proto.Message) error {
-	inputValue := json.RawMessage{}
-	if err := dec.Decode(&inputValue); err != nil {
+	err := u.unmarshalNext(dec, pb)
+	if err == io.EOF {
+		return err
+	}
+	documentsMu.Lock()
+	u.documents++
+	index := int(u.documents)
+	documentsMu.Unlock()
+	if err != nil {
+		return &DocumentError{Index: index, Err: err}
+	}
+	return nil
+}
+
+// DocumentCount returns the number of documents read by UnmarshalNext
+// through u so far, including those that failed to decode. Streams read
+// concurrently should each use their own Unmarshaler, see Clone.
+func (u *Unmarshaler) DocumentCount() int {
+	documentsMu.RLock()
+	defer documentsMu.RUnlock()
+	return int(u.documents)
+}
+
+// documentsMu guards the document counts of Unmarshalers, which UnmarshalNext
+// updates while other calls may be copying the Unmarshaler.
+var documentsMu sync.RWMutex
+
+// options returns a copy of u, to change options in for a single call.
+func (u *Unmarshaler) options() Unmarshaler {
+	documentsMu.RLock()
+	defer documentsMu.RUnlock()
+	return *u
+}
+
+func (u *Unmarshaler) unmarshalNext(dec *json.Decoder, pb proto.Message) error {
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := dec.Decode(inputValue); err != nil {
 		return err
 	}
-	return u.unmarshalValue(reflect.ValueOf(pb).Elem(), inputValue, nil)
+	return u.unmarshalDocument(pb, *inputValue)
 }
 
 // Unmarshal unmarshals a JSON object stream into a protocol
 // buffer. This function is lenient and will decode any options
 // permutations of the related Marshaler.
 func (u *Unmarshaler) Unmarshal(r io.Reader, pb proto.Message) error {
-	dec := json.NewDecoder(r)
-	return u.UnmarshalNext(dec, pb)
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := u.readDocument(r, inputValue); err != nil {
+		return err
+	}
+	return u.unmarshalDocument(pb, *inputValue)
+}
+
+// UnmarshalBytes unmarshals a JSON document held in memory into a protocol
+// buffer. Unlike Unmarshal it decodes straight from b, without going through
+// a json.Decoder and copying the document first.
+func (u *Unmarshaler) UnmarshalBytes(b []byte, pb proto.Message) error {
+	if u.InputEncoding == EncodingAuto {
+		var err error
+		if b, err = textBytes(b); err != nil {
+			return err
+		}
+	}
+	if u.MaxInputBytes > 0 && int64(len(b)) > u.MaxInputBytes {
+		return inputTooLarge(u.MaxInputBytes)
+	}
+	if u.allowsJSONC() {
+		var err error
+		if b, err = u.blankJSONC(append([]byte(nil), b...)); err != nil {
+			return err
+		}
+	}
+	b = bytes.Trim(b, jsonWhitespace)
+	if len(b) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return u.unmarshalDocument(pb, b)
+}
+
+// unmarshalDocument decodes a whole JSON document into pb.
+func (d *decodeState) unmarshalDocument(pb proto.Message, inputValue json.RawMessage) error {
+	if d.Observer != nil && d.observerCtx == nil {
+		return d.observe(proto.MessageReflect(pb).Descriptor().FullName(), inputValue, func() error {
+			return d.unmarshalDocument(pb, inputValue)
+		})
+	}
+	if d.ProtoJSON {
+		return d.formatErrors(d.unmarshalProtoJSON(proto.MessageReflect(pb), inputValue))
+	}
+	if d.rejectNullDocument && isNull(inputValue) {
+		return d.formatErrors(errNullDocument)
+	}
+	if !d.MergeInto {
+		pb.Reset()
+	}
+	if err := d.collect(d.unmarshalValue(reflect.ValueOf(pb).Elem(), inputValue, nil)); err != nil {
+		return d.formatErrors(err)
+	}
+	return d.formatErrors(d.finishDocument(proto.MessageReflect(pb)))
 }
 
 // UnmarshalNext unmarshals the next protocol buffer from a JSON object stream.
@@ -84,6 +469,13 @@ func Unmarshal(r io.Reader, pb proto.Message) error {
 	return new(Unmarshaler).Unmarshal(r, pb)
 }
 
+// UnmarshalBytes unmarshals a JSON document held in memory into a protocol
+// buffer. This function is lenient and will decode any options permutations
+// of the related Marshaler.
+func UnmarshalBytes(b []byte, pb proto.Message) error {
+	return new(Unmarshaler).UnmarshalBytes(b, pb)
+}
+
 // UnmarshalString will populate the fields of a protocol buffer based
 // on a JSON string. This function is lenient and will decode any options
 // permutations of the related Marshaler.
@@ -93,60 +485,50 @@ func UnmarshalString(str string, pb proto.Message) error {
 
 // unmarshalValue converts/copies a value into the target.
 // prop may be nil.
-func (u *Unmarshaler) unmarshalValue(target reflect.Value, inputValue json.RawMessage, prop *proto.Properties) error {
+func (d *decodeState) unmarshalValue(target reflect.Value, inputValue json.RawMessage, prop *proto.Properties) error {
 	targetType := target.Type()
 
-	// Allocate memory for pointer fields.
+	// Allocate memory for pointer fields, unless merging into an existing value.
 	if targetType.Kind() == reflect.Ptr {
-		target.Set(reflect.New(targetType.Elem()))
-		return u.unmarshalValue(target.Elem(), inputValue, prop)
-	}
-
-	// Handle well-known types.
-	type wkt interface {
-		XXX_WellKnownType() string
-	}
-	if wkt, ok := target.Addr().Interface().(wkt); ok {
-		switch wkt.XXX_WellKnownType() {
-		case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value",
-			"Int32Value", "UInt32Value", "BoolValue", "StringValue", "BytesValue":
-			// "Wrappers use the same representation in JSON
-			//  as the wrapped primitive type, except that null is allowed."
-			// encoding/json will turn JSON `null` into Go `nil`,
-			// so we don't have to do any extra work.
-			return u.unmarshalValue(target.Field(0), inputValue, prop)
-		case "Any":
-			return fmt.Errorf("unmarshaling Any not supported yet")
-		case "Duration":
-			unq, err := strconv.Unquote(string(inputValue))
-			if err != nil {
-				return err
-			}
-			d, err := time.ParseDuration(unq)
-			if err != nil {
-				return fmt.Errorf("bad Duration: %v", err)
+		if !d.MergeInto || target.IsNil() {
+			target.Set(reflect.New(targetType.Elem()))
+		}
+		return d.unmarshalValue(target.Elem(), inputValue, prop)
+	}
+
+	if handled, err := d.unmarshalStdTime(target, inputValue); handled {
+		return err
+	}
+	if handled, err := d.unmarshalCustomType(target, inputValue, prop); handled {
+		return err
+	}
+
+	if len(d.typeHandlers) > 0 {
+		if pb, ok := target.Addr().Interface().(proto.Message); ok {
+			if handler, ok := d.typeHandlers[proto.MessageName(pb)]; ok {
+				return handler(inputValue, pb)
 			}
-			ns := d.Nanoseconds()
-			s := ns / 1e9
-			ns %= 1e9
-			target.Field(0).SetInt(s)
-			target.Field(1).SetInt(ns)
-			return nil
-		case "Timestamp":
-			unq, err := strconv.Unquote(string(inputValue))
-			if err != nil {
+		}
+	}
+
+	if jsu, ok := target.Addr().Interface().(JSONPBUnmarshaler); ok {
+		return jsu.UnmarshalJSONPB(d.Unmarshaler, []byte(inputValue))
+	}
+
+	// Handle well-known types, and the other messages with a JSON form of their own.
+	if pb, ok := target.Addr().Interface().(proto.Message); ok {
+		m := proto.MessageReflect(pb)
+		if handler, ok := wellKnownHandlers[m.Descriptor().FullName()]; ok {
+			return handler(d, inputValue, m)
+		}
+	}
+
+	if d.coercesScalars() {
+		if kind, ok := scalarKind(targetType, prop); ok {
+			var err error
+			if inputValue, err = d.coerce(kind, inputValue); err != nil {
 				return err
 			}
-			t, err := time.Parse(time.RFC3339Nano, unq)
-			if err != nil {
-				return fmt.Errorf("bad Timestamp: %v", err)
-			}
-			ns := t.UnixNano()
-			s := ns / 1e9
-			ns %= 1e9
-			target.Field(0).SetInt(s)
-			target.Field(1).SetInt(ns)
-			return nil
 		}
 	}
 
@@ -161,7 +543,10 @@ func (u *Unmarshaler) unmarshalValue(target reflect.Value, inputValue json.RawMe
 		s := inputValue[1 : len(inputValue)-1]
 		n, ok := vmap[string(s)]
 		if !ok {
-			return fmt.Errorf("unknown value '%q' for enum %s", s, prop.Enum)
+			var err error
+			if n, err = d.unknownEnumName(string(s), prop.Enum, vmap); err != nil {
+				return err
+			}
 		}
 		if target.Kind() == reflect.Ptr { // proto2
 			target.Set(reflect.New(targetType.Elem()))
@@ -173,135 +558,330 @@ func (u *Unmarshaler) unmarshalValue(target reflect.Value, inputValue json.RawMe
 
 	// Handle nested messages.
 	if targetType.Kind() == reflect.Struct {
-		var jsonFields map[string]json.RawMessage
-		if err := json.Unmarshal(inputValue, &jsonFields); err != nil {
+		if err := d.enterMessage(); err != nil {
+			return err
+		}
+		defer d.leaveMessage()
+		jsonFields := getFieldMap()
+		defer putFieldMap(jsonFields)
+		if err := d.tokens().splitObject(inputValue, jsonFields); err != nil {
 			return correctJsonType(err, targetType)
 		}
 
-		consumeField := func(prop *proto.Properties) (json.RawMessage, bool) {
+		consumeField := func(prop *proto.Properties) (json.RawMessage, string, bool, error) {
+			if !d.fieldAllowed(protoFieldName(prop)) {
+				return nil, "", false, nil
+			}
 			// Be liberal in what names we accept; both orig_name and camelName are okay.
 			fieldNames := acceptedJSONFieldNames(prop)
 
 			vOrig, okOrig := jsonFields[fieldNames.orig]
 			vCamel, okCamel := jsonFields[fieldNames.camel]
 			if !okOrig && !okCamel {
-				return nil, false
+				return nil, "", false, nil
 			}
 			// If, for some reason, both are present in the data, favour the camelName.
+			if okOrig && okCamel && fieldNames.orig != fieldNames.camel {
+				if d.rejectDuplicateKeys {
+					delete(jsonFields, fieldNames.orig)
+					delete(jsonFields, fieldNames.camel)
+					return nil, "", false, fieldErrorAt(prop.Name, fieldNames.camel, errDuplicateName(fieldNames.orig, fieldNames.camel))
+				}
+				d.warn(WarnDuplicateName, prop.Name, "both %q and %q are set, using %q", fieldNames.orig, fieldNames.camel, fieldNames.camel)
+			}
 			var raw json.RawMessage
+			var key string
 			if okOrig {
-				raw = vOrig
+				raw, key = vOrig, fieldNames.orig
 				delete(jsonFields, fieldNames.orig)
 			}
 			if okCamel {
-				raw = vCamel
+				raw, key = vCamel, fieldNames.camel
 				delete(jsonFields, fieldNames.camel)
 			}
-			return raw, true
+			return raw, key, true, nil
 		}
 
 		sprops := proto.GetProperties(targetType)
 		for i := 0; i < target.NumField(); i++ {
 			ft := target.Type().Field(i)
-			if strings.HasPrefix(ft.Name, "XXX_") {
+			// Skip internal bookkeeping fields, both the XXX_ ones and the unexported ones of newer generated code.
+			if strings.HasPrefix(ft.Name, "XXX_") || ft.PkgPath != "" {
+				continue
+			}
+			// Oneof members are decoded below, not through the interface field holding them.
+			if ft.Tag.Get("protobuf_oneof") != "" {
 				continue
 			}
 
-			valueForField, ok := consumeField(sprops.Prop[i])
+			valueForField, key, ok, err := consumeField(sprops.Prop[i])
+			if err != nil {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
+			}
 			if !ok {
+				d.fieldMissing(target.Addr().Interface().(proto.Message), sprops.Prop[i].Name, sprops.Prop[i].Tag)
+				continue
+			}
+			if settable, err := d.fieldSettable(target.Addr().Interface().(proto.Message), key, sprops.Prop[i].Tag); !settable {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
+			}
+			d.recordSetField(protoFieldName(sprops.Prop[i]))
+			d.warnIfDeprecated(target.Addr().Interface().(proto.Message), sprops.Prop[i].Name, sprops.Prop[i].Tag)
+			if isNull(valueForField) && !acceptsNull(ft.Type, sprops.Prop[i]) {
+				d.fieldMissing(target.Addr().Interface().(proto.Message), sprops.Prop[i].Name, sprops.Prop[i].Tag)
+				if proto3Optional(target.Addr().Interface().(proto.Message), sprops.Prop[i].Tag) {
+					target.Field(i).Set(reflect.Zero(ft.Type))
+					continue
+				}
+				if _, err := d.unmarshalNullField(sprops.Prop[i].Name, key, target.Field(i)); err != nil {
+					if err := d.collect(err); err != nil {
+						return err
+					}
+				}
 				continue
 			}
 
-			if err := u.unmarshalValue(target.Field(i), valueForField, sprops.Prop[i]); err != nil {
-				return FieldError(sprops.Prop[i].Name, err)
+			err = d.redactingField(target.Addr().Interface().(proto.Message), sprops.Prop[i].Tag, func() error {
+				return d.unmarshalField(sprops.Prop[i], key, target.Field(i), valueForField)
+			})
+			if err := d.collect(err); err != nil {
+				return err
 			}
 		}
 		// Check for any oneof fields.
 		if len(jsonFields) > 0 {
-			for _, oop := range sprops.OneofTypes {
-				raw, ok := consumeField(oop.Prop)
+			oneofsSet := map[int]string{}
+			for _, oop := range sortedOneofTypes(sprops) {
+				raw, key, ok, err := consumeField(oop.Prop)
+				if err != nil {
+					if err := d.collect(err); err != nil {
+						return err
+					}
+					continue
+				}
 				if !ok {
 					continue
 				}
+				if settable, err := d.fieldSettable(target.Addr().Interface().(proto.Message), key, oop.Prop.Tag); !settable {
+					if err := d.collect(err); err != nil {
+						return err
+					}
+					continue
+				}
+				d.recordSetField(protoFieldName(oop.Prop))
 				nv := reflect.New(oop.Type.Elem())
+				if cur := target.Field(oop.Field); d.MergeInto && !cur.IsNil() && cur.Elem().Type() == oop.Type {
+					nv = cur.Elem()
+				}
+				if isNull(raw) && !acceptsNull(nv.Elem().Field(0).Type(), oop.Prop) {
+					set, err := d.unmarshalNullField(oop.Prop.Name, key, nv.Elem().Field(0))
+					if err != nil {
+						if err := d.collect(err); err != nil {
+							return err
+						}
+						continue
+					}
+					if set {
+						if err := d.checkOneofConflict(oneofsSet, oop.Field, protoFieldName(oop.Prop), targetType.Field(oop.Field).Tag.Get("protobuf_oneof")); err != nil {
+							if err := d.collect(err); err != nil {
+								return err
+							}
+							continue
+						}
+						target.Field(oop.Field).Set(nv)
+					}
+					continue
+				}
+				if err := d.checkOneofConflict(oneofsSet, oop.Field, protoFieldName(oop.Prop), targetType.Field(oop.Field).Tag.Get("protobuf_oneof")); err != nil {
+					if err := d.collect(err); err != nil {
+						return err
+					}
+					continue
+				}
 				target.Field(oop.Field).Set(nv)
-				if err := u.unmarshalValue(nv.Elem().Field(0), raw, oop.Prop); err != nil {
-					return FieldError(oop.Prop.Name, err)
+				err = d.redactingField(target.Addr().Interface().(proto.Message), oop.Prop.Tag, func() error {
+					return d.unmarshalField(oop.Prop, key, nv.Elem().Field(0), raw)
+				})
+				if err := d.collect(err); err != nil {
+					return err
 				}
 			}
 		}
-		if !u.AllowUnknownFields && len(jsonFields) > 0 {
-			return getFieldMismatchError(jsonFields, sprops)
+		// Check for any extensions, keyed by their full name in square brackets.
+		if len(jsonFields) > 0 {
+			if err := d.unmarshalExtensions(target, jsonFields); err != nil {
+				return err
+			}
 		}
-		return nil
+		return d.checkUnknownFields(jsonFields, func() error {
+			pb, _ := target.Addr().Interface().(proto.Message)
+			return getFieldMismatchError(jsonFields, sprops, pb)
+		})
 	}
 
 	// Handle arrays (which aren't encoded bytes)
 	if targetType.Kind() == reflect.Slice && targetType.Elem().Kind() != reflect.Uint8 {
-		var slc []json.RawMessage
-		if err := json.Unmarshal(inputValue, &slc); err != nil {
+		slc, err := d.splitRepeated(inputValue)
+		if err != nil {
 			return correctJsonType(err, targetType)
 		}
-		len := len(slc)
-		target.Set(reflect.MakeSlice(targetType, len, len))
-		for i := 0; i < len; i++ {
-			if err := u.unmarshalValue(target.Index(i), slc[i], prop); err != nil {
-				return FieldError(fmt.Sprintf("[%d]", i), err)
+		elems := reflect.MakeSlice(targetType, 0, len(slc))
+		for i, raw := range slc {
+			if err := d.step(); err != nil {
+				return err
+			}
+			if isNull(raw) && !acceptsNull(targetType.Elem(), prop) {
+				if d.SkipNullElements {
+					continue
+				}
+				if err := d.collect(d.withRaw(FieldError(fmt.Sprintf("[%d]", i), errNullElement), jsonNull)); err != nil {
+					return err
+				}
+				continue
+			}
+			elem := reflect.New(targetType.Elem()).Elem()
+			if err := d.unmarshalElement(fmt.Sprintf("[%d]", i), strconv.Itoa(i), elem, raw, prop); err != nil {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
 			}
+			elems = reflect.Append(elems, elem)
+		}
+		if d.MergeInto && d.AppendRepeated {
+			elems = reflect.AppendSlice(target, elems)
 		}
+		target.Set(elems)
 		return nil
 	}
 
 	// Handle maps (whose keys are always strings)
 	if targetType.Kind() == reflect.Map {
-		var mp map[string]json.RawMessage
-		if err := json.Unmarshal(inputValue, &mp); err != nil {
+		mp := map[string]json.RawMessage{}
+		if err := d.tokens().splitObject(inputValue, mp); err != nil {
 			return err
 		}
-		target.Set(reflect.MakeMap(targetType))
-		var keyprop, valprop *proto.Properties
+		if !d.MergeInto || target.IsNil() {
+			target.Set(reflect.MakeMap(targetType))
+		}
+		var valprop *proto.Properties
 		if prop != nil {
-			// These could still be nil if the protobuf metadata is broken somehow.
-			// TODO: This won't work because the fields are unexported.
-			// We should probably just reparse them.
-			//keyprop, valprop = prop.mkeyprop, prop.mvalprop
+			// This could still be nil if the protobuf metadata is broken somehow.
+			valprop = prop.MapValProp
 		}
-		for ks, raw := range mp {
+		for _, ks := range sortedKeys(mp) {
+			if err := d.step(); err != nil {
+				return err
+			}
+			raw := mp[ks]
 			// Unmarshal map key. The core json library already decoded the key into a
-			// string, so we handle that specially. Other types were quoted post-serialization.
-			var k reflect.Value
-			if targetType.Key().Kind() == reflect.String {
-				k = reflect.ValueOf(ks)
-			} else {
-				k = reflect.New(targetType.Key()).Elem()
-				if err := u.unmarshalValue(k, json.RawMessage(ks), keyprop); err != nil {
-					return FieldError(fmt.Sprintf("['%s']key", ks), err)
+			// string, other types were quoted post-serialization.
+			k := reflect.ValueOf(ks)
+			if keyKind, ok := mapKeyKinds[targetType.Key().Kind()]; ok {
+				key, ok := parseMapKey(keyKind, ks)
+				if !ok {
+					if err := d.collect(d.badMapKeyError(ks, keyKind.String(), protoTypeName(targetType.Elem()))); err != nil {
+						return err
+					}
+					continue
 				}
+				k = reflect.ValueOf(key.Interface()).Convert(targetType.Key())
 			}
 
 			// Unmarshal map value.
+			if isNull(raw) && !acceptsNull(targetType.Elem(), valprop) && d.NullHandling != NullAsDefault {
+				if err := d.collect(d.withRaw(fieldErrorAt(fmt.Sprintf("['%s']value", ks), ks, errNullMapValue), jsonNull)); err != nil {
+					return err
+				}
+				continue
+			}
 			v := reflect.New(targetType.Elem()).Elem()
-			if err := u.unmarshalValue(v, raw, valprop); err != nil {
-				return FieldError(fmt.Sprintf("['%s']value", ks), err)
+			if err := d.unmarshalElement(fmt.Sprintf("['%s']value", ks), ks, v, raw, valprop); err != nil {
+				if err := d.collect(err); err != nil {
+					return err
+				}
+				continue
 			}
 			target.SetMapIndex(k, v)
 		}
 		return nil
 	}
 
-	// 64-bit integers can be encoded as strings. In this case we drop
-	// the quotes and proceed as normal.
-	isNum := targetType.Kind() == reflect.Int64 || targetType.Kind() == reflect.Uint64
-	if isNum && strings.HasPrefix(string(inputValue), `"`) {
-		inputValue = inputValue[1 : len(inputValue)-1]
-		if err := json.Unmarshal(inputValue, target.Addr().Interface()); err != nil {
-			return fmt.Errorf("%v while looking for an integer in a string", err)
+	// Numbers are range checked. 64-bit integers can also be encoded as
+	// strings, and so are the non-finite floating point values. Bytes are
+	// base64 strings in any of its variants, and bools only strings with
+	// CoerceStringBools.
+	switch targetType.Kind() {
+	case reflect.Bool:
+		v, err := d.parseBool(inputValue)
+		if err != nil {
+			return err
+		}
+		target.SetBool(v)
+		return nil
+	case reflect.Int32, reflect.Int64, reflect.Int:
+		n, err := d.parseInt(inputValue, targetType.Bits())
+		if err != nil {
+			return err
+		}
+		target.SetInt(n)
+		return nil
+	case reflect.Uint32, reflect.Uint64, reflect.Uint:
+		n, err := d.parseUint(inputValue, targetType.Bits())
+		if err != nil {
+			return err
+		}
+		target.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		v, err := d.parseFloat(inputValue, targetType.Bits())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(v)
+		return nil
+	case reflect.String:
+		s, err := d.parseString(inputValue)
+		if err != nil {
+			return err
+		}
+		target.SetString(s)
+		return nil
+	case reflect.Slice:
+		b, err := d.parseBytes(inputValue)
+		if err != nil {
+			return err
 		}
+		target.SetBytes(b)
 		return nil
-	} else {
-		// Use the encoding/json for parsing other value types.
-		return json.Unmarshal(inputValue, target.Addr().Interface())
 	}
+
+	// Use the encoding/json for parsing other value types.
+	return json.Unmarshal(inputValue, target.Addr().Interface())
+}
+
+// wellKnownType returns the name of the well-known type held in target (e.g.
+// "Duration"), or "" if it isn't one. Both the XXX_WellKnownType marker of
+// older generated code and the descriptors of google.golang.org/protobuf
+// messages are understood.
+func wellKnownType(target reflect.Value) string {
+	m := target.Addr().Interface()
+	if wkt, ok := m.(interface{ XXX_WellKnownType() string }); ok {
+		return wkt.XXX_WellKnownType()
+	}
+	if m, ok := m.(protoreflect.ProtoMessage); ok {
+		name := m.ProtoReflect().Descriptor().FullName()
+		if name.Parent() == "google.protobuf" {
+			return string(name.Name())
+		}
+	}
+	return ""
 }
 
 // jsonProperties returns parsed proto.Properties for the field and corrects JSONName attribute.
@@ -318,7 +898,18 @@ type fieldNames struct {
 	orig, camel string
 }
 
+// String lists the accepted names, as "some_value/someValue", or only once if they are the same.
+func (f fieldNames) String() string {
+	if f.orig == f.camel {
+		return f.orig
+	}
+	return f.orig + "/" + f.camel
+}
+
 func acceptedJSONFieldNames(prop *proto.Properties) fieldNames {
+	if isGroup(prop) {
+		return fieldNames{orig: protoFieldName(prop), camel: prop.OrigName}
+	}
 	opts := fieldNames{orig: prop.OrigName, camel: prop.OrigName}
 	if prop.JSONName != "" {
 		opts.camel = prop.JSONName
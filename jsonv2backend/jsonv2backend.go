@@ -0,0 +1,101 @@
+// Package jsonv2backend provides an experimental nicejsonpb.Backend built on the jsontext package
+// of encoding/json/v2, as found in github.com/go-json-experiment/json:
+//
+//	u := nicejsonpb.New(nicejsonpb.WithBackend(jsonv2backend.Backend))
+//
+// The members of objects and arrays are copied out of the input, which the Unmarshaler reuses for
+// the next document once it returns.
+package jsonv2backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Backend splits JSON with jsontext, configured to accept what encoding/json does.
+var Backend backend
+
+type backend struct{}
+
+var (
+	errUnexpectedKind = errors.New("unexpected kind of JSON value")
+	errTrailingData   = errors.New("unexpected data after JSON value")
+)
+
+var decoderPool = sync.Pool{
+	New: func() interface{} { return jsontext.NewDecoder(bytes.NewReader(nil)) },
+}
+
+// decoderOptions let the last of duplicate names win, as encoding/json does. Invalid UTF-8 fails,
+// leaving encoding/json to replace it.
+var decoderOptions = []jsontext.Options{jsontext.AllowDuplicateNames(true)}
+
+func (backend) SplitObject(raw []byte, fields map[string]json.RawMessage) error {
+	return split(raw, '{', '}', func(dec *jsontext.Decoder) error {
+		tok, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		name := tok.String() // The token is only valid until the next read.
+		value, err := readValue(dec)
+		if err != nil {
+			return err
+		}
+		fields[name] = value
+		return nil
+	})
+}
+
+func (backend) SplitArray(raw []byte) ([]json.RawMessage, error) {
+	var slc []json.RawMessage
+	err := split(raw, '[', ']', func(dec *jsontext.Decoder) error {
+		value, err := readValue(dec)
+		if err == nil {
+			slc = append(slc, value)
+		}
+		return err
+	})
+	return slc, err
+}
+
+// split reads the object or array held in raw, delimited by begin and end, calling member for each
+// of its members.
+func split(raw []byte, begin, end jsontext.Kind, member func(dec *jsontext.Decoder) error) error {
+	dec := decoderPool.Get().(*jsontext.Decoder)
+	defer decoderPool.Put(dec)
+	dec.Reset(bytes.NewReader(raw), decoderOptions...)
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != begin {
+		return errUnexpectedKind
+	}
+	for dec.PeekKind() != end {
+		if err := member(dec); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+	if _, err := dec.ReadToken(); err != io.EOF {
+		return errTrailingData
+	}
+	return nil
+}
+
+// readValue reads the next value, returning a copy of it: values end up in UnknownFieldSink and
+// errors, which may keep them past the Unmarshal call.
+func readValue(dec *jsontext.Decoder) (json.RawMessage, error) {
+	value, err := dec.ReadValue()
+	if err != nil {
+		return nil, err
+	}
+	return append(json.RawMessage(nil), value...), nil
+}
@@ -0,0 +1,50 @@
+package jsonv2backend_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/jsonv2backend"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_MatchesEncodingJSON(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithBackend(jsonv2backend.Backend))
+	for _, input := range []string{
+		`{"someString": "aé", "someStringRep": ["x", "y"], "someEmbedded": {"children": [{"identifier": "c"}]}}`,
+		`{"someStringToInt64": {"a": "1", "b\"q": 2}, "someInt32ToString": {"-1": "x"}}`,
+		`{"someString": "a", "someString": "b"}`,
+		`{"someStringRep": "x"}`,
+		`{"someEmbedded": [1]}`,
+		`{"someStringToInt64": {"a": true}}`,
+		`{"unknown": 1}`,
+		"{\"someString\": \"\xff\"}",
+		`{"someStringRep": [], "someEmbeddedRep": [{}, {"children": []}]}`,
+		`{"someStringRep": ["a" "b"]}`,
+	} {
+		want, got := &validatortest.Message3{}, &validatortest.Message3{}
+		wantErr := nicejsonpb.UnmarshalString(input, want)
+		err := u.Unmarshal(strings.NewReader(input), got)
+		if wantErr != nil {
+			require.EqualError(t, err, wantErr.Error(), input)
+			continue
+		}
+		require.NoError(t, err, input)
+		require.True(t, proto.Equal(want, got), input)
+	}
+}
+
+func TestBackend_ValuesOutliveTheInput(t *testing.T) {
+	var captured []json.RawMessage
+	u := nicejsonpb.New(nicejsonpb.WithBackend(jsonv2backend.Backend), nicejsonpb.WithUnknownFieldSink(func(path string, key string, raw json.RawMessage) {
+		captured = append(captured, raw)
+	}))
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"first": "first-value", "someString": "a"}`), &validatortest.Message3{}))
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someString": "XXXXXX"}`), &validatortest.Message3{}))
+	require.Len(t, captured, 1)
+	require.Equal(t, `"first-value"`, string(captured[0]))
+}
@@ -0,0 +1,62 @@
+package nicejsonpb
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInputTooLarge is returned, wrapped, for documents larger than MaxInputBytes.
+var ErrInputTooLarge = errors.New("input too large")
+
+func inputTooLarge(limit int64) error {
+	return codeErrorf(CodeLimitExceeded, "%w: more than %d bytes", ErrInputTooLarge, limit)
+}
+
+// LimitReader returns a reader that reads from r but fails with ErrInputTooLarge once more than
+// MaxInputBytes have been read, or r itself if there is no limit. It lets front-ends reading other
+// formats, or decompressing their input, hold it to the same limit as JSON.
+func (u *Unmarshaler) LimitReader(r io.Reader) io.Reader {
+	if u.MaxInputBytes <= 0 {
+		return r
+	}
+	if l, ok := r.(*limitedReader); ok && l.limit <= u.MaxInputBytes {
+		return l
+	}
+	return &limitedReader{r: r, limit: u.MaxInputBytes, left: u.MaxInputBytes}
+}
+
+// maxBytesPerChar is the most bytes a character takes in the encodings EncodingAuto detects, which is
+// UTF-32, for every character that takes at least one in UTF-8.
+const maxBytesPerChar = 4
+
+// rawLimitReader is LimitReader for documents yet to be transcoded to UTF-8, as UTF-16 and UTF-32
+// documents are read whole to be. They are held to maxBytesPerChar times MaxInputBytes, beyond which
+// no document is within MaxInputBytes once in UTF-8.
+func (u *Unmarshaler) rawLimitReader(r io.Reader) io.Reader {
+	if u.MaxInputBytes <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, limit: u.MaxInputBytes, left: maxBytesPerChar * u.MaxInputBytes}
+}
+
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	left  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, inputTooLarge(l.limit)
+	}
+	if int64(len(p)) > l.left+1 {
+		// One more byte than allowed tells a document of exactly the limit from a larger one.
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, inputTooLarge(l.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,86 @@
+package nicejsonpb
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// mapKeyKinds are the proto kinds of the Go types of non-string map keys.
+var mapKeyKinds = map[reflect.Kind]protoreflect.Kind{
+	reflect.Bool:   protoreflect.BoolKind,
+	reflect.Int32:  protoreflect.Int32Kind,
+	reflect.Int64:  protoreflect.Int64Kind,
+	reflect.Uint32: protoreflect.Uint32Kind,
+	reflect.Uint64: protoreflect.Uint64Kind,
+}
+
+// parseMapKey decodes the JSON object key ks of a map entry. The proto3 JSON mapping encodes integer
+// keys as their decimal strings and bool keys as "true" or "false".
+func parseMapKey(kind protoreflect.Kind, ks string) (protoreflect.Value, bool) {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(ks), true
+	case protoreflect.BoolKind:
+		switch ks {
+		case "true":
+			return protoreflect.ValueOfBool(true), true
+		case "false":
+			return protoreflect.ValueOfBool(false), true
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, err := strconv.ParseInt(ks, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(n)), true
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, err := strconv.ParseInt(ks, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(n), true
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseUint(ks, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(n)), true
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, err := strconv.ParseUint(ks, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(n), true
+		}
+	}
+	return protoreflect.Value{}, false
+}
+
+// badMapKeyError reports a map key that parseMapKey couldn't decode, for a map<keyType, valueType>.
+func (d *decodeState) badMapKeyError(ks string, keyType, valueType string) error {
+	return codeErrorf(CodeBadMapKey, "bad map key '%s' for map<%s, %s>", d.echo("%s", "string", ks), keyType, valueType)
+}
+
+// protoTypeName names the type of the map values held in Go type t as it would be in a .proto file.
+func protoTypeName(t reflect.Type) string {
+	if m, ok := reflect.Zero(t).Interface().(proto.Message); ok {
+		return string(proto.MessageReflect(m).Descriptor().FullName())
+	}
+	if e, ok := reflect.Zero(t).Interface().(protoreflect.Enum); ok {
+		return string(e.Descriptor().FullName())
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "bytes"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	}
+	return t.Kind().String()
+}
+
+// dynamicProtoTypeName names the type of the values of fd as in a .proto file.
+func dynamicProtoTypeName(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.Message() != nil:
+		return string(fd.Message().FullName())
+	case fd.Enum() != nil:
+		return string(fd.Enum().FullName())
+	}
+	return fd.Kind().String()
+}
@@ -0,0 +1,53 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_NonStringMapKeys(t *testing.T) {
+	input := `{"someInt32ToString": {"-7": "minus seven", "12": "twelve"}, "someBoolToString": {"true": "yes", "false": "no"}}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Equal(t, map[int32]string{-7: "minus seven", 12: "twelve"}, stuff.SomeInt32ToString)
+	require.Equal(t, map[bool]string{true: "yes", false: "no"}, stuff.SomeBoolToString)
+
+	msg, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+	require.Equal(t, 2, msg.Get(msg.Descriptor().Fields().ByName("some_bool_to_string")).Map().Len())
+}
+
+func TestUnmarshal_BadMapKeys(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`{"someInt32ToString": {"abc": "x"}}`, "unparsable field SomeInt32ToString: bad map key 'abc' for map<int32, string>"},
+		{`{"someInt32ToString": {"1e3": "x"}}`, "unparsable field SomeInt32ToString: bad map key '1e3' for map<int32, string>"},
+		{`{"someInt32ToString": {"2147483648": "x"}}`, "unparsable field SomeInt32ToString: bad map key '2147483648' for map<int32, string>"},
+		{`{"someBoolToString": {"True": "x"}}`, "unparsable field SomeBoolToString: bad map key 'True' for map<bool, string>"},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{})
+		require.EqualError(t, err, tc.err, tc.input)
+
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestUnmarshal_MapValueProperties(t *testing.T) {
+	input := `{"someStringToStatus": {"a": "STATUS_ACTIVE", "b": 2}, "someStringToEmbedded": {"c": {"someValue": "3"}}}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Equal(t, map[string]validatortest.Status{"a": validatortest.Status_STATUS_ACTIVE, "b": validatortest.Status_STATUS_DISABLED}, stuff.SomeStringToStatus)
+	require.Equal(t, int64(3), stuff.SomeStringToEmbedded["c"].SomeValue)
+
+	err := nicejsonpb.UnmarshalString(`{"someStringToStatus": {"a": "STATUS_NOPE"}}`, stuff)
+	require.EqualError(t, err, `unparsable field SomeStringToStatus.['a']value: unknown value '"STATUS_NOPE"' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]`)
+	err = nicejsonpb.UnmarshalString(`{"someStringToEmbedded": {"c": {"someValue": true}}}`, stuff)
+	require.EqualError(t, err, "unparsable field SomeStringToEmbedded.['c']value.SomeValue: json: cannot unmarshal bool into Go value of type int64")
+}
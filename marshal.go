@@ -0,0 +1,338 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Marshaler is a configurable object for converting a protocol buffer object to
+// its JSON representation, with the options of jsonpb.Marshaler, so that it can
+// replace it. What it writes, the Unmarshaler reads.
+type Marshaler struct {
+	// Whether to use the proto names of fields, such as "some_value", rather than
+	// their JSON names, such as "someValue".
+	OrigName bool
+
+	// Whether to write enum values as numbers rather than names.
+	EnumsAsInts bool
+
+	// Whether to write fields that are unset: scalars as their zero values, lists
+	// as [], maps as {}, and messages as null. Unset oneofs, and proto3 optional
+	// fields, which belong to oneofs of their own, are left out.
+	EmitDefaults bool
+
+	// A string to indent each level of nesting with. If set, fields and elements
+	// are written on lines of their own, with a space after each colon.
+	Indent string
+
+	// Whether to write 64-bit integers as JSON numbers rather than strings, for
+	// readers that don't take strings. Numbers above 2^53 lose precision in
+	// JavaScript.
+	Int64sAsNumbers bool
+
+	// Mask, if set, trims the JSON to the fields it selects, like a read mask: the
+	// fields it names are written whole, and the messages leading to them with only
+	// the fields selected in them. Paths through repeated fields select within each
+	// element, and paths through maps name their keys, as in
+	// "some_map.some_key.some_field". Well-known types are written whole.
+	Mask *fieldmaskpb.FieldMask
+}
+
+// JSONPBMarshaler is implemented by protobuf messages that customize the way
+// they are marshaled to JSON, the counterpart of JSONPBUnmarshaler. The
+// Marshaler writes the JSON it returns in place of the message.
+type JSONPBMarshaler interface {
+	MarshalJSONPB(*Marshaler) ([]byte, error)
+}
+
+// encodeState holds the JSON written by a single Marshal call.
+type encodeState struct {
+	*Marshaler
+	buf bytes.Buffer
+	// mask selects the fields of the message being written, all of them if nil.
+	mask maskTree
+}
+
+// Marshal writes the JSON representation of pb to w.
+func (m *Marshaler) Marshal(w io.Writer, pb proto.Message) error {
+	e := &encodeState{Marshaler: m, mask: newMaskTree(m.Mask)}
+	if err := e.marshalMessage(proto.MessageReflect(pb)); err != nil {
+		return err
+	}
+	out := e.buf.Bytes()
+	if m.Indent != "" {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, out, "", m.Indent); err != nil {
+			return err
+		}
+		out = indented.Bytes()
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+// MarshalToString returns the JSON representation of pb as a string.
+func (m *Marshaler) MarshalToString(pb proto.Message) (string, error) {
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf, pb); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// marshalMessage writes msg as a JSON object, or in the JSON form of its own of
+// the well-known types.
+func (e *encodeState) marshalMessage(msg protoreflect.Message) error {
+	if jsm, ok := proto.MessageV1(msg.Interface()).(JSONPBMarshaler); ok {
+		b, err := jsm.MarshalJSONPB(e.Marshaler)
+		if err != nil {
+			return err
+		}
+		return json.Compact(&e.buf, b)
+	}
+	md := msg.Descriptor()
+	// The well-known types are written by protojson, which knows their JSON forms.
+	// 64-bit integers in them, such as those of Int64Value, stay strings.
+	if md.FullName().Parent() == "google.protobuf" {
+		b, err := protojson.MarshalOptions{
+			UseProtoNames:   e.OrigName,
+			UseEnumNumbers:  e.EnumsAsInts,
+			EmitUnpopulated: e.EmitDefaults,
+		}.Marshal(msg.Interface())
+		if err != nil {
+			return err
+		}
+		return json.Compact(&e.buf, b)
+	}
+
+	e.buf.WriteByte('{')
+	first := true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		mask, selected := e.selectField(string(fd.Name()))
+		if !selected {
+			continue
+		}
+		if !msg.Has(fd) {
+			if !e.EmitDefaults || fd.ContainingOneof() != nil {
+				continue
+			}
+			if fd.HasPresence() {
+				e.writeName(e.fieldName(fd), &first)
+				e.buf.WriteString("null")
+				continue
+			}
+		}
+		e.writeName(e.fieldName(fd), &first)
+		if err := e.marshalMasked(mask, fd, msg.Get(fd)); err != nil {
+			return FieldError(goCamelCase(string(fd.Name())), err)
+		}
+	}
+	// Extensions are keyed by their full name in square brackets, in the order of
+	// their numbers. A FieldMask can't name them, so they are only written when
+	// the message is selected whole.
+	var exts []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() && e.mask == nil {
+			exts = append(exts, fd)
+		}
+		return true
+	})
+	sort.Slice(exts, func(i, j int) bool { return exts[i].Number() < exts[j].Number() })
+	for _, fd := range exts {
+		e.writeName("["+string(fd.FullName())+"]", &first)
+		if err := e.marshalField(fd, msg.Get(fd)); err != nil {
+			return FieldError("["+string(fd.FullName())+"]", err)
+		}
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
+
+// selectField returns the mask of the field of the message being written with the
+// given proto name, and whether the field is selected at all.
+func (e *encodeState) selectField(name string) (maskTree, bool) {
+	if e.mask == nil {
+		return nil, true
+	}
+	mask, ok := e.mask[name]
+	return mask, ok
+}
+
+// marshalMasked is marshalField with mask selecting the fields of the messages in v.
+func (e *encodeState) marshalMasked(mask maskTree, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	outer := e.mask
+	e.mask = mask
+	defer func() { e.mask = outer }()
+	return e.marshalField(fd, v)
+}
+
+// fieldName returns the name fd is written with, which for groups is the name of
+// the group either way.
+func (e *encodeState) fieldName(fd protoreflect.FieldDescriptor) string {
+	if e.OrigName {
+		return fd.TextName()
+	}
+	return dynamicJSONName(fd)
+}
+
+// writeName writes the name of an object member, preceded by a comma unless it is
+// the first.
+func (e *encodeState) writeName(name string, first *bool) {
+	if !*first {
+		e.buf.WriteByte(',')
+	}
+	*first = false
+	e.writeString(name)
+	e.buf.WriteByte(':')
+}
+
+// marshalField writes v, the value of the field fd, which may be a list or a map.
+func (e *encodeState) marshalField(fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		e.buf.WriteByte('[')
+		for i := 0; i < list.Len(); i++ {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err := e.marshalSingular(fd, list.Get(i)); err != nil {
+				return FieldError("["+strconv.Itoa(i)+"]", err)
+			}
+		}
+		e.buf.WriteByte(']')
+	case fd.IsMap():
+		mp := v.Map()
+		e.buf.WriteByte('{')
+		first := true
+		entries := e.mask
+		for _, k := range sortedMapKeys(mp, fd.MapKey().Kind()) {
+			e.mask = entries
+			mask, selected := e.selectField(k.String())
+			if !selected {
+				continue
+			}
+			e.mask = mask
+			e.writeName(k.String(), &first)
+			if err := e.marshalSingular(fd.MapValue(), mp.Get(k)); err != nil {
+				return FieldError("['"+k.String()+"']value", err)
+			}
+		}
+		e.mask = entries
+		e.buf.WriteByte('}')
+	default:
+		return e.marshalSingular(fd, v)
+	}
+	return nil
+}
+
+// marshalSingular writes v, a single value of the kind of fd.
+func (e *encodeState) marshalSingular(fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		e.buf.WriteString(strconv.FormatBool(v.Bool()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		e.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		e.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		e.writeInt64(strconv.FormatInt(v.Int(), 10))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		e.writeInt64(strconv.FormatUint(v.Uint(), 10))
+	case protoreflect.FloatKind:
+		return e.writeFloat(float32(v.Float()), v.Float())
+	case protoreflect.DoubleKind:
+		return e.writeFloat(v.Float(), v.Float())
+	case protoreflect.StringKind:
+		e.writeString(v.String())
+	case protoreflect.BytesKind:
+		e.writeString(base64.StdEncoding.EncodeToString(v.Bytes()))
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			e.buf.WriteString("null")
+			return nil
+		}
+		value := fd.Enum().Values().ByNumber(v.Enum())
+		if e.EnumsAsInts || value == nil {
+			e.buf.WriteString(strconv.FormatInt(int64(v.Enum()), 10))
+			return nil
+		}
+		e.writeString(string(value.Name()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return e.marshalMessage(v.Message())
+	default:
+		return codeErrorf(CodeUnsupported, "unsupported field kind %v", fd.Kind())
+	}
+	return nil
+}
+
+// writeInt64 writes a 64-bit integer, quoted unless Int64sAsNumbers is set.
+func (e *encodeState) writeInt64(s string) {
+	if e.Int64sAsNumbers {
+		e.buf.WriteString(s)
+		return
+	}
+	e.buf.WriteByte('"')
+	e.buf.WriteString(s)
+	e.buf.WriteByte('"')
+}
+
+// writeFloat writes f, a float32 or float64, with the non-finite values as the
+// strings "NaN", "Infinity" and "-Infinity".
+func (e *encodeState) writeFloat(f interface{}, f64 float64) error {
+	switch {
+	case math.IsNaN(f64):
+		e.buf.WriteString(`"NaN"`)
+	case math.IsInf(f64, 1):
+		e.buf.WriteString(`"Infinity"`)
+	case math.IsInf(f64, -1):
+		e.buf.WriteString(`"-Infinity"`)
+	default:
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(b)
+	}
+	return nil
+}
+
+// writeString writes s as a JSON string.
+func (e *encodeState) writeString(s string) {
+	b, _ := json.Marshal(s)
+	e.buf.Write(b)
+}
+
+// sortedMapKeys returns the keys of mp, of the given kind, in order.
+func sortedMapKeys(mp protoreflect.Map, kind protoreflect.Kind) []protoreflect.MapKey {
+	keys := make([]protoreflect.MapKey, 0, mp.Len())
+	mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		switch kind {
+		case protoreflect.BoolKind:
+			return !keys[i].Bool() && keys[j].Bool()
+		case protoreflect.StringKind:
+			return keys[i].String() < keys[j].String()
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return keys[i].Uint() < keys[j].Uint()
+		default:
+			return keys[i].Int() < keys[j].Int()
+		}
+	})
+	return keys
+}
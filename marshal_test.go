@@ -0,0 +1,104 @@
+package nicejsonpb_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func marshalTestMessage() *validatortest.Message3 {
+	return &validatortest.Message3{
+		SomeString:        "foo",
+		SomeInt64:         -7,
+		SomeUint64:        8,
+		SomeDouble:        math.Inf(1),
+		SomeBytes:         []byte("bar"),
+		SomeStatus:        validatortest.Status_STATUS_ACTIVE,
+		SomeEmbeddedRep:   []*validatortest.Message3_Embedded{{Identifier: "a"}},
+		SomeInt32ToString: map[int32]string{10: "ten", 2: "two"},
+		SomeDuration:      durationpb.New(1500000000),
+		SomeWrappedInt:    wrapperspb.Int64(5),
+		Contact:           &validatortest.Message3_Email{Email: "a@b.c"},
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	s, err := (&nicejsonpb.Marshaler{}).MarshalToString(marshalTestMessage())
+	require.NoError(t, err)
+	require.Equal(t, `{"someString":"foo","someInt64":"-7","someUint64":"8","someDouble":"Infinity","someBytes":"YmFy",`+
+		`"someStatus":"STATUS_ACTIVE","someEmbeddedRep":[{"identifier":"a"}],"someInt32ToString":{"2":"two","10":"ten"},`+
+		`"someDuration":"1.500s","someWrappedInt":"5","email":"a@b.c"}`, s)
+
+	back := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(s, back))
+	require.True(t, proto.Equal(marshalTestMessage(), back))
+}
+
+func TestMarshal_Options(t *testing.T) {
+	m := &nicejsonpb.Marshaler{OrigName: true, EnumsAsInts: true, Int64sAsNumbers: true}
+	s, err := m.MarshalToString(&validatortest.Message3{SomeInt64: -7, SomeStatus: validatortest.Status_STATUS_DISABLED})
+	require.NoError(t, err)
+	require.Equal(t, `{"some_int64":-7,"some_status":2}`, s)
+
+	m = &nicejsonpb.Marshaler{Indent: "  "}
+	s, err = m.MarshalToString(&validatortest.Message3_Embedded{Identifier: "a", Children: []*validatortest.Message3_Embedded{{}}})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"identifier\": \"a\",\n  \"children\": [\n    {}\n  ]\n}", s)
+}
+
+func TestMarshal_EmitDefaults(t *testing.T) {
+	m := &nicejsonpb.Marshaler{EmitDefaults: true}
+	s, err := m.MarshalToString(&validatortest.Message3_Embedded{})
+	require.NoError(t, err)
+	require.Equal(t, `{"identifier":"","someValue":"0","children":[]}`, s)
+
+	s, err = m.MarshalToString(&validatortest.Message3{})
+	require.NoError(t, err)
+	require.Contains(t, s, `"someEmbedded":null`)
+	require.NotContains(t, s, `"someOptionalString"`)
+	require.Contains(t, s, `"someStringToInt64":{}`)
+	require.NotContains(t, s, `"email"`)
+
+	back := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(s, back))
+	require.Nil(t, back.SomeOptionalString)
+}
+
+func TestMarshal_Groups(t *testing.T) {
+	for _, m := range []*nicejsonpb.Marshaler{{}, {OrigName: true}} {
+		s, err := m.MarshalToString(&validatortest.Grouped2{Somegroup: &validatortest.Grouped2_SomeGroup{Label: proto.String("foo")}})
+		require.NoError(t, err)
+		require.Equal(t, `{"SomeGroup":{"label":"foo"}}`, s)
+	}
+}
+
+func TestMarshal_Mask(t *testing.T) {
+	stuff := marshalTestMessage()
+	stuff.SomeEmbeddedRep = []*validatortest.Message3_Embedded{{Identifier: "a", SomeValue: 1}, {Identifier: "b", SomeValue: 2}}
+	stuff.SomeStringToEmbedded = map[string]*validatortest.Message3_Embedded{"x": {Identifier: "x", SomeValue: 3}, "y": {Identifier: "y"}}
+	m := &nicejsonpb.Marshaler{Mask: &fieldmaskpb.FieldMask{Paths: []string{
+		"some_string", "some_embedded_rep.identifier", "some_string_to_embedded.x.some_value", "some_duration", "email",
+	}}}
+	s, err := m.MarshalToString(stuff)
+	require.NoError(t, err)
+	require.Equal(t, `{"someString":"foo","someEmbeddedRep":[{"identifier":"a"},{"identifier":"b"}],`+
+		`"someStringToEmbedded":{"x":{"someValue":"3"}},"someDuration":"1.500s","email":"a@b.c"}`, s)
+
+	// A field selected whole stays whole, even with paths within it.
+	m.Mask = &fieldmaskpb.FieldMask{Paths: []string{"some_embedded_rep", "some_embedded_rep.identifier", "phone"}}
+	s, err = m.MarshalToString(stuff)
+	require.NoError(t, err)
+	require.Equal(t, `{"someEmbeddedRep":[{"identifier":"a","someValue":"1"},{"identifier":"b","someValue":"2"}]}`, s)
+
+	m = &nicejsonpb.Marshaler{EmitDefaults: true, Mask: &fieldmaskpb.FieldMask{Paths: []string{"some_int32", "some_embedded.identifier"}}}
+	s, err = m.MarshalToString(&validatortest.Message3{})
+	require.NoError(t, err)
+	require.Equal(t, `{"someInt32":0,"someEmbedded":null}`, s)
+}
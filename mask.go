@@ -0,0 +1,68 @@
+package nicejsonpb
+
+import (
+	"io"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// UnmarshalWithMask unmarshals a JSON object stream into a protocol buffer like Unmarshal, and also
+// returns a FieldMask of the fields that were present in the JSON, including those set to their zero
+// value or null. This lets PATCH-style handlers tell a field that was cleared from one that was left
+// out. Fields within repeated fields, maps and extensions can't be expressed in a FieldMask, so the
+// mask stops at those.
+func (u *Unmarshaler) UnmarshalWithMask(r io.Reader, pb proto.Message) (*fieldmaskpb.FieldMask, error) {
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	if err := u.readDocument(r, inputValue); err != nil {
+		return nil, err
+	}
+	d := u.newDecodeState()
+	defer d.release()
+	d.recordFields = true
+	if err := d.unmarshalDocument(pb, *inputValue); err != nil {
+		return nil, err
+	}
+	return &fieldmaskpb.FieldMask{Paths: d.setFields}, nil
+}
+
+// UnmarshalWithMask unmarshals a JSON object stream into a protocol buffer, returning a FieldMask of
+// the fields that were present in the JSON.
+func UnmarshalWithMask(r io.Reader, pb proto.Message) (*fieldmaskpb.FieldMask, error) {
+	return new(Unmarshaler).UnmarshalWithMask(r, pb)
+}
+
+// maskTree is a FieldMask as a tree of the proto names of fields. A field mapped to nil is selected
+// whole, one mapped to a tree only for the fields in it. For repeated fields the tree applies to each
+// element, and for maps its names are keys of the map.
+type maskTree map[string]maskTree
+
+// newMaskTree returns the tree of mask, or nil, selecting everything, if mask has no paths.
+func newMaskTree(mask *fieldmaskpb.FieldMask) maskTree {
+	if len(mask.GetPaths()) == 0 {
+		return nil
+	}
+	root := maskTree{}
+	for _, path := range mask.GetPaths() {
+		node := root
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			child, ok := node[name]
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if ok && child == nil {
+				break // Already selected whole.
+			}
+			if !ok {
+				child = maskTree{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
@@ -0,0 +1,32 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalWithMask_RecordsPresentFields(t *testing.T) {
+	input := `{
+		"someString": "",
+		"some_embedded": {"someValue": 0, "children": [{"identifier": "not recorded"}]},
+		"someEmbeddedRep": [{"identifier": "not recorded"}],
+		"someWrappedInt": null,
+		"email": "foo@example.com"
+	}`
+	stuff := &validatortest.Message3{}
+	mask, err := nicejsonpb.UnmarshalWithMask(strings.NewReader(input), stuff)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"some_string",
+		"some_embedded",
+		"some_embedded.some_value",
+		"some_embedded.children",
+		"some_embedded_rep",
+		"some_wrapped_int",
+		"email",
+	}, mask.Paths)
+}
@@ -0,0 +1,48 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func existingMessage3() *validatortest.Message3 {
+	return &validatortest.Message3{
+		SomeString:        "existing",
+		SomeIntRep:        []uint32{1},
+		SomeEmbedded:      &validatortest.Message3_Embedded{Identifier: "existing", SomeValue: 1},
+		SomeStringToInt64: map[string]int64{"a": 1},
+	}
+}
+
+const mergeInput = `{"someIntRep": [2], "someEmbedded": {"someValue": 2}, "someStringToInt64": {"b": 2}}`
+
+func TestUnmarshal_ResetsTargetByDefault(t *testing.T) {
+	stuff := existingMessage3()
+	require.NoError(t, nicejsonpb.UnmarshalString(mergeInput, stuff))
+	require.Equal(t, "", stuff.SomeString)
+	require.Equal(t, []uint32{2}, stuff.SomeIntRep)
+	require.Equal(t, "", stuff.SomeEmbedded.Identifier)
+	require.Equal(t, map[string]int64{"b": 2}, stuff.SomeStringToInt64)
+}
+
+func TestUnmarshal_MergeInto(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{MergeInto: true}
+	stuff := existingMessage3()
+	require.NoError(t, u.Unmarshal(strings.NewReader(mergeInput), stuff))
+	require.Equal(t, "existing", stuff.SomeString)
+	require.Equal(t, []uint32{2}, stuff.SomeIntRep)
+	require.Equal(t, "existing", stuff.SomeEmbedded.Identifier)
+	require.Equal(t, int64(2), stuff.SomeEmbedded.SomeValue)
+	require.Equal(t, map[string]int64{"a": 1, "b": 2}, stuff.SomeStringToInt64)
+}
+
+func TestUnmarshal_MergeIntoAppendRepeated(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{MergeInto: true, AppendRepeated: true}
+	stuff := existingMessage3()
+	require.NoError(t, u.Unmarshal(strings.NewReader(mergeInput), stuff))
+	require.Equal(t, []uint32{1, 2}, stuff.SomeIntRep)
+}
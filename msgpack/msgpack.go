@@ -0,0 +1,75 @@
+// Package msgpack decodes MessagePack documents into protocol buffers, for services that accept
+// application/msgpack next to JSON. Documents are converted to JSON and decoded by a
+// nicejsonpb.Unmarshaler, so they are decoded with the same options and fail with the same field
+// errors as the equivalent JSON:
+//
+//	err := msgpack.UnmarshalMsgpack(r, pb)
+//
+// Binary values become strings in the encoding the Unmarshaler decodes bytes fields from,
+// timestamps RFC 3339 strings, and map keys of other types than strings are formatted the way JSON
+// map keys are.
+package msgpack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// UnmarshalMsgpack decodes the MessagePack document held in r into pb with the default options.
+func UnmarshalMsgpack(r io.Reader, pb proto.Message) error {
+	return UnmarshalMsgpackWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalMsgpackWith decodes the MessagePack document held in r into pb with the options of u.
+func UnmarshalMsgpackWith(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetMapDecoder(func(dec *msgpack.Decoder) (interface{}, error) {
+		return dec.DecodeUntypedMap()
+	})
+	doc, err := dec.DecodeInterface()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(jsonValue(u, doc))
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBytes(b, pb)
+}
+
+// jsonValue converts v, a decoded MessagePack value, to one encoding/json marshals the way the JSON
+// mapping of protocol buffers wants it, and u decodes.
+func jsonValue(u *nicejsonpb.Unmarshaler, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			obj[fmt.Sprint(k)] = jsonValue(u, elem)
+		}
+		return obj
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = jsonValue(u, elem)
+		}
+	case []byte:
+		return u.EncodeBytes(v)
+	case float32:
+		return jsonValue(u, float64(v))
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN"
+		case math.IsInf(v, 1):
+			return "Infinity"
+		case math.IsInf(v, -1):
+			return "-Infinity"
+		}
+	}
+	return v
+}
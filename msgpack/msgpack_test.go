@@ -0,0 +1,69 @@
+package msgpack_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	nicemsgpack "github.com/mwitkow/go-nicejsonpb/msgpack"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func encode(t *testing.T, v interface{}) *bytes.Reader {
+	b, err := msgpack.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewReader(b)
+}
+
+func TestUnmarshalMsgpack(t *testing.T) {
+	doc := map[string]interface{}{
+		"someString":        "hello",
+		"some_int64":        int64(math.MaxInt64),
+		"someUint32":        uint8(7),
+		"someFloat":         float32(math.Inf(1)),
+		"someBytes":         []byte{0, 1, 2},
+		"someStringRep":     []string{"a", "b"},
+		"someInt32ToString": map[int32]string{-1: "minus one"},
+		"someEmbedded":      map[string]interface{}{"identifier": "x", "children": []interface{}{map[string]interface{}{"someValue": 3}}},
+		"someTimestamp":     time.Date(2024, 5, 1, 10, 0, 0, 5, time.UTC),
+	}
+	m := &validatortest.Message3{}
+	require.NoError(t, nicemsgpack.UnmarshalMsgpack(encode(t, doc), m))
+	require.Equal(t, "hello", m.SomeString)
+	require.Equal(t, int64(math.MaxInt64), m.SomeInt64)
+	require.Equal(t, uint32(7), m.SomeUint32)
+	require.True(t, math.IsInf(float64(m.SomeFloat), 1))
+	require.Equal(t, []byte{0, 1, 2}, m.SomeBytes)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, map[int32]string{-1: "minus one"}, m.SomeInt32ToString)
+	require.Equal(t, int64(3), m.SomeEmbedded.Children[0].SomeValue)
+	require.Equal(t, int32(5), m.SomeTimestamp.Nanos)
+}
+
+func TestUnmarshalMsgpack_FieldErrors(t *testing.T) {
+	doc := map[string]interface{}{"someEmbedded": map[string]interface{}{"children": []interface{}{map[string]interface{}{"someValue": true}}}}
+	err := nicemsgpack.UnmarshalMsgpack(encode(t, doc), &validatortest.Message3{})
+	want := nicejsonpb.UnmarshalString(`{"someEmbedded": {"children": [{"someValue": true}]}}`, &validatortest.Message3{})
+	require.EqualError(t, err, want.Error())
+	require.Equal(t, "/someEmbedded/children/0/someValue", nicejsonpb.ErrorPointer(err))
+
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, nicemsgpack.UnmarshalMsgpackWith(u, encode(t, map[string]int{"other": 1}), &validatortest.Message3{}))
+}
+
+func TestUnmarshalMsgpack_BytesEncoding(t *testing.T) {
+	for _, u := range []*nicejsonpb.Unmarshaler{
+		{BytesEncoding: nicejsonpb.BytesHex},
+		{BytesEncoding: nicejsonpb.BytesAuto},
+		{Profile: nicejsonpb.ProfileLenient},
+	} {
+		m := &validatortest.Message3{}
+		// In base64, "abcd", which also reads as hex.
+		require.NoError(t, nicemsgpack.UnmarshalMsgpackWith(u, encode(t, map[string]interface{}{"someBytes": []byte{0x69, 0xb7, 0x1d}}), m))
+		require.Equal(t, []byte{0x69, 0xb7, 0x1d}, m.SomeBytes)
+	}
+}
@@ -0,0 +1,56 @@
+package nicejsonpb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// StreamDecoder reads newline-delimited JSON (JSON Lines), where every non-blank line holds exactly one
+// message. Errors are prefixed with the number of the offending line.
+type StreamDecoder struct {
+	u    *Unmarshaler
+	r    *bufio.Reader
+	line int
+}
+
+// NewStreamDecoder returns a StreamDecoder reading from r, using the options of u.
+func (u *Unmarshaler) NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{u: u, r: bufio.NewReader(r)}
+}
+
+// NewStreamDecoder returns a StreamDecoder reading from r with the default options.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return new(Unmarshaler).NewStreamDecoder(r)
+}
+
+// Decode unmarshals the next non-blank line into pb, which should be a fresh message.
+// It returns io.EOF once the input is exhausted.
+func (d *StreamDecoder) Decode(pb proto.Message) error {
+	for {
+		line, readErr := d.r.ReadBytes('\n')
+		if len(line) == 0 && readErr != nil {
+			return readErr
+		}
+		d.line++
+		line = bytes.Trim(line, jsonWhitespace)
+		if len(line) == 0 {
+			if readErr != nil {
+				return readErr
+			}
+			continue
+		}
+		if err := d.u.UnmarshalBytes(line, pb); err != nil {
+			return fmt.Errorf("line %d: %w", d.line, err)
+		}
+		return nil
+	}
+}
+
+// Line returns the number of the line last read by Decode, starting at 1.
+func (d *StreamDecoder) Line() int {
+	return d.line
+}
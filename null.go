@@ -0,0 +1,111 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NullHandling controls what a JSON null does to the field it is set on. Proto3 optional fields are
+// always unset by null, which is how their absence is written, whatever the NullHandling.
+type NullHandling int
+
+const (
+	// NullAsUnset treats a field set to null as if it was absent from the JSON. This matches protojson
+	// and is the default.
+	NullAsUnset NullHandling = iota
+	// NullAsDefault resets a field set to null to its default value. Message fields are set to an empty
+	// message rather than left nil. Map values set to null, which otherwise fail the unmarshal as they
+	// do with protojson, are stored as their default value too.
+	NullAsDefault
+	// NullIsError fails the unmarshal on any field set to null.
+	NullIsError
+)
+
+// errNullNotAllowed is returned for fields set to null when NullHandling is NullIsError.
+var errNullNotAllowed = newCodedError(CodeTypeMismatch, "null is not allowed")
+
+// jsonNull is the JSON value null errors are about.
+var jsonNull = json.RawMessage("null")
+
+// errNullElement is returned for null elements of repeated fields, unless SkipNullElements is set.
+var errNullElement = newCodedError(CodeTypeMismatch, "null elements are not allowed in repeated fields")
+
+// errNullDocument is returned for a document that is null rather than an object, when the profile
+// doesn't allow it.
+var errNullDocument = newCodedError(CodeTypeMismatch, "null isn't a message, expected a JSON object")
+
+// errNullMapValue is returned for null map values, unless NullHandling is NullAsDefault.
+var errNullMapValue = newCodedError(CodeTypeMismatch, "null values are not allowed in maps")
+
+// isNull reports whether raw is the JSON null literal.
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(raw, []byte("null"))
+}
+
+// acceptsNull reports whether JSON null is a regular value for a field rather than the absence of
+// one, which is only the case for google.protobuf.Value and google.protobuf.NullValue.
+func acceptsNull(t reflect.Type, prop *proto.Properties) bool {
+	if prop != nil && prop.Enum == "google.protobuf.NullValue" {
+		return true
+	}
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		return wellKnownType(reflect.New(t.Elem()).Elem()) == "Value"
+	}
+	return false
+}
+
+// proto3Optional reports whether the field with the given number of the message held in target is a
+// proto3 optional field.
+func proto3Optional(target proto.Message, number int) bool {
+	fd := proto.MessageReflect(target).Descriptor().Fields().ByNumber(protoreflect.FieldNumber(number))
+	return fd != nil && isProto3Optional(fd)
+}
+
+// isProto3Optional reports whether fd is a proto3 optional field, which tracks its presence with a
+// synthetic oneof of its own.
+func isProto3Optional(fd protoreflect.FieldDescriptor) bool {
+	oo := fd.ContainingOneof()
+	return oo != nil && oo.IsSynthetic()
+}
+
+// unmarshalNullField applies the NullHandling to the field called name, with the given key in the
+// JSON, which was set to null. It returns false if the field is to be left unset.
+func (d *decodeState) unmarshalNullField(name string, key string, target reflect.Value) (bool, error) {
+	switch d.NullHandling {
+	case NullIsError:
+		return false, d.withRaw(fieldErrorAt(name, key, errNullNotAllowed), jsonNull)
+	case NullAsDefault:
+		target.Set(reflect.Zero(target.Type()))
+		if target.Kind() == reflect.Ptr && target.Type().Elem().Kind() == reflect.Struct {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// dynamicAcceptsNull is the counterpart of acceptsNull for dynamic messages.
+func dynamicAcceptsNull(fd protoreflect.FieldDescriptor) bool {
+	if fd.Enum() != nil && fd.Enum().FullName() == "google.protobuf.NullValue" {
+		return true
+	}
+	return fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Value"
+}
+
+// unmarshalDynamicNullField is the counterpart of unmarshalNullField for dynamic messages.
+func (d *decodeState) unmarshalDynamicNullField(name string, key string, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	switch d.NullHandling {
+	case NullIsError:
+		return d.withRaw(fieldErrorAt(name, key, errNullNotAllowed), jsonNull)
+	case NullAsDefault:
+		m.Clear(fd)
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			m.Mutable(fd)
+		}
+	}
+	return nil
+}
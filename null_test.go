@@ -0,0 +1,118 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const allNullsInput = `{"someString": null, "someIntRep": null, "someEmbedded": null, "someStringToInt64": null, "someWrappedInt": null, "address": null}`
+
+func TestUnmarshal_NullLeavesFieldsUnsetByDefault(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{MergeInto: true}
+	stuff := &validatortest.Message3{SomeString: "keep"}
+	require.NoError(t, u.Unmarshal(strings.NewReader(allNullsInput), stuff))
+	require.Equal(t, "keep", stuff.SomeString)
+	require.Nil(t, stuff.SomeIntRep)
+	require.Nil(t, stuff.SomeEmbedded)
+	require.Nil(t, stuff.SomeStringToInt64)
+	require.Nil(t, stuff.SomeWrappedInt)
+	require.Nil(t, stuff.Contact)
+}
+
+func TestUnmarshal_NullAsDefault(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{NullHandling: nicejsonpb.NullAsDefault, MergeInto: true}
+	stuff := &validatortest.Message3{SomeString: "reset"}
+	require.NoError(t, u.Unmarshal(strings.NewReader(allNullsInput), stuff))
+	require.Equal(t, "", stuff.SomeString)
+	require.NotNil(t, stuff.SomeEmbedded)
+	require.NotNil(t, stuff.SomeWrappedInt)
+	require.NotNil(t, stuff.GetAddress())
+}
+
+func TestUnmarshal_NullIsError(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{NullHandling: nicejsonpb.NullIsError}
+	err := u.Unmarshal(strings.NewReader(`{"someEmbedded": {"identifier": null}}`), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Identifier: null is not allowed")
+}
+
+func TestUnmarshal_NullElements(t *testing.T) {
+	input := `{"someEmbeddedRep": [{"identifier": "a"}, null, {"identifier": "b"}], "someStringRep": ["x", null]}`
+	err := nicejsonpb.UnmarshalString(input, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeStringRep.[1]: null elements are not allowed in repeated fields")
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeStringRep.[1]: null elements are not allowed in repeated fields")
+	err = nicejsonpb.UnmarshalString(`{"someEmbeddedRep": [{}, null]}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbeddedRep.[1]: null elements are not allowed in repeated fields")
+
+	u := &nicejsonpb.Unmarshaler{SkipNullElements: true}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Len(t, stuff.SomeEmbeddedRep, 2)
+	require.Equal(t, "b", stuff.SomeEmbeddedRep[1].Identifier)
+	require.Equal(t, []string{"x"}, stuff.SomeStringRep)
+}
+
+func TestUnmarshal_NullUnsetsProto3Optional(t *testing.T) {
+	input := `{"someOptionalString": null, "someOptionalInt32": null}`
+	for _, h := range []nicejsonpb.NullHandling{nicejsonpb.NullAsUnset, nicejsonpb.NullAsDefault, nicejsonpb.NullIsError} {
+		u := &nicejsonpb.Unmarshaler{NullHandling: h, MergeInto: true}
+		stuff := &validatortest.Message3{SomeOptionalString: proto.String("keep"), SomeOptionalInt32: proto.Int32(3)}
+		require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+		require.Nil(t, stuff.SomeOptionalString)
+		require.Nil(t, stuff.SomeOptionalInt32)
+
+		desc := stuff.ProtoReflect().Descriptor()
+		m, err := u.UnmarshalDynamic(strings.NewReader(input), desc)
+		require.NoError(t, err)
+		require.False(t, m.Has(desc.Fields().ByName("some_optional_string")))
+	}
+
+	stuff := &validatortest.Message3{SomeOptionalInt32: proto.Int32(3)}
+	require.NoError(t, nicejsonpb.ApplyJSONPatch([]byte(`[{"op": "replace", "path": "/someOptionalInt32", "value": null}]`), stuff))
+	require.Nil(t, stuff.SomeOptionalInt32)
+}
+
+func TestUnmarshal_Proto3OptionalPresence(t *testing.T) {
+	input := `{"someOptionalString": "", "someOptionalInt32": 0}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.NotNil(t, stuff.SomeOptionalString)
+	require.NotNil(t, stuff.SomeOptionalInt32)
+	wire, err := proto.Marshal(stuff)
+	require.NoError(t, err)
+	require.NotEmpty(t, wire)
+
+	desc := stuff.ProtoReflect().Descriptor()
+	m, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.NoError(t, err)
+	require.True(t, m.Has(desc.Fields().ByName("some_optional_string")))
+	require.True(t, m.Has(desc.Fields().ByName("some_optional_int32")))
+}
+
+func TestUnmarshal_NullMapValues(t *testing.T) {
+	for _, input := range []string{
+		`{"someStringToStatus": {"a": null}}`,
+		`{"someStringToEmbedded": {"a": null}}`,
+		`{"someStringToInt64": {"a": null}}`,
+	} {
+		err := nicejsonpb.UnmarshalString(input, &validatortest.Message3{})
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), "null values are not allowed in maps", input)
+		require.Equal(t, nicejsonpb.CodeTypeMismatch, nicejsonpb.ErrorCode(err), input)
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(input), (&validatortest.Message3{}).ProtoReflect().Descriptor())
+		require.Error(t, err, input)
+	}
+	err := nicejsonpb.UnmarshalString(`{"someStringToInt64": {"a/b": null}}`, &validatortest.Message3{})
+	require.Equal(t, "/someStringToInt64/a~1b", nicejsonpb.ErrorPointer(err))
+
+	u := &nicejsonpb.Unmarshaler{NullHandling: nicejsonpb.NullAsDefault}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someStringToInt64": {"a": null}, "someStringToEmbedded": {"b": null}}`), stuff))
+	require.Equal(t, map[string]int64{"a": 0}, stuff.SomeStringToInt64)
+	require.NotNil(t, stuff.SomeStringToEmbedded["b"])
+}
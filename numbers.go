@@ -0,0 +1,179 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseInt decodes raw as a signed integer of the given bit size.
+func (d *decodeState) parseInt(raw json.RawMessage, bits int) (int64, error) {
+	goType := reflect.TypeOf(int64(0))
+	if bits == 32 {
+		goType = reflect.TypeOf(int32(0))
+	}
+	s, err := d.integerLiteral(raw, goType)
+	if err != nil || s == "" {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(s, 10, bits)
+	if errors.Is(err, strconv.ErrSyntax) {
+		var integral string
+		if integral, err = d.integralLiteral(s, goType); err != nil {
+			return 0, err
+		}
+		n, err = strconv.ParseInt(integral, 10, bits)
+	}
+	if err != nil {
+		return 0, codeErrorf(CodeOutOfRange, "value %s out of range for %v", d.echo("%s", "number", s), goType)
+	}
+	return n, nil
+}
+
+// parseUint decodes raw as an unsigned integer of the given bit size.
+func (d *decodeState) parseUint(raw json.RawMessage, bits int) (uint64, error) {
+	goType := reflect.TypeOf(uint64(0))
+	if bits == 32 {
+		goType = reflect.TypeOf(uint32(0))
+	}
+	s, err := d.integerLiteral(raw, goType)
+	if err != nil || s == "" {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(s, 10, bits)
+	if errors.Is(err, strconv.ErrSyntax) {
+		// Negative zeros, such as -0 or -0.0, are zero.
+		if mantissa, _, _ := strings.Cut(strings.ToLower(s), "e"); strings.HasPrefix(s, "-") && strings.Trim(mantissa, "-0.") != "" {
+			return 0, codeErrorf(CodeOutOfRange, "value %s out of range for %v", d.echo("%s", "number", s), goType)
+		}
+		var integral string
+		if integral, err = d.integralLiteral(s, goType); err != nil {
+			return 0, err
+		}
+		n, err = strconv.ParseUint(integral, 10, bits)
+	}
+	if err != nil {
+		return 0, codeErrorf(CodeOutOfRange, "value %s out of range for %v", d.echo("%s", "number", s), goType)
+	}
+	return n, nil
+}
+
+// integerLiteral returns the JSON number held in raw, which for 64-bit integers, or all of them with
+// AcceptStringNumbers, may also be quoted. Values that aren't numbers at all fail the same way as with
+// encoding/json into goType, except for null which gives an empty literal.
+func (d *decodeState) integerLiteral(raw json.RawMessage, goType reflect.Type) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' && d.rejectQuotedIntegers {
+		return "", codeErrorf(CodeTypeMismatch, "quoted integer %s is not allowed, expected a JSON number", d.echo("%s", "string", string(raw)))
+	}
+	if len(raw) > 0 && raw[0] == '"' && (goType.Bits() == 64 || d.AcceptStringNumbers) {
+		s := raw[1 : len(raw)-1]
+		if !isNumberLiteral(s) {
+			return "", codeErrorf(CodeTypeMismatch, "%v while looking for an integer in a string", json.Unmarshal(s, reflect.New(goType).Interface()))
+		}
+		return string(s), nil
+	}
+	if !isNumberLiteral(raw) {
+		return "", json.Unmarshal(raw, reflect.New(goType).Interface())
+	}
+	return string(raw), nil
+}
+
+// maxIntegerExponent bounds the exponents accepted in numbers for integer fields.
+const maxIntegerExponent = 1000
+
+// integralLiteral converts number literals that have a fraction or an exponent, such as 1e3 or 2.0,
+// to plain integer literals as allowed by the proto3 JSON mapping. Those that aren't integral fail,
+// unless CoerceFloatsToInts is set in which case they are truncated.
+func (d *decodeState) integralLiteral(s string, goType reflect.Type) (string, error) {
+	// Don't let big.Rat expand huge exponents, no integer field can hold them.
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if exp, err := strconv.Atoi(s[i+1:]); err != nil || exp > maxIntegerExponent || exp < -maxIntegerExponent {
+			return "", codeErrorf(CodeOutOfRange, "value %s out of range for %v", d.echo("%s", "number", s), goType)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return "", codeErrorf(CodeTypeMismatch, "value %s is not a number", d.echo("%s", "number", s))
+	}
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	if !d.CoerceFloatsToInts {
+		return "", codeErrorf(CodeTypeMismatch, "value %s is not an integer, expected %v", d.echo("%s", "number", s), goType)
+	}
+	truncated := new(big.Int).Quo(r.Num(), r.Denom()).String()
+	d.warn(WarnLossyConversion, "", "value %s truncated to %s for %v", d.echo("%s", "number", s), d.echo("%s", "number", truncated), goType)
+	return truncated, nil
+}
+
+// nonFiniteFloats are the string encodings of the non-finite floating point values.
+var nonFiniteFloats = map[string]float64{
+	"NaN":       math.NaN(),
+	"Infinity":  math.Inf(1),
+	"-Infinity": math.Inf(-1),
+}
+
+// parseFloat decodes raw as a floating point number of the given bit size. The non-finite values are
+// given as the strings "NaN", "Infinity" and "-Infinity", and with AcceptStringNumbers any number may
+// be quoted.
+func (d *decodeState) parseFloat(raw json.RawMessage, bits int) (float64, error) {
+	goType := reflect.TypeOf(float64(0))
+	if bits == 32 {
+		goType = reflect.TypeOf(float32(0))
+	}
+	if len(raw) > 0 && raw[0] == '"' {
+		s := raw[1 : len(raw)-1]
+		if v, ok := nonFiniteFloats[string(s)]; ok {
+			if d.RejectNonFiniteFloats {
+				return 0, codeErrorf(CodeOutOfRange, "non-finite value %s is not allowed", raw)
+			}
+			return v, nil
+		}
+		if !d.AcceptStringNumbers || !isNumberLiteral(s) {
+			return 0, json.Unmarshal(raw, reflect.New(goType).Interface())
+		}
+		raw = s
+	}
+	if !isNumberLiteral(raw) {
+		return 0, json.Unmarshal(raw, reflect.New(goType).Interface())
+	}
+	v, err := strconv.ParseFloat(string(raw), bits)
+	if err != nil {
+		return 0, codeErrorf(CodeOutOfRange, "value %s out of range for %v", d.echo("%s", "number", string(raw)), goType)
+	}
+	if d.ExactNumbers && !isExactFloat(string(raw), v, bits) {
+		return 0, codeErrorf(CodeOutOfRange, "value %s can't be represented exactly as %v, it would be rounded to %s", d.echo("%s", "number", string(raw)), goType, d.echo("%s", "number", strconv.FormatFloat(v, 'g', -1, bits)))
+	}
+	return v, nil
+}
+
+// isExactFloat reports whether the number literal s survives a round trip through v, the floating
+// point number of the given bit size it was parsed to: formatting v as the shortest literal that
+// parses back to it must give the same number. A literal like 0.1 does, even though it has no exact
+// binary representation, one with more significant digits than the type holds doesn't.
+func isExactFloat(s string, v float64, bits int) bool {
+	// Don't let big.Rat expand huge exponents, such numbers are out of the range of floats anyway.
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if exp, err := strconv.Atoi(s[i+1:]); err != nil || exp > maxIntegerExponent || exp < -maxIntegerExponent {
+			return false
+		}
+	}
+	want, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return false
+	}
+	got, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, bits))
+	return ok && want.Cmp(got) == 0
+}
+
+// isNumberLiteral reports whether b is a single JSON number.
+func isNumberLiteral(b []byte) bool {
+	if len(b) == 0 || (b[0] != '-' && (b[0] < '0' || b[0] > '9')) {
+		return false
+	}
+	return json.Valid(b)
+}
@@ -0,0 +1,165 @@
+package nicejsonpb_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_IntegerRangeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`{"someUint32": 4294967296}`, "unparsable field SomeUint32: value 4294967296 out of range for uint32"},
+		{`{"someUint32": -1}`, "unparsable field SomeUint32: value -1 out of range for uint32"},
+		{`{"someInt32": -2147483649}`, "unparsable field SomeInt32: value -2147483649 out of range for int32"},
+		{`{"someInt64": "9223372036854775808"}`, "unparsable field SomeInt64: value 9223372036854775808 out of range for int64"},
+		{`{"someUint64": -5}`, "unparsable field SomeUint64: value -5 out of range for uint64"},
+		{`{"someEmbedded": {"someValue": 9223372036854775808}}`, "unparsable field SomeEmbedded.SomeValue: value 9223372036854775808 out of range for int64"},
+		{`{"someIntRep": [1, 4294967296]}`, "unparsable field SomeIntRep.[1]: value 4294967296 out of range for uint32"},
+	} {
+		err := nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{})
+		require.EqualError(t, err, tc.err, tc.input)
+
+		_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestUnmarshal_IntegerLimits(t *testing.T) {
+	input := `{"someInt32": -2147483648, "someUint32": 4294967295, "someInt64": "-9223372036854775808", "someUint64": 18446744073709551615}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Equal(t, int32(-2147483648), stuff.SomeInt32)
+	require.Equal(t, uint32(4294967295), stuff.SomeUint32)
+	require.Equal(t, int64(-9223372036854775808), stuff.SomeInt64)
+	require.Equal(t, uint64(18446744073709551615), stuff.SomeUint64)
+}
+
+func TestUnmarshal_FractionalIntegers(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someEmbedded": {"someValue": 3.5}}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded.SomeValue: value 3.5 is not an integer, expected int64")
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"someUint32": 1.5}`), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeUint32: value 1.5 is not an integer, expected uint32")
+	err = nicejsonpb.UnmarshalString(`{"someInt32": 1e999999999}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeInt32: value 1e999999999 out of range for int32")
+}
+
+func TestUnmarshal_CoerceFloatsToInts(t *testing.T) {
+	warnings := []string{}
+	u := &nicejsonpb.Unmarshaler{
+		CoerceFloatsToInts: true,
+		OnWarning: func(w nicejsonpb.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	}
+	input := `{"someInt32": -3.9, "someUint64": "7.25", "someIntRep": [1.5]}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, int32(-3), stuff.SomeInt32)
+	require.Equal(t, uint64(7), stuff.SomeUint64)
+	require.Equal(t, []uint32{1}, stuff.SomeIntRep)
+	require.Equal(t, []string{
+		"field SomeInt32: value -3.9 truncated to -3 for int32",
+		"field SomeUint64: value 7.25 truncated to 7 for uint64",
+		"field SomeIntRep.[0]: value 1.5 truncated to 1 for uint32",
+	}, warnings)
+
+	err := u.Unmarshal(strings.NewReader(`{"someUint32": -0.5}`), stuff)
+	require.EqualError(t, err, "unparsable field SomeUint32: value -0.5 out of range for uint32")
+
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someUint32": -0.0, "someUint64": "-0"}`), stuff))
+	require.Zero(t, stuff.SomeUint32)
+}
+
+func TestUnmarshal_NegativeZeroUnsigned(t *testing.T) {
+	stuff := &validatortest.Message3{SomeUint32: 1, SomeUint64: 1}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someUint32": -0, "someUint64": -0e3, "someIntRep": [-0.0]}`, stuff))
+	require.Zero(t, stuff.SomeUint32)
+	require.Zero(t, stuff.SomeUint64)
+	require.Equal(t, []uint32{0}, stuff.SomeIntRep)
+}
+
+func TestUnmarshal_IntegralNumberForms(t *testing.T) {
+	input := `{"someInt32": 1e3, "someUint32": 2.0, "someInt64": "-1.5E2", "someUint64": 12.5e1, "someIntRep": [1E+1]}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.Equal(t, int32(1000), stuff.SomeInt32)
+	require.Equal(t, uint32(2), stuff.SomeUint32)
+	require.Equal(t, int64(-150), stuff.SomeInt64)
+	require.Equal(t, uint64(125), stuff.SomeUint64)
+	require.Equal(t, []uint32{10}, stuff.SomeIntRep)
+
+	err := nicejsonpb.UnmarshalString(`{"someInt32": 3e9}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeInt32: value 3e9 out of range for int32")
+	err = nicejsonpb.UnmarshalString(`{"someInt32": 1.5e-1}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeInt32: value 1.5e-1 is not an integer, expected int32")
+}
+
+func TestUnmarshal_NonFiniteFloats(t *testing.T) {
+	input := `{"someFloat": "NaN", "someDouble": "-Infinity"}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(input, stuff))
+	require.True(t, math.IsNaN(float64(stuff.SomeFloat)))
+	require.True(t, math.IsInf(stuff.SomeDouble, -1))
+
+	msg, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"someDouble": "Infinity"}`), message3Descriptor(t))
+	require.NoError(t, err)
+	require.True(t, math.IsInf(msg.Get(msg.Descriptor().Fields().ByName("some_double")).Float(), 1))
+
+	u := &nicejsonpb.Unmarshaler{RejectNonFiniteFloats: true}
+	err = u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeFloat: non-finite value "NaN" is not allowed`)
+	err = nicejsonpb.UnmarshalString(`{"someFloat": "nan"}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeFloat: json: cannot unmarshal string into Go value of type float32")
+	err = nicejsonpb.UnmarshalString(`{"someFloat": 1e39}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeFloat: value 1e39 out of range for float32")
+}
+
+func TestUnmarshal_AcceptStringNumbers(t *testing.T) {
+	input := `{"someInt32": "-12", "someUint32": "1e2", "someFloat": "1.5", "someDouble": "-2.25", "someIntRep": ["3"]}`
+	err := nicejsonpb.UnmarshalString(input, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeInt32: json: cannot unmarshal string into Go value of type int32")
+
+	u := &nicejsonpb.Unmarshaler{AcceptStringNumbers: true}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, int32(-12), stuff.SomeInt32)
+	require.Equal(t, uint32(100), stuff.SomeUint32)
+	require.Equal(t, float32(1.5), stuff.SomeFloat)
+	require.Equal(t, -2.25, stuff.SomeDouble)
+	require.Equal(t, []uint32{3}, stuff.SomeIntRep)
+
+	err = u.Unmarshal(strings.NewReader(`{"someDouble": "1.5x"}`), stuff)
+	require.EqualError(t, err, "unparsable field SomeDouble: json: cannot unmarshal string into Go value of type float64")
+}
+
+func TestUnmarshal_ExactNumbers(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithExactNumbers())
+	m := &validatortest.Message3{}
+	input := `{"someDouble": 9007199254740992, "someFloat": 0.1, "someUint64": 18446744073709551615, "someInt64": 9223372036854775807}`
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, float64(9007199254740992), m.SomeDouble)
+	require.Equal(t, uint64(18446744073709551615), m.SomeUint64)
+	require.Equal(t, int64(9223372036854775807), m.SomeInt64)
+
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`{"someDouble": 9007199254740993}`, "unparsable field SomeDouble: value 9007199254740993 can't be represented exactly as float64, it would be rounded to 9.007199254740992e+15"},
+		{`{"someFloat": 16777217}`, "unparsable field SomeFloat: value 16777217 can't be represented exactly as float32, it would be rounded to 1.6777216e+07"},
+		{`{"someDouble": 3.14159265358979323846}`, "unparsable field SomeDouble: value 3.14159265358979323846 can't be represented exactly as float64, it would be rounded to 3.141592653589793"},
+	} {
+		err := u.Unmarshal(strings.NewReader(tc.input), &validatortest.Message3{})
+		require.EqualError(t, err, tc.err, tc.input)
+		_, err = u.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.EqualError(t, err, tc.err, tc.input)
+		require.NoError(t, nicejsonpb.UnmarshalString(tc.input, &validatortest.Message3{}), "rounding is allowed by default")
+	}
+}
@@ -0,0 +1,47 @@
+package nicejsonpb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Observer is told about every document an Unmarshaler decodes, to monitor decoding in production.
+// Its methods are called from the goroutine doing the unmarshal, and must be safe for concurrent use
+// when the Unmarshaler is.
+type Observer interface {
+	// OnStart is called as decoding a document into a message of the given type starts, with the
+	// context of the unmarshal, or context.Background() if there is none. The context it returns is
+	// handed to the other methods for the same document.
+	OnStart(ctx context.Context, message protoreflect.FullName) context.Context
+	// OnUnknownField is called for every object key matching no field, at path, named as in field
+	// errors, whether unknown fields are allowed or not.
+	OnUnknownField(ctx context.Context, path string, key string)
+	// OnFinish is called once the document is decoded, with the error of the unmarshal if it
+	// failed.
+	OnFinish(ctx context.Context, message protoreflect.FullName, err error, stats Stats)
+}
+
+// observe runs decode, decoding inputValue into a message of the given type, telling the Observer
+// about it.
+func (d *decodeState) observe(message protoreflect.FullName, inputValue json.RawMessage, decode func() error) error {
+	start := time.Now()
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if d.stats == nil {
+		d.stats = new(Stats)
+	}
+	if d.observerCtx = d.Observer.OnStart(ctx, message); d.observerCtx == nil {
+		d.observerCtx = ctx
+	}
+	err := decode()
+	d.stats.Bytes = len(inputValue)
+	d.stats.Nodes = d.nodes
+	d.stats.Duration = time.Since(start)
+	d.Observer.OnFinish(d.observerCtx, message, err, *d.stats)
+	return err
+}
@@ -0,0 +1,53 @@
+package nicejsonpb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type ctxKey struct{}
+
+type recordingObserver struct {
+	events []string
+	stats  []nicejsonpb.Stats
+}
+
+func (r *recordingObserver) OnStart(ctx context.Context, message protoreflect.FullName) context.Context {
+	r.events = append(r.events, "start "+string(message))
+	return context.WithValue(ctx, ctxKey{}, "call")
+}
+
+func (r *recordingObserver) OnUnknownField(ctx context.Context, path string, key string) {
+	r.events = append(r.events, "unknown "+ctx.Value(ctxKey{}).(string)+" "+path+" "+key)
+}
+
+func (r *recordingObserver) OnFinish(ctx context.Context, message protoreflect.FullName, err error, stats nicejsonpb.Stats) {
+	r.events = append(r.events, "finish "+ctx.Value(ctxKey{}).(string)+" "+nicejsonpb.ErrorCode(err).String())
+	r.stats = append(r.stats, stats)
+}
+
+func TestUnmarshal_Observer(t *testing.T) {
+	o := &recordingObserver{}
+	u := nicejsonpb.New(nicejsonpb.WithObserver(o))
+	input := `{"someString": "a", "someEmbedded": {"wat": 1}}`
+	require.Error(t, u.Unmarshal(strings.NewReader(input), &validatortest.Message3{}))
+	_, err := u.UnmarshalDynamic(strings.NewReader(`{"someString": "a"}`), message3Descriptor(t))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"start validatortest.Message3",
+		"unknown call SomeEmbedded wat",
+		"finish call UnknownField",
+		"start validatortest.Message3",
+		"finish call None",
+	}, o.events)
+	require.Equal(t, len(input), o.stats[0].Bytes)
+	require.Equal(t, 2, o.stats[0].Fields)
+	require.Equal(t, 1, o.stats[0].UnknownFields)
+}
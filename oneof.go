@@ -0,0 +1,28 @@
+package nicejsonpb
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// sortedOneofTypes returns the oneof members of a message in field number order, so that they are
+// always decoded in the same order.
+func sortedOneofTypes(sprops *proto.StructProperties) []*proto.OneofProperties {
+	oops := make([]*proto.OneofProperties, 0, len(sprops.OneofTypes))
+	for _, oop := range sprops.OneofTypes {
+		oops = append(oops, oop)
+	}
+	sort.Slice(oops, func(i, j int) bool { return oops[i].Prop.Tag < oops[j].Prop.Tag })
+	return oops
+}
+
+// checkOneofConflict fails if a member of the oneof called oneofName, identified by key, was already
+// set in the message being decoded, as tracked in set. Otherwise it records name as the member set.
+func (d *decodeState) checkOneofConflict(set map[int]string, key int, name string, oneofName string) error {
+	if first, ok := set[key]; ok && !d.AllowOneofConflicts {
+		return codeErrorf(CodeOneofConflict, "fields %s and %s belong to oneof %s; only one may be set", first, name, oneofName)
+	}
+	set[key] = name
+	return nil
+}
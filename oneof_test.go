@@ -0,0 +1,38 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_OneofConflict(t *testing.T) {
+	input := `{"email": "foo@example.com", "phone": "555-1234"}`
+	err := nicejsonpb.UnmarshalString(input, &validatortest.Message3{})
+	require.EqualError(t, err, "fields email and phone belong to oneof contact; only one may be set")
+	_, err = nicejsonpb.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, "fields email and phone belong to oneof contact; only one may be set")
+
+	err = nicejsonpb.UnmarshalString(`{"someEmbedded": {"children": [{}]}, "address": {}, "email": "x"}`, &validatortest.Message3{})
+	require.EqualError(t, err, "fields email and address belong to oneof contact; only one may be set")
+}
+
+func TestUnmarshal_AllowOneofConflicts(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{AllowOneofConflicts: true}
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"phone": "555-1234", "email": "foo@example.com"}`), stuff))
+	require.Equal(t, "555-1234", stuff.GetPhone())
+}
+
+func TestUnmarshal_UnknownFieldListsOneofMembers(t *testing.T) {
+	err := nicejsonpb.UnmarshalString(`{"someEmbedded": {"id": "x"}}`, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded: fields [id] do not exist in set of known fields [children identifier some_value/someValue]")
+
+	err = nicejsonpb.UnmarshalString(`{"mail": "x"}`, &validatortest.Message3{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "known fields [address email phone some_bool/someBool ")
+	require.NotContains(t, err.Error(), "contact")
+}
@@ -0,0 +1,313 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Option configures an Unmarshaler, see New and With.
+type Option func(*Unmarshaler)
+
+// New returns an Unmarshaler configured by the given options. It is the same as setting the
+// corresponding fields of an Unmarshaler literal.
+func New(opts ...Option) *Unmarshaler {
+	u := &Unmarshaler{}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Clone returns a copy of u that can be changed, including through RegisterTypeHandler, without
+// affecting u. The copy starts with a DocumentCount of zero.
+func (u *Unmarshaler) Clone() *Unmarshaler {
+	c := u.options()
+	c.documents = 0
+	if u.typeHandlers != nil {
+		c.typeHandlers = make(map[string]TypeHandler, len(u.typeHandlers))
+		for name, fn := range u.typeHandlers {
+			c.typeHandlers[name] = fn
+		}
+	}
+	return &c
+}
+
+// With returns a copy of u with the given options applied on top of its configuration, leaving u
+// unchanged. This makes it easy to derive per-request variants from a shared Unmarshaler.
+func (u *Unmarshaler) With(opts ...Option) *Unmarshaler {
+	c := u.Clone()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithAllowUnknownFields sets AllowUnknownFields.
+func WithAllowUnknownFields() Option {
+	return func(u *Unmarshaler) { u.AllowUnknownFields = true }
+}
+
+// WithUnknownFieldSink sets UnknownFieldSink.
+func WithUnknownFieldSink(fn func(path string, key string, raw json.RawMessage)) Option {
+	return func(u *Unmarshaler) { u.UnknownFieldSink = fn }
+}
+
+// WithFieldFilter sets FieldFilter.
+func WithFieldFilter(filter func(path string) bool) Option {
+	return func(u *Unmarshaler) { u.FieldFilter = filter }
+}
+
+// WithAllowedFields sets FieldFilter to allow only the fields in mask, see AllowFields.
+func WithAllowedFields(mask *fieldmaskpb.FieldMask) Option {
+	return func(u *Unmarshaler) { u.FieldFilter = AllowFields(mask) }
+}
+
+// WithUnknownFieldPolicy sets UnknownFieldPolicy.
+func WithUnknownFieldPolicy(policy func(path string, key string) UnknownFieldAction) Option {
+	return func(u *Unmarshaler) { u.UnknownFieldPolicy = policy }
+}
+
+// WithOnWarning sets OnWarning.
+func WithOnWarning(fn func(Warning)) Option {
+	return func(u *Unmarshaler) { u.OnWarning = fn }
+}
+
+// WithNullHandling sets NullHandling.
+func WithNullHandling(h NullHandling) Option {
+	return func(u *Unmarshaler) { u.NullHandling = h }
+}
+
+// WithMergeInto sets MergeInto, and AppendRepeated if appendRepeated is true.
+func WithMergeInto(appendRepeated bool) Option {
+	return func(u *Unmarshaler) {
+		u.MergeInto = true
+		u.AppendRepeated = appendRepeated
+	}
+}
+
+// WithIgnoreUnknownEnumValues sets IgnoreUnknownEnumValues.
+func WithIgnoreUnknownEnumValues() Option {
+	return func(u *Unmarshaler) { u.IgnoreUnknownEnumValues = true }
+}
+
+// WithLenientEnumNames sets LenientEnumNames.
+func WithLenientEnumNames() Option {
+	return func(u *Unmarshaler) { u.LenientEnumNames = true }
+}
+
+// WithCoerceFloatsToInts sets CoerceFloatsToInts.
+func WithCoerceFloatsToInts() Option {
+	return func(u *Unmarshaler) { u.CoerceFloatsToInts = true }
+}
+
+// WithRejectNonFiniteFloats sets RejectNonFiniteFloats.
+func WithRejectNonFiniteFloats() Option {
+	return func(u *Unmarshaler) { u.RejectNonFiniteFloats = true }
+}
+
+// WithAcceptStringNumbers sets AcceptStringNumbers.
+func WithAcceptStringNumbers() Option {
+	return func(u *Unmarshaler) { u.AcceptStringNumbers = true }
+}
+
+// WithExactNumbers sets ExactNumbers.
+func WithExactNumbers() Option {
+	return func(u *Unmarshaler) { u.ExactNumbers = true }
+}
+
+// WithBytesEncoding sets BytesEncoding.
+func WithBytesEncoding(e BytesEncoding) Option {
+	return func(u *Unmarshaler) { u.BytesEncoding = e }
+}
+
+// WithCoerceStringBools sets CoerceStringBools.
+func WithCoerceStringBools() Option {
+	return func(u *Unmarshaler) { u.CoerceStringBools = true }
+}
+
+// WithEmptyStringAsZero sets EmptyStringAsZero.
+func WithEmptyStringAsZero() Option {
+	return func(u *Unmarshaler) { u.EmptyStringAsZero = true }
+}
+
+// WithWrapSingleValues sets WrapSingleValues.
+func WithWrapSingleValues() Option {
+	return func(u *Unmarshaler) { u.WrapSingleValues = true }
+}
+
+// WithRejectInvalidUTF8 sets RejectInvalidUTF8.
+func WithRejectInvalidUTF8() Option {
+	return func(u *Unmarshaler) { u.RejectInvalidUTF8 = true }
+}
+
+// WithRepairInvalidUTF8 sets RepairInvalidUTF8.
+func WithRepairInvalidUTF8() Option {
+	return func(u *Unmarshaler) { u.RepairInvalidUTF8 = true }
+}
+
+// WithRejectControlChars sets RejectControlChars.
+func WithRejectControlChars() Option {
+	return func(u *Unmarshaler) { u.RejectControlChars = true }
+}
+
+// WithSkipNullElements sets SkipNullElements.
+func WithSkipNullElements() Option {
+	return func(u *Unmarshaler) { u.SkipNullElements = true }
+}
+
+// WithAllowOneofConflicts sets AllowOneofConflicts.
+func WithAllowOneofConflicts() Option {
+	return func(u *Unmarshaler) { u.AllowOneofConflicts = true }
+}
+
+// WithCheckRequiredFields sets CheckRequiredFields.
+func WithCheckRequiredFields() Option {
+	return func(u *Unmarshaler) { u.CheckRequiredFields = true }
+}
+
+// WithApplyDefaults sets ApplyDefaults.
+func WithApplyDefaults() Option {
+	return func(u *Unmarshaler) { u.ApplyDefaults = true }
+}
+
+// WithEnforceRequiredBehavior sets EnforceRequiredBehavior.
+func WithEnforceRequiredBehavior() Option {
+	return func(u *Unmarshaler) { u.EnforceRequiredBehavior = true }
+}
+
+// WithOutputOnly sets OutputOnly.
+func WithOutputOnly(h OutputOnlyHandling) Option {
+	return func(u *Unmarshaler) { u.OutputOnly = h }
+}
+
+// WithRejectImmutableUpdates sets RejectImmutableUpdates.
+func WithRejectImmutableUpdates() Option {
+	return func(u *Unmarshaler) { u.RejectImmutableUpdates = true }
+}
+
+// WithMaxDepth sets MaxDepth.
+func WithMaxDepth(n int) Option {
+	return func(u *Unmarshaler) { u.MaxDepth = n }
+}
+
+// WithMaxNodes sets MaxNodes.
+func WithMaxNodes(n int) Option {
+	return func(u *Unmarshaler) { u.MaxNodes = n }
+}
+
+// WithMaxInputBytes sets MaxInputBytes.
+func WithMaxInputBytes(n int64) Option {
+	return func(u *Unmarshaler) { u.MaxInputBytes = n }
+}
+
+// WithAllErrors sets AllErrors.
+func WithAllErrors() Option {
+	return func(u *Unmarshaler) { u.AllErrors = true }
+}
+
+// WithMaxErrors sets MaxErrors.
+func WithMaxErrors(n int) Option {
+	return func(u *Unmarshaler) { u.MaxErrors = n }
+}
+
+// WithDisallowTrailingData sets DisallowTrailingData.
+func WithDisallowTrailingData() Option {
+	return func(u *Unmarshaler) { u.DisallowTrailingData = true }
+}
+
+// WithAllowComments sets AllowComments.
+func WithAllowComments() Option {
+	return func(u *Unmarshaler) { u.AllowComments = true }
+}
+
+// WithAllowTrailingCommas sets AllowTrailingCommas.
+func WithAllowTrailingCommas() Option {
+	return func(u *Unmarshaler) { u.AllowTrailingCommas = true }
+}
+
+// WithInputEncoding sets InputEncoding.
+func WithInputEncoding(e InputEncoding) Option {
+	return func(u *Unmarshaler) { u.InputEncoding = e }
+}
+
+// WithErrorFormatter sets ErrorFormatter.
+func WithErrorFormatter(f ErrorFormatter) Option {
+	return func(u *Unmarshaler) { u.ErrorFormatter = f }
+}
+
+// WithRedactValues sets RedactValues.
+func WithRedactValues() Option {
+	return func(u *Unmarshaler) { u.RedactValues = true }
+}
+
+// WithSensitiveFields sets SensitiveFields.
+func WithSensitiveFields(fn func(fd protoreflect.FieldDescriptor) bool) Option {
+	return func(u *Unmarshaler) { u.SensitiveFields = fn }
+}
+
+// WithCoercer sets Coercer.
+func WithCoercer(c Coercer) Option {
+	return func(u *Unmarshaler) { u.Coercer = c }
+}
+
+// WithTypeResolver sets TypeResolver.
+func WithTypeResolver(r TypeResolver) Option {
+	return func(u *Unmarshaler) { u.TypeResolver = r }
+}
+
+// WithCheckStructAnys sets CheckStructAnys.
+func WithCheckStructAnys() Option {
+	return func(u *Unmarshaler) { u.CheckStructAnys = true }
+}
+
+// WithMoneyStrings sets MoneyStrings.
+func WithMoneyStrings() Option {
+	return func(u *Unmarshaler) { u.MoneyStrings = true }
+}
+
+// WithTimestampOffsets sets TimestampOffsets.
+func WithTimestampOffsets(o TimestampOffsets) Option {
+	return func(u *Unmarshaler) { u.TimestampOffsets = o }
+}
+
+// WithEpochTimestamps sets EpochTimestamps.
+func WithEpochTimestamps(e EpochTimestamps) Option {
+	return func(u *Unmarshaler) { u.EpochTimestamps = e }
+}
+
+// WithDurationNumbers sets DurationNumbers.
+func WithDurationNumbers() Option {
+	return func(u *Unmarshaler) { u.DurationNumbers = true }
+}
+
+// WithExtendedDurations sets ExtendedDurations.
+func WithExtendedDurations() Option {
+	return func(u *Unmarshaler) { u.ExtendedDurations = true }
+}
+
+// WithProtoJSON sets ProtoJSON.
+func WithProtoJSON() Option {
+	return func(u *Unmarshaler) { u.ProtoJSON = true }
+}
+
+// WithProfile sets Profile.
+func WithProfile(p Profile) Option {
+	return func(u *Unmarshaler) { u.Profile = p }
+}
+
+// WithObserver sets Observer.
+func WithObserver(o Observer) Option {
+	return func(u *Unmarshaler) { u.Observer = o }
+}
+
+// WithBackend sets Backend.
+func WithBackend(b Backend) Option {
+	return func(u *Unmarshaler) { u.Backend = b }
+}
+
+// WithTypeHandler registers fn as with RegisterTypeHandler.
+func WithTypeHandler(name string, fn TypeHandler) Option {
+	return func(u *Unmarshaler) { u.RegisterTypeHandler(name, fn) }
+}
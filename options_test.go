@@ -0,0 +1,225 @@
+package nicejsonpb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_AppliesOptions(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields(), nicejsonpb.WithMaxDepth(3), nicejsonpb.WithMergeInto(true))
+	require.True(t, u.AllowUnknownFields)
+	require.Equal(t, 3, u.MaxDepth)
+	require.True(t, u.MergeInto)
+	require.True(t, u.AppendRepeated)
+	require.False(t, u.AllErrors)
+}
+
+func TestWith_LeavesOriginalUnchanged(t *testing.T) {
+	base := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	strict := base.With(nicejsonpb.WithAllErrors(), nicejsonpb.WithTypeHandler("validatortest.Message3.Embedded", func(raw json.RawMessage, target proto.Message) error {
+		target.(*validatortest.Message3_Embedded).Identifier = "handled"
+		return nil
+	}))
+	require.True(t, strict.AllowUnknownFields)
+	require.True(t, strict.AllErrors)
+	require.False(t, base.AllErrors)
+
+	input := `{"someEmbedded": {"identifier": "x"}}`
+	m := &validatortest.Message3{}
+	require.NoError(t, strict.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, "handled", m.SomeEmbedded.Identifier)
+	require.NoError(t, base.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+}
+
+func TestClone_CopiesTypeHandlers(t *testing.T) {
+	base := &nicejsonpb.Unmarshaler{}
+	base.RegisterTypeHandler("validatortest.Message3.Embedded", func(raw json.RawMessage, target proto.Message) error {
+		target.(*validatortest.Message3_Embedded).Identifier = "base"
+		return nil
+	})
+	clone := base.Clone()
+	clone.RegisterTypeHandler("validatortest.Message3.Embedded", func(raw json.RawMessage, target proto.Message) error {
+		target.(*validatortest.Message3_Embedded).Identifier = "clone"
+		return nil
+	})
+	m := &validatortest.Message3{}
+	require.NoError(t, base.Unmarshal(strings.NewReader(`{"someEmbedded": {}}`), m))
+	require.Equal(t, "base", m.SomeEmbedded.Identifier)
+	require.NoError(t, clone.Unmarshal(strings.NewReader(`{"someEmbedded": {}}`), m))
+	require.Equal(t, "clone", m.SomeEmbedded.Identifier)
+}
+
+func TestUnmarshal_MaxDepth(t *testing.T) {
+	input := `{"someEmbedded": {"children": [{"children": [{"identifier": "deep"}]}]}}`
+	u := nicejsonpb.New(nicejsonpb.WithMaxDepth(4))
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), &validatortest.Message3{}))
+
+	u = nicejsonpb.New(nicejsonpb.WithMaxDepth(3))
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Children.[0].Children.[0]: messages nested more than 3 deep")
+
+	_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Children.[0].Children.[0]: messages nested more than 3 deep")
+}
+
+const allErrorsInput = `{
+	"someInt32": "x",
+	"someString": "fine",
+	"someStringRep": ["a", null, 3],
+	"someEmbeddedRep": [{"someValue": true}, {"identifier": 1}],
+	"someInt32ToString": {"b": "x", "1": 2}
+}`
+
+func TestUnmarshal_AllErrors(t *testing.T) {
+	m := &validatortest.Message3{}
+	err := nicejsonpb.New(nicejsonpb.WithAllErrors()).Unmarshal(strings.NewReader(allErrorsInput), m)
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 7)
+	require.Contains(t, multi[0].Error(), "unparsable field SomeStringRep.[1]: null elements are not allowed")
+	require.Contains(t, multi[1].Error(), "unparsable field SomeStringRep.[2]:")
+	require.Contains(t, multi[2].Error(), "unparsable field SomeInt32:")
+	require.Contains(t, multi[3].Error(), "unparsable field SomeEmbeddedRep.[0].SomeValue:")
+	require.Contains(t, multi[4].Error(), "unparsable field SomeEmbeddedRep.[1].Identifier:")
+	require.Contains(t, multi[5].Error(), "unparsable field SomeInt32ToString.['1']value:")
+	require.Contains(t, multi[6].Error(), "bad map key 'b'")
+	require.Equal(t, "fine", m.SomeString)
+
+	err = nicejsonpb.UnmarshalString(allErrorsInput, &validatortest.Message3{})
+	require.EqualError(t, err, multi[0].Error())
+}
+
+func TestUnmarshalDynamic_AllErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors())
+	_, err := u.UnmarshalDynamic(strings.NewReader(allErrorsInput), message3Descriptor(t))
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 7)
+}
+
+func TestUnmarshal_MaxErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors(), nicejsonpb.WithMaxErrors(3))
+	for _, decode := range []func() error{
+		func() error { return u.Unmarshal(strings.NewReader(allErrorsInput), &validatortest.Message3{}) },
+		func() error {
+			_, err := u.UnmarshalDynamic(strings.NewReader(allErrorsInput), message3Descriptor(t))
+			return err
+		},
+	} {
+		var multi nicejsonpb.MultiError
+		require.ErrorAs(t, decode(), &multi)
+		require.Len(t, multi, 4)
+		require.Contains(t, multi[2].Error(), "unparsable field SomeInt32:")
+		require.EqualError(t, multi[3], "and 4 more errors")
+		var more *nicejsonpb.MoreErrors
+		require.ErrorAs(t, multi[3], &more)
+		require.Equal(t, 4, more.Count)
+	}
+}
+
+func TestUnmarshaler_ConcurrentUse(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors(), nicejsonpb.WithProfile(nicejsonpb.ProfileLenient))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				m := &validatortest.Message3{}
+				input := fmt.Sprintf(`{"someInt32": "%d", "someEmbedded": {"children": [{"someValue": %d}]}}`, g*100+i, i)
+				if err := u.Unmarshal(strings.NewReader(input), m); err != nil {
+					t.Error(err)
+					return
+				}
+				if m.SomeInt32 != int32(g*100+i) || m.SomeEmbedded.Children[0].SomeValue != int64(i) {
+					t.Errorf("decoded %v from %s", m, input)
+				}
+				err := u.Unmarshal(strings.NewReader(`{"someInt32": "x", "someBool": 1}`), m)
+				if multi, ok := err.(nicejsonpb.MultiError); !ok || len(multi) != 2 {
+					t.Errorf("got %v, want 2 errors", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestUnmarshal_MaxNodes(t *testing.T) {
+	input := `{"someEmbeddedRep": [{"children": [{}, {}]}, {}], "someStringRep": ["a", "b"], "someStringToInt64": {"a": 1}}`
+	// 1 top-level message, 2 elements holding 2 messages with 2 children, 2 strings and 1 entry.
+	u := nicejsonpb.New(nicejsonpb.WithMaxNodes(12))
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), &validatortest.Message3{}))
+	_, err := u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+
+	u = nicejsonpb.New(nicejsonpb.WithMaxNodes(11), nicejsonpb.WithAllErrors())
+	err = u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeStringToInt64: more than 11 messages, list elements and map entries")
+	_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.EqualError(t, err, "unparsable field SomeStringToInt64: more than 11 messages, list elements and map entries")
+}
+
+func TestUnmarshal_WrapSingleValues(t *testing.T) {
+	input := `{"someStringRep": "red", "someEmbeddedRep": {"identifier": "x"}, "someIntRep": [1, 2]}`
+	u := nicejsonpb.New(nicejsonpb.WithWrapSingleValues())
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, []string{"red"}, m.SomeStringRep)
+	require.Len(t, m.SomeEmbeddedRep, 1)
+	require.Equal(t, "x", m.SomeEmbeddedRep[0].Identifier)
+	require.Equal(t, []uint32{1, 2}, m.SomeIntRep)
+
+	dm, err := u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+	require.NoError(t, err)
+	require.Equal(t, 1, dm.Get(dm.Descriptor().Fields().ByName("some_string_rep")).List().Len())
+
+	err = u.Unmarshal(strings.NewReader(`{"someStringRep": 3}`), &validatortest.Message3{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unparsable field SomeStringRep.[0]:")
+	require.Error(t, nicejsonpb.UnmarshalString(`{"someStringRep": "red"}`, &validatortest.Message3{}))
+}
+
+func TestUnmarshal_MaxInputBytes(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMaxInputBytes(24))
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someString": "fits"}`), &validatortest.Message3{}))
+	for _, err := range []error{
+		u.Unmarshal(strings.NewReader(`{"someString": "too long"}`), &validatortest.Message3{}),
+		u.UnmarshalBytes([]byte(`{"someString": "too long"}`), &validatortest.Message3{}),
+	} {
+		require.ErrorIs(t, err, nicejsonpb.ErrInputTooLarge)
+		require.EqualError(t, err, "input too large: more than 24 bytes")
+		require.Equal(t, nicejsonpb.CodeLimitExceeded, nicejsonpb.ErrorCode(err))
+	}
+}
+
+func TestUnmarshal_MaxInputBytesTranscoded(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithMaxInputBytes(1 << 10))
+	// The whitespace of a UTF-16 document is read through the limit, not buffered whole first.
+	body := &countingReader{r: io.MultiReader(bytes.NewReader([]byte{0xFF, 0xFE, '{', 0}), strings.NewReader(strings.Repeat(" \x00", 50<<20)))}
+	err := u.Unmarshal(body, &validatortest.Message3{})
+	require.ErrorIs(t, err, nicejsonpb.ErrInputTooLarge)
+	require.EqualError(t, err, "input too large: more than 1024 bytes")
+	require.Less(t, body.n, int64(1<<20))
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
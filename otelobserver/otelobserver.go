@@ -0,0 +1,118 @@
+// Package otelobserver provides a nicejsonpb.Observer that traces and measures unmarshals with
+// OpenTelemetry:
+//
+//	u := nicejsonpb.New(nicejsonpb.WithObserver(otelobserver.New()))
+//
+// Every document gets a span, and its decode duration, size and unknown fields are recorded as
+// metrics, all attributed by message type and, for failures, by nicejsonpb.ErrorCode.
+package otelobserver
+
+import (
+	"context"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const instrumentationName = "github.com/mwitkow/go-nicejsonpb/otelobserver"
+
+// Attribute keys set on spans and metrics.
+const (
+	MessageKey = attribute.Key("nicejsonpb.message")
+	ErrorKey   = attribute.Key("nicejsonpb.error")
+)
+
+// Option configures an Observer.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the TracerProvider spans come from, the global one by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the MeterProvider metrics are recorded with, the global one by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// Observer is a nicejsonpb.Observer recording spans and metrics.
+type Observer struct {
+	tracer        trace.Tracer
+	duration      metric.Float64Histogram
+	size          metric.Int64Histogram
+	unknownFields metric.Int64Counter
+}
+
+var _ nicejsonpb.Observer = (*Observer)(nil)
+
+// New returns an Observer using the given options.
+func New(opts ...Option) *Observer {
+	c := config{tracerProvider: otel.GetTracerProvider(), meterProvider: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	meter := c.meterProvider.Meter(instrumentationName)
+	o := &Observer{tracer: c.tracerProvider.Tracer(instrumentationName)}
+	// Instruments that fail to be created are replaced by no-ops, so that metrics never get in the
+	// way of decoding.
+	var err error
+	if o.duration, err = meter.Float64Histogram("nicejsonpb.unmarshal.duration",
+		metric.WithDescription("Time taken to decode a JSON document."), metric.WithUnit("s")); err != nil {
+		o.duration = noop.Float64Histogram{}
+	}
+	if o.size, err = meter.Int64Histogram("nicejsonpb.unmarshal.size",
+		metric.WithDescription("Size of the decoded JSON documents."), metric.WithUnit("By")); err != nil {
+		o.size = noop.Int64Histogram{}
+	}
+	if o.unknownFields, err = meter.Int64Counter("nicejsonpb.unmarshal.unknown_fields",
+		metric.WithDescription("Object keys that matched no field."), metric.WithUnit("{field}")); err != nil {
+		o.unknownFields = noop.Int64Counter{}
+	}
+	return o
+}
+
+func (o *Observer) OnStart(ctx context.Context, message protoreflect.FullName) context.Context {
+	ctx, _ = o.tracer.Start(ctx, "nicejsonpb.Unmarshal",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(MessageKey.String(string(message))))
+	return ctx
+}
+
+func (o *Observer) OnUnknownField(ctx context.Context, path string, key string) {
+	trace.SpanFromContext(ctx).AddEvent("unknown field", trace.WithAttributes(
+		attribute.String("path", path), attribute.String("key", key)))
+}
+
+func (o *Observer) OnFinish(ctx context.Context, message protoreflect.FullName, err error, stats nicejsonpb.Stats) {
+	attrs := []attribute.KeyValue{MessageKey.String(string(message))}
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		attrs = append(attrs, ErrorKey.String(nicejsonpb.ErrorCode(err).String()))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, nicejsonpb.ErrorCode(err).String())
+	}
+	span.SetAttributes(
+		attribute.Int("nicejsonpb.bytes", stats.Bytes),
+		attribute.Int("nicejsonpb.fields", stats.Fields),
+		attribute.Int("nicejsonpb.unknown_fields", stats.UnknownFields),
+	)
+	span.End()
+
+	set := metric.WithAttributes(attrs...)
+	o.duration.Record(ctx, stats.Duration.Seconds(), set)
+	o.size.Record(ctx, int64(stats.Bytes), set)
+	if stats.UnknownFields > 0 {
+		o.unknownFields.Add(ctx, int64(stats.UnknownFields), metric.WithAttributes(MessageKey.String(string(message))))
+	}
+}
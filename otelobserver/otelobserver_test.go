@@ -0,0 +1,52 @@
+package otelobserver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/otelobserver"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestObserver(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	o := otelobserver.New(
+		otelobserver.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelobserver.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	u := nicejsonpb.New(nicejsonpb.WithObserver(o), nicejsonpb.WithAllowUnknownFields())
+
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someString": "a", "extra": 1}`), &validatortest.Message3{}))
+	require.Error(t, u.Unmarshal(strings.NewReader(`{"someInt32": "x"}`), &validatortest.Message3{}))
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	require.Equal(t, "nicejsonpb.Unmarshal", ended[0].Name())
+	require.Len(t, ended[0].Events(), 1)
+	require.Equal(t, "unknown field", ended[0].Events()[0].Name)
+	require.Equal(t, codes.Unset, ended[0].Status().Code)
+	require.Equal(t, codes.Error, ended[1].Status().Code)
+	require.Equal(t, "TypeMismatch", ended[1].Status().Description)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	byName := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		byName[m.Name] = m
+	}
+	duration := byName["nicejsonpb.unmarshal.duration"].Data.(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 2)
+	unknown := byName["nicejsonpb.unmarshal.unknown_fields"].Data.(metricdata.Sum[int64])
+	require.Len(t, unknown.DataPoints, 1)
+	require.Equal(t, int64(1), unknown.DataPoints[0].Value)
+}
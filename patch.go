@@ -0,0 +1,341 @@
+package nicejsonpb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// PatchError is returned by ApplyJSONPatch for the operation that couldn't be applied.
+type PatchError struct {
+	// Index is the position of the operation in the patch document.
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %s): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// jsonPatchOp is one operation of an RFC 6902 patch document.
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to a protocol buffer. Paths are JSON
+// Pointers made of field names, either the original proto names or their lowerCamelCase JSON names,
+// list indices (or "-" for the end of a list) and map keys. The add, remove, replace and test
+// operations are supported. Values are decoded with the same rules as Unmarshal. The patch is applied
+// atomically: if any operation fails, pb is left unchanged and a *PatchError is returned.
+func (u *Unmarshaler) ApplyJSONPatch(ops []byte, pb proto.Message) error {
+	var patch []jsonPatchOp
+	if err := json.Unmarshal(ops, &patch); err != nil {
+		return fmt.Errorf("bad JSON Patch document: %v", err)
+	}
+	target := proto.MessageReflect(pb)
+	work := protov2.Clone(target.Interface()).ProtoReflect()
+	// An operation fails as a whole, so its errors aren't collected even with AllErrors.
+	pu := u.options()
+	pu.AllErrors = false
+	d := pu.newDecodeState()
+	defer d.release()
+	for i, op := range patch {
+		if op.Path == nil {
+			return &PatchError{Index: i, Op: op.Op, Err: errors.New(`missing "path"`)}
+		}
+		if err := d.applyPatchOp(work, op); err != nil {
+			return &PatchError{Index: i, Op: op.Op, Path: *op.Path, Err: err}
+		}
+	}
+	protov2.Reset(target.Interface())
+	protov2.Merge(target.Interface(), work.Interface())
+	return nil
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to a protocol buffer.
+func ApplyJSONPatch(ops []byte, pb proto.Message) error {
+	return new(Unmarshaler).ApplyJSONPatch(ops, pb)
+}
+
+// patchLocation is where a JSON Pointer leads: either the field fd of m, or when elem is set one of
+// its list elements or map entries.
+type patchLocation struct {
+	m     protoreflect.Message
+	fd    protoreflect.FieldDescriptor
+	elem  bool
+	index int // of the list element, -1 for the end of the list
+	key   protoreflect.MapKey
+}
+
+func (d *decodeState) applyPatchOp(root protoreflect.Message, op jsonPatchOp) error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.New(`missing "value"`)
+		}
+	case "remove":
+	case "move", "copy":
+		return fmt.Errorf("operation %q is not supported", op.Op)
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+	tokens, err := parseJSONPointer(*op.Path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("the whole message can't be patched, only its fields")
+	}
+	loc, err := d.resolvePatchLocation(root, tokens)
+	if err != nil {
+		return err
+	}
+	if loc.elem && loc.fd.IsList() {
+		return d.applyPatchToListElement(loc, op)
+	}
+	if loc.elem {
+		return d.applyPatchToMapEntry(loc, op)
+	}
+	return d.applyPatchToField(loc, op)
+}
+
+func (d *decodeState) applyPatchToField(loc patchLocation, op jsonPatchOp) error {
+	m, fd := loc.m, loc.fd
+	name := goCamelCase(string(fd.Name()))
+	switch op.Op {
+	case "remove":
+		m.Clear(fd)
+		return nil
+	case "test":
+		// Decode the expected value into an empty message of the same type, so that it can be
+		// compared using proto equality.
+		want, got := m.New(), m.New()
+		if err := d.setPatchField(want, fd, name, op.Value); err != nil {
+			return err
+		}
+		if m.Has(fd) {
+			got.Set(fd, m.Get(fd))
+		}
+		if !protov2.Equal(want.Interface(), got.Interface()) {
+			return errors.New("test failed, the field has a different value")
+		}
+		return nil
+	}
+	m.Clear(fd)
+	return d.setPatchField(m, fd, name, op.Value)
+}
+
+// setPatchField decodes raw as the value of the field fd of m.
+func (d *decodeState) setPatchField(m protoreflect.Message, fd protoreflect.FieldDescriptor, name string, raw json.RawMessage) error {
+	if isNull(raw) && !dynamicAcceptsNull(fd) {
+		if isProto3Optional(fd) {
+			return nil
+		}
+		return d.unmarshalDynamicNullField(name, dynamicJSONName(fd), m, fd)
+	}
+	return d.within(name, dynamicJSONName(fd), func() error {
+		return d.unmarshalDynamicField(m, fd, raw)
+	})
+}
+
+func (d *decodeState) applyPatchToListElement(loc patchLocation, op jsonPatchOp) error {
+	list := loc.m.Get(loc.fd).List()
+	index := loc.index
+	if index < 0 {
+		if op.Op != "add" {
+			return errors.New(`"-" can only be used to add to the end of a list`)
+		}
+		index = list.Len()
+	}
+	if index > list.Len() || (index == list.Len() && op.Op != "add") {
+		return fmt.Errorf("index %d is out of range, the list has %d elements", index, list.Len())
+	}
+	var value protoreflect.Value
+	if op.Op != "remove" {
+		var err error
+		value, err = d.patchValue(loc.fd, loc.fd, list.NewElement(), fmt.Sprintf("[%d]", index), op.Value)
+		if err != nil {
+			return err
+		}
+	}
+	if op.Op == "test" {
+		if !patchValuesEqual(loc.fd, value, list.Get(index)) {
+			return errors.New("test failed, the element has a different value")
+		}
+		return nil
+	}
+	list = loc.m.Mutable(loc.fd).List()
+	switch op.Op {
+	case "add":
+		list.Append(value)
+		for i := list.Len() - 1; i > index; i-- {
+			list.Set(i, list.Get(i-1))
+		}
+		list.Set(index, value)
+	case "replace":
+		list.Set(index, value)
+	case "remove":
+		for i := index; i < list.Len()-1; i++ {
+			list.Set(i, list.Get(i+1))
+		}
+		list.Truncate(list.Len() - 1)
+	}
+	return nil
+}
+
+func (d *decodeState) applyPatchToMapEntry(loc patchLocation, op jsonPatchOp) error {
+	mapValue := loc.m.Get(loc.fd).Map()
+	ks := loc.key.String()
+	if op.Op != "add" && !mapValue.Has(loc.key) {
+		return fmt.Errorf("map has no entry with key %q", ks)
+	}
+	var value protoreflect.Value
+	if op.Op != "remove" {
+		var err error
+		value, err = d.patchValue(loc.fd, loc.fd.MapValue(), mapValue.NewValue(), fmt.Sprintf("['%s']value", ks), op.Value)
+		if err != nil {
+			return err
+		}
+	}
+	switch op.Op {
+	case "test":
+		if !patchValuesEqual(loc.fd.MapValue(), value, mapValue.Get(loc.key)) {
+			return errors.New("test failed, the entry has a different value")
+		}
+	case "remove":
+		loc.m.Mutable(loc.fd).Map().Clear(loc.key)
+	default:
+		loc.m.Mutable(loc.fd).Map().Set(loc.key, value)
+	}
+	return nil
+}
+
+// patchValue decodes raw as a list element or map value of the field, described by fd.
+func (d *decodeState) patchValue(field, fd protoreflect.FieldDescriptor, empty protoreflect.Value, name string, raw json.RawMessage) (protoreflect.Value, error) {
+	var v protoreflect.Value
+	err := d.within(goCamelCase(string(field.Name())), dynamicJSONName(field), func() error {
+		return d.within(name, pointerKey(name), func() (err error) {
+			v, err = d.dynamicValue(fd, empty, raw)
+			return err
+		})
+	})
+	return v, err
+}
+
+func patchValuesEqual(fd protoreflect.FieldDescriptor, a, b protoreflect.Value) bool {
+	switch {
+	case fd.Message() != nil:
+		return protov2.Equal(a.Message().Interface(), b.Message().Interface())
+	case fd.Kind() == protoreflect.BytesKind:
+		return bytes.Equal(a.Bytes(), b.Bytes())
+	}
+	return a.Interface() == b.Interface()
+}
+
+// resolvePatchLocation walks the tokens of a JSON Pointer through root. All but the last token must
+// lead to messages that are already present.
+func (d *decodeState) resolvePatchLocation(root protoreflect.Message, tokens []string) (patchLocation, error) {
+	m := root
+	for i := 0; i < len(tokens); i++ {
+		fd := findPatchField(m.Descriptor(), tokens[i])
+		if fd == nil {
+			return patchLocation{}, fmt.Errorf("field %q does not exist in message %v", tokens[i], m.Descriptor().FullName())
+		}
+		if i == len(tokens)-1 {
+			return patchLocation{m: m, fd: fd}, nil
+		}
+		if !fd.IsList() && !fd.IsMap() {
+			if fd.Message() == nil {
+				return patchLocation{}, fmt.Errorf("field %q is not a message", tokens[i])
+			}
+			if !m.Has(fd) {
+				return patchLocation{}, fmt.Errorf("field %q is not set", tokens[i])
+			}
+			m = m.Mutable(fd).Message()
+			continue
+		}
+		i++
+		loc := patchLocation{m: m, fd: fd, elem: true}
+		var value protoreflect.Value
+		if fd.IsList() {
+			if tokens[i] == "-" {
+				loc.index = -1
+			} else {
+				index, err := strconv.Atoi(tokens[i])
+				if err != nil || index < 0 || (index > 0 && tokens[i][0] == '0') {
+					return patchLocation{}, fmt.Errorf("%q is not a list index", tokens[i])
+				}
+				loc.index = index
+			}
+			if i == len(tokens)-1 {
+				return loc, nil
+			}
+			list := m.Get(fd).List()
+			if loc.index < 0 || loc.index >= list.Len() {
+				return patchLocation{}, fmt.Errorf("index %s is out of range, the list has %d elements", tokens[i], list.Len())
+			}
+			value = m.Mutable(fd).List().Get(loc.index)
+		} else {
+			key, err := d.dynamicMapKey(fd, tokens[i])
+			if err != nil {
+				return patchLocation{}, err
+			}
+			loc.key = key
+			if i == len(tokens)-1 {
+				return loc, nil
+			}
+			if !m.Get(fd).Map().Has(key) {
+				return patchLocation{}, fmt.Errorf("map has no entry with key %q", tokens[i])
+			}
+			fd = fd.MapValue()
+			value = m.Mutable(loc.fd).Map().Mutable(key)
+		}
+		if fd.Message() == nil {
+			return patchLocation{}, fmt.Errorf("path continues past the scalar at %q", tokens[i])
+		}
+		m = value.Message()
+	}
+	panic("unreachable")
+}
+
+// findPatchField looks up a field by its proto name or its JSON name, which for groups is the name
+// of the group.
+func findPatchField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	if fd := md.Fields().ByJSONName(name); fd != nil {
+		return fd
+	}
+	return md.Fields().ByTextName(name)
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("JSON Pointer %q must start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
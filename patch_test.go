@@ -0,0 +1,78 @@
+package nicejsonpb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONPatch_Operations(t *testing.T) {
+	stuff := existingMessage3()
+	stuff.SomeInt32ToString = map[int32]string{7: "seven"}
+	patch := `[
+		{"op": "test", "path": "/some_string", "value": "existing"},
+		{"op": "replace", "path": "/someString", "value": "patched"},
+		{"op": "add", "path": "/someIntRep/0", "value": 0},
+		{"op": "add", "path": "/someIntRep/-", "value": 2},
+		{"op": "replace", "path": "/some_embedded/some_value", "value": "5"},
+		{"op": "add", "path": "/someStringToInt64/a~1b", "value": 3},
+		{"op": "remove", "path": "/someStringToInt64/a"},
+		{"op": "test", "path": "/someInt32ToString/7", "value": "seven"},
+		{"op": "add", "path": "/someEmbeddedRep", "value": [{"identifier": "first"}]},
+		{"op": "replace", "path": "/someEmbeddedRep/0/identifier", "value": "patched"}
+	]`
+	require.NoError(t, nicejsonpb.ApplyJSONPatch([]byte(patch), stuff))
+	require.Equal(t, "patched", stuff.SomeString)
+	require.Equal(t, []uint32{0, 1, 2}, stuff.SomeIntRep)
+	require.Equal(t, int64(5), stuff.SomeEmbedded.SomeValue)
+	require.Equal(t, map[string]int64{"a/b": 3}, stuff.SomeStringToInt64)
+	require.Equal(t, "patched", stuff.SomeEmbeddedRep[0].Identifier)
+}
+
+func TestApplyJSONPatch_FailureLeavesMessageUnchanged(t *testing.T) {
+	stuff := existingMessage3()
+	patch := `[
+		{"op": "replace", "path": "/someString", "value": "patched"},
+		{"op": "remove", "path": "/someIntRep/3"}
+	]`
+	err := nicejsonpb.ApplyJSONPatch([]byte(patch), stuff)
+	require.EqualError(t, err, "patch operation 1 (remove /someIntRep/3): index 3 is out of range, the list has 1 elements")
+	var patchErr *nicejsonpb.PatchError
+	require.True(t, errors.As(err, &patchErr))
+	require.Equal(t, 1, patchErr.Index)
+	require.True(t, proto.Equal(existingMessage3(), stuff))
+}
+
+func TestApplyJSONPatch_ReportsFieldErrors(t *testing.T) {
+	for _, tc := range []struct {
+		patch string
+		err   string
+	}{
+		{
+			patch: `[{"op": "test", "path": "/someString", "value": "other"}]`,
+			err:   "patch operation 0 (test /someString): test failed, the field has a different value",
+		},
+		{
+			patch: `[{"op": "add", "path": "/someEmbedded/someValue", "value": true}]`,
+			err:   "patch operation 0 (add /someEmbedded/someValue): unparsable field SomeValue: json: cannot unmarshal bool into Go value of type int64",
+		},
+		{
+			patch: `[{"op": "add", "path": "/someIntRep/0", "value": "x"}]`,
+			err:   "patch operation 0 (add /someIntRep/0): unparsable field SomeIntRep.[0]: json: cannot unmarshal string into Go value of type uint32",
+		},
+		{
+			patch: `[{"op": "add", "path": "/nothing", "value": 1}]`,
+			err:   `patch operation 0 (add /nothing): field "nothing" does not exist in message validatortest.Message3`,
+		},
+		{
+			patch: `[{"op": "move", "path": "/someString", "from": "/someString"}]`,
+			err:   `patch operation 0 (move /someString): operation "move" is not supported`,
+		},
+	} {
+		err := nicejsonpb.ApplyJSONPatch([]byte(tc.patch), existingMessage3())
+		require.EqualError(t, err, tc.err, tc.patch)
+	}
+}
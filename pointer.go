@@ -0,0 +1,59 @@
+package nicejsonpb
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pointer returns the location of the field that failed as an RFC 6901 JSON Pointer, such as
+// /someEmbedded/someIntRep/3, made of the keys used in the JSON.
+func (f *fieldError) Pointer() string {
+	var b strings.Builder
+	for _, key := range f.pointerKeys() {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(key))
+	}
+	return b.String()
+}
+
+// ErrorPointer returns the JSON Pointer to the field err, which may be wrapped, is about. It is empty
+// for errors about the whole document. For a MultiError it is that of the first error.
+func ErrorPointer(err error) string {
+	var pErr interface{ Pointer() string }
+	if errors.As(err, &pErr) {
+		return pErr.Pointer()
+	}
+	return ""
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointerKeys returns the keys of f, deriving them from fieldStack where they aren't known.
+func (f *fieldError) pointerKeys() []string {
+	if len(f.keys) == len(f.fieldStack) {
+		return f.keys
+	}
+	keys := make([]string, len(f.fieldStack))
+	for i, name := range f.fieldStack {
+		keys[i] = pointerKey(name)
+	}
+	return keys
+}
+
+// pointerKey guesses the key in the JSON of an element of a field path: the index of a list element
+// "[3]", the key of a map entry "['key']value", or the JSON name of a field from its Go name.
+// Extensions keep their bracketed name.
+func pointerKey(name string) string {
+	switch {
+	case strings.HasPrefix(name, "['") && strings.HasSuffix(name, "']value"):
+		return name[2 : len(name)-len("']value")]
+	case strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") && strings.Trim(name[1:len(name)-1], "0123456789") == "":
+		return name[1 : len(name)-1]
+	case isExtensionKey(name):
+		return name
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
@@ -0,0 +1,47 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// jsonWhitespace is the set of insignificant whitespace characters allowed around JSON values.
+const jsonWhitespace = " \t\r\n"
+
+// maxPooledRawMessage caps the size of document buffers kept around for reuse, so that a single
+// huge document doesn't pin its memory in the pool forever.
+const maxPooledRawMessage = 64 << 10
+
+var (
+	rawMessagePool = sync.Pool{
+		New: func() interface{} { return new(json.RawMessage) },
+	}
+	fieldMapPool = sync.Pool{
+		New: func() interface{} { return make(map[string]json.RawMessage) },
+	}
+)
+
+// getRawMessage returns an empty buffer for reading a whole JSON document into.
+func getRawMessage() *json.RawMessage {
+	return rawMessagePool.Get().(*json.RawMessage)
+}
+
+func putRawMessage(raw *json.RawMessage) {
+	if cap(*raw) > maxPooledRawMessage {
+		return
+	}
+	*raw = (*raw)[:0]
+	rawMessagePool.Put(raw)
+}
+
+// getFieldMap returns an empty map for splitting a JSON object into its fields.
+func getFieldMap() map[string]json.RawMessage {
+	return fieldMapPool.Get().(map[string]json.RawMessage)
+}
+
+func putFieldMap(fields map[string]json.RawMessage) {
+	for k := range fields {
+		delete(fields, k)
+	}
+	fieldMapPool.Put(fields)
+}
@@ -0,0 +1,56 @@
+package nicejsonpb
+
+// Profile is a named level of strictness, bundling the options of an Unmarshaler. A profile only
+// ever turns options on, so the options set on the Unmarshaler itself are always honoured on top of
+// it.
+type Profile int
+
+const (
+	// ProfileNone uses the options of the Unmarshaler as they are. This is the default.
+	ProfileNone Profile = iota
+	// ProfileLenient accepts what well-meaning but sloppy clients send: numbers as strings, fractional
+	// numbers for integers (truncated), enum names in any case and without their prefix, bytes in
+	// base64 or hex, and null elements in repeated fields, which are dropped.
+	ProfileLenient
+	// ProfileStandard follows google.golang.org/protobuf/encoding/protojson: numbers are accepted as
+	// strings, proto2 required fields must be set, strings must be valid UTF-8, durations must be in
+	// seconds, such as "1.5s", names may not be repeated in an object, including a field under both
+	// its proto and JSON names, and the document may not be null.
+	ProfileStandard
+	// ProfileStrictConformance only accepts the canonical proto3 JSON form: on top of
+	// ProfileStandard, integers must not be quoted, timestamps must be in UTC with 0, 3, 6 or 9
+	// fractional digits, and durations in seconds with as many.
+	ProfileStrictConformance
+)
+
+// applyProfile replaces the Unmarshaler of d by a copy with the options of its profile turned on.
+func (d *decodeState) applyProfile() {
+	u := d.options()
+	switch u.Profile {
+	case ProfileLenient:
+		u.AcceptStringNumbers = true
+		u.CoerceFloatsToInts = true
+		u.LenientEnumNames = true
+		u.SkipNullElements = true
+		if u.BytesEncoding == BytesBase64 {
+			u.BytesEncoding = BytesAuto
+		}
+	case ProfileStandard:
+		u.AcceptStringNumbers = true
+		u.CheckRequiredFields = true
+		u.RejectInvalidUTF8 = true
+		d.protoJSONDurations = !u.ExtendedDurations
+		d.rejectDuplicateKeys = true
+		d.rejectNullDocument = true
+	case ProfileStrictConformance:
+		u.CheckRequiredFields = true
+		u.RejectInvalidUTF8 = true
+		d.rejectQuotedIntegers = !u.AcceptStringNumbers
+		d.canonicalTimestamps = true
+		d.canonicalDurations = !u.ExtendedDurations
+		d.protoJSONDurations = !u.ExtendedDurations
+		d.rejectDuplicateKeys = true
+		d.rejectNullDocument = true
+	}
+	d.Unmarshaler = &u
+}
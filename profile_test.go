@@ -0,0 +1,107 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnmarshal_ProfileLenient(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileLenient}
+	m := &validatortest.Message3{}
+	input := `{"someInt32": "7", "someUint32": 2.9, "someStatus": "active", "someBytes": "cafe", "someStringRep": ["a", null]}`
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), m))
+	require.Equal(t, int32(7), m.SomeInt32)
+	require.Equal(t, uint32(2), m.SomeUint32)
+	require.Equal(t, validatortest.Status_STATUS_ACTIVE, m.SomeStatus)
+	require.Equal(t, []byte{0xca, 0xfe}, m.SomeBytes)
+	require.Equal(t, []string{"a"}, m.SomeStringRep)
+
+	require.Error(t, u.Unmarshal(strings.NewReader(`{"unknown": 1}`), m), "unknown fields are still rejected")
+}
+
+func TestUnmarshal_ProfileStandard(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileStandard}
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someInt32": "7", "someDouble": "1.5"}`), m))
+	require.Equal(t, int32(7), m.SomeInt32)
+	require.Equal(t, 1.5, m.SomeDouble)
+
+	err := u.Unmarshal(strings.NewReader(`{"someStatus": "active"}`), m)
+	require.EqualError(t, err, "unparsable field SomeStatus: unknown value '\"active\"' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]")
+
+	err = u.Unmarshal(strings.NewReader(`{"inner": {"id": 1}}`), &validatortest.Required2{})
+	require.EqualError(t, err, "unparsable field Name: required field is missing")
+}
+
+// standardCases are documents that ProfileStandard must accept or reject as protojson does.
+var standardCases = []string{
+	`{"someDuration": "1.5s"}`,
+	`{"someDuration": "-.5s"}`,
+	`{"someDuration": "1.s"}`,
+	`{"someDuration": "0s"}`,
+	`{"someDuration": "315576000000s"}`,
+	`{"someDuration": "-10000000000.000000001s"}`,
+	`{"someDuration": "1h"}`,
+	`{"someDuration": "01s"}`,
+	`{"someDuration": "1.0000000001s"}`,
+	`{"someDuration": "315576000001s"}`,
+	`{"someTimestamp": "2020-01-02T03:04:05.123456789+01:00"}`,
+	`{"someTimestamp": "2020-01-02T03:04:05.1234567891Z"}`,
+	`{"some_string": "x", "someString": "y"}`,
+	`{"someString": "x", "someString": "y"}`,
+	`{"someStringToInt64": {"a": "1", "b": 2}}`,
+	`{"someStringToInt64": {"a": "1", "a": "2"}}`,
+	`{"someStringToInt64": {"a": null}}`,
+	`{"someStringToEmbedded": {"a": null}}`,
+	`null`,
+}
+
+func TestUnmarshal_ProfileStandardMatchesProtoJSON(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileStandard}
+	for _, input := range standardCases {
+		want := &validatortest.Message3{}
+		wantErr := protojson.Unmarshal([]byte(input), want)
+		got := &validatortest.Message3{}
+		err := u.Unmarshal(strings.NewReader(input), got)
+		if wantErr != nil {
+			require.Error(t, err, input)
+			continue
+		}
+		require.NoError(t, err, input)
+		require.True(t, proto.Equal(want, got), "%s: got %v, want %v", input, got, want)
+	}
+	_, err := u.UnmarshalDynamic(strings.NewReader(`{"some_string": "x", "someString": "y"}`), (&validatortest.Message3{}).ProtoReflect().Descriptor())
+	require.EqualError(t, err, `unparsable field SomeString: duplicate field, both "some_string" and "someString" are set`)
+}
+
+func TestUnmarshal_ProfileStrictConformance(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileStrictConformance}
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someInt64": 7, "someTimestamp": "2020-01-02T03:04:05.123Z"}`), m))
+	require.Equal(t, int64(7), m.SomeInt64)
+
+	err := u.Unmarshal(strings.NewReader(`{"someInt64": "7"}`), m)
+	require.EqualError(t, err, `unparsable field SomeInt64: quoted integer "7" is not allowed, expected a JSON number`)
+
+	for _, ts := range []string{"2020-01-02T03:04:05.12Z", "2020-01-02T03:04:05+01:00"} {
+		err = u.Unmarshal(strings.NewReader(`{"someTimestamp": "`+ts+`"}`), m)
+		require.EqualError(t, err, `unparsable field SomeTimestamp: bad Timestamp: "`+ts+`" isn't in the canonical form, in UTC ("Z") with 0, 3, 6 or 9 fractional digits`)
+		_, err = u.UnmarshalDynamic(strings.NewReader(`{"someTimestamp": "`+ts+`"}`), message3Descriptor(t))
+		require.Error(t, err)
+	}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "2020-01-02T03:04:05+01:00"}`, m))
+}
+
+func TestUnmarshal_ProfileOverriddenByOptions(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileStrictConformance, AcceptStringNumbers: true, AllowUnknownFields: true}
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someInt64": "7", "unknown": true}`), m))
+	require.Equal(t, int64(7), m.SomeInt64)
+	require.Equal(t, nicejsonpb.ProfileStrictConformance, nicejsonpb.New(nicejsonpb.WithProfile(nicejsonpb.ProfileStrictConformance)).Profile)
+}
@@ -0,0 +1,35 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// unmarshalProtoJSON decodes the document with protojson, so that exactly the same documents are
+// accepted with the same results. When protojson fails, the document is decoded again the way
+// ProfileStandard does, to report the problem as a field error instead.
+func (d *decodeState) unmarshalProtoJSON(m protoreflect.Message, inputValue json.RawMessage) error {
+	opts := protojson.UnmarshalOptions{DiscardUnknown: d.AllowUnknownFields}
+	decoded := m.New()
+	err := opts.Unmarshal(inputValue, decoded.Interface())
+	if err == nil {
+		if !d.MergeInto {
+			protov2.Reset(m.Interface())
+		}
+		protov2.Merge(m.Interface(), decoded.Interface())
+		return nil
+	}
+	explain := &Unmarshaler{Profile: ProfileStandard, AllowUnknownFields: d.AllowUnknownFields, RedactValues: d.RedactValues, SensitiveFields: d.SensitiveFields}
+	if fErr := explain.unmarshalDynamicDocument(m.New(), inputValue); fErr != nil {
+		return fErr
+	}
+	if d.RedactValues {
+		// protojson errors quote the offending values.
+		return errors.New("document rejected by protojson")
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// protoJSONCases are documents that the default options and protojson treat differently.
+var protoJSONCases = []string{
+	`{"someInt32": "7", "someFloat": "1.5"}`,
+	`{"someInt32": 1e2}`,
+	`{"someDuration": "1.5s", "someTimestamp": "2020-01-02T03:04:05+01:00"}`,
+	`{"someDuration": "1m"}`,
+	`{"someStatus": 1, "someStatusRep": ["STATUS_ACTIVE", 2]}`,
+	`{"someStringRep": ["a", null]}`,
+	`{"someString": null, "someEmbedded": null, "someWrappedInt": null}`,
+	`{"email": "a@b.c", "phone": "123"}`,
+	`{"someInt32ToString": {"01": "x"}}`,
+	`{"someBytes": "-_8"}`,
+	`{"unknown": 1}`,
+	`{"some_string": "x", "someString": "y"}`,
+	`{"someUint32": -1}`,
+}
+
+func TestUnmarshal_ProtoJSONParity(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithProtoJSON())
+	for _, input := range protoJSONCases {
+		want := &validatortest.Message3{}
+		wantErr := protojson.Unmarshal([]byte(input), want)
+		got := &validatortest.Message3{SomeString: "previous"}
+		err := u.Unmarshal(strings.NewReader(input), got)
+		if wantErr != nil {
+			require.Error(t, err, input)
+			continue
+		}
+		require.NoError(t, err, input)
+		require.True(t, proto.Equal(want, got), "%s: got %v, want %v", input, got, want)
+	}
+}
+
+func TestUnmarshal_ProtoJSONFieldErrors(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithProtoJSON())
+	err := u.Unmarshal(strings.NewReader(`{"someEmbedded": {"someValue": "x"}}`), &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeEmbedded.SomeValue: invalid character 'x' looking for beginning of value while looking for an integer in a string`)
+
+	err = u.Unmarshal(strings.NewReader(`{"someEmbedded": {"unknown": 1}}`), &validatortest.Message3{})
+	require.Error(t, err)
+	require.NoError(t, u.With(nicejsonpb.WithAllowUnknownFields()).Unmarshal(strings.NewReader(`{"someEmbedded": {"unknown": 1}}`), &validatortest.Message3{}))
+}
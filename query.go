@@ -0,0 +1,155 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UnmarshalQuery decodes the parameters of a query string, or of any url.Values, into pb. Parameters
+// are named by the path of the field they set, such as item.quantity, with every field named by its
+// original or JSON name, and map entries by the key in brackets, such as labels[env]. Repeating a
+// parameter sets the elements of a repeated field. Values are decoded as the same JSON strings
+// would be, with AcceptStringNumbers and CoerceStringBools turned on, and fail with the same field
+// errors.
+func (u *Unmarshaler) UnmarshalQuery(values url.Values, pb proto.Message) error {
+	d := u.newDecodeState()
+	defer d.release()
+	opts := d.options()
+	opts.AcceptStringNumbers = true
+	opts.CoerceStringBools = true
+	d.Unmarshaler = &opts
+
+	doc := queryObject{}
+	names := make([]string, 0, len(values))
+	for name, vals := range values {
+		// A parameter with no values, which url.Values can hold, is as if it was absent.
+		if len(vals) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	md := proto.MessageReflect(pb).Descriptor()
+	for _, name := range names {
+		if err := doc.set(md, strings.Split(name, "."), values[name], nil, nil); err != nil {
+			return d.formatErrors(err)
+		}
+	}
+	inputValue, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return d.unmarshalDocument(pb, inputValue)
+}
+
+// UnmarshalQuery decodes the parameters of a query string into pb with the default options.
+func UnmarshalQuery(values url.Values, pb proto.Message) error {
+	return new(Unmarshaler).UnmarshalQuery(values, pb)
+}
+
+// queryObject is the JSON object of a message set from query parameters. Its values are strings,
+// lists of strings for repeated fields, and queryObjects for messages and maps.
+type queryObject map[string]interface{}
+
+// set sets vals, the values of the parameter naming the field at path within o, a message of type
+// md. Parameters naming unknown fields are set as they are, to be reported as unknown fields.
+// fieldStack and keys lead to o, named as in field errors.
+func (o queryObject) set(md protoreflect.MessageDescriptor, path []string, vals []string, fieldStack []string, keys []string) error {
+	name, mapKey, isEntry := splitQueryName(path[0])
+	fd := md.Fields().ByJSONName(name)
+	if fd == nil {
+		fd = md.Fields().ByName(protoreflect.Name(name))
+	}
+	if fd == nil {
+		fd = md.Fields().ByTextName(name)
+	}
+	if fd == nil {
+		o[strings.Join(path, ".")] = queryValue(vals, len(vals) > 1)
+		return nil
+	}
+	fieldStack = append(fieldStack, goCamelCase(string(fd.Name())))
+	keys = append(keys, name)
+	fail := func(format string, args ...interface{}) error {
+		return &fieldError{fieldStack: fieldStack, keys: keys, nestedErr: codeErrorf(CodeTypeMismatch, format, args...)}
+	}
+	last := len(path) == 1
+	switch {
+	case isEntry && !fd.IsMap():
+		return fail("only map fields have entries set with %s", path[0])
+	case isEntry:
+		entries, ok := o.child(name)
+		if !ok {
+			return fail("set both as a whole and by its entries")
+		}
+		fieldStack = append(fieldStack, "['"+mapKey+"']value")
+		keys = append(keys, mapKey)
+		if last {
+			entries[mapKey] = queryValue(vals, false)
+			return checkSingular(vals, fieldStack, keys)
+		}
+		if fd.MapValue().Message() == nil {
+			return &fieldError{fieldStack: fieldStack, keys: keys, nestedErr: codeErrorf(CodeTypeMismatch, "values of this map have no fields")}
+		}
+		entry, ok := entries.child(mapKey)
+		if !ok {
+			return &fieldError{fieldStack: fieldStack, keys: keys, nestedErr: codeErrorf(CodeTypeMismatch, "set both as a whole and by its fields")}
+		}
+		return entry.set(fd.MapValue().Message(), path[1:], vals, fieldStack, keys)
+	case last:
+		o[name] = queryValue(vals, fd.IsList())
+		if fd.IsList() {
+			return nil
+		}
+		return checkSingular(vals, fieldStack, keys)
+	case fd.IsList() || fd.IsMap() || fd.Message() == nil:
+		return fail("only singular message fields have fields set with %s", strings.Join(path, "."))
+	}
+	child, ok := o.child(name)
+	if !ok {
+		return fail("set both as a whole and by its fields")
+	}
+	return child.set(fd.Message(), path[1:], vals, fieldStack, keys)
+}
+
+// child returns the queryObject held in o under key, adding it if there is nothing there, or false
+// if something else is.
+func (o queryObject) child(key string) (queryObject, bool) {
+	v, ok := o[key]
+	if !ok {
+		child := queryObject{}
+		o[key] = child
+		return child, true
+	}
+	child, ok := v.(queryObject)
+	return child, ok
+}
+
+// queryValue is the JSON value of the values of a parameter, a list if repeated.
+func queryValue(vals []string, repeated bool) interface{} {
+	if repeated {
+		return vals
+	}
+	return vals[len(vals)-1]
+}
+
+// checkSingular fails if a parameter setting a singular field is given more than once.
+func checkSingular(vals []string, fieldStack []string, keys []string) error {
+	if len(vals) > 1 {
+		return &fieldError{fieldStack: fieldStack, keys: keys, nestedErr: codeErrorf(CodeTypeMismatch, "%d values given for a singular field", len(vals))}
+	}
+	return nil
+}
+
+// splitQueryName splits a parameter name such as labels[env] into the name of the map field and
+// the key of the entry.
+func splitQueryName(s string) (name string, key string, isEntry bool) {
+	open := strings.IndexByte(s, '[')
+	if open < 0 || !strings.HasSuffix(s, "]") {
+		return s, "", false
+	}
+	return s[:open], s[open+1 : len(s)-1], true
+}
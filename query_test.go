@@ -0,0 +1,64 @@
+package nicejsonpb_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalQuery(t *testing.T) {
+	values, err := url.ParseQuery("someString=hi&some_int32=3&someBool=1&someStringRep=a&someStringRep=b&someIntRep=7" +
+		"&someEmbedded.identifier=x&someEmbedded.some_value=5&someStatus=STATUS_ACTIVE&someStringToInt64[a]=1" +
+		"&someStringToEmbedded[k].identifier=y&someTimestamp=2024-05-01T10:00:00Z&someWrappedInt=9")
+	require.NoError(t, err)
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalQuery(values, m))
+	require.Equal(t, "hi", m.SomeString)
+	require.Equal(t, int32(3), m.SomeInt32)
+	require.True(t, m.SomeBool)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, []uint32{7}, m.SomeIntRep)
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Equal(t, int64(5), m.SomeEmbedded.SomeValue)
+	require.Equal(t, validatortest.Status_STATUS_ACTIVE, m.SomeStatus)
+	require.Equal(t, map[string]int64{"a": 1}, m.SomeStringToInt64)
+	require.Equal(t, "y", m.SomeStringToEmbedded["k"].Identifier)
+	require.Equal(t, int64(1714557600), m.SomeTimestamp.Seconds)
+	require.Equal(t, int64(9), m.SomeWrappedInt.Value)
+}
+
+func TestUnmarshalQuery_Errors(t *testing.T) {
+	for query, want := range map[string]string{
+		"someEmbedded.someValue=x":        "unparsable field SomeEmbedded.SomeValue:",
+		"someInt32=1&someInt32=2":         "unparsable field SomeInt32: 2 values given for a singular field",
+		"someString.x=1":                  "unparsable field SomeString: only singular message fields have fields set with someString.x",
+		"someEmbedded=1&someEmbedded.x=2": "unparsable field SomeEmbedded: set both as a whole and by its fields",
+		"someString[a]=1":                 "unparsable field SomeString: only map fields have entries set with someString[a]",
+		"someEmbedded.bogus=1":            "unparsable field SomeEmbedded: ",
+	} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		err = nicejsonpb.UnmarshalQuery(values, &validatortest.Message3{})
+		require.Error(t, err, query)
+		require.Contains(t, err.Error(), want, query)
+	}
+
+	values := url.Values{"someEmbedded.children.identifier": {"x"}}
+	err := nicejsonpb.UnmarshalQuery(values, &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Children: only singular message fields have fields set with children.identifier")
+	require.Equal(t, "/someEmbedded/children", nicejsonpb.ErrorPointer(err))
+
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, u.UnmarshalQuery(url.Values{"utm_source": {"mail"}}, &validatortest.Message3{}))
+}
+
+func TestUnmarshalQuery_NoValues(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalQuery(url.Values{"someString": {}, "someStringToInt64[a]": {}, "someInt32": {"3"}}, m))
+	require.Equal(t, "", m.SomeString)
+	require.Empty(t, m.SomeStringToInt64)
+	require.Equal(t, int32(3), m.SomeInt32)
+}
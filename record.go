@@ -0,0 +1,112 @@
+package nicejsonpb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// RecordDecoder decodes the records of message queues, such as Kafka, SQS or Pub/Sub, each holding
+// a JSON document, for consumers going through many of them. Messages are taken from a pool and
+// should be handed back with Release once processed, and failures are counted per topic. A
+// RecordDecoder is safe for concurrent use, so the OnWarning and UnknownFieldSink callbacks of its
+// Unmarshaler must be too.
+type RecordDecoder struct {
+	u    *Unmarshaler
+	pool sync.Pool
+
+	mu    sync.Mutex
+	stats map[string]*RecordStats
+}
+
+// RecordStats counts the records of a topic decoded by a RecordDecoder.
+type RecordStats struct {
+	// Records is the number of records decoded, including the ones that failed.
+	Records int64
+	// Failed is the number of records that failed to decode.
+	Failed int64
+	// FailedByCode breaks Failed down by the code of the errors.
+	FailedByCode map[Code]int64
+}
+
+// NewRecordDecoder returns a RecordDecoder with the options of u, decoding into messages obtained
+// from factory when the pool is empty.
+func (u *Unmarshaler) NewRecordDecoder(factory func() proto.Message) *RecordDecoder {
+	d := &RecordDecoder{u: u, stats: map[string]*RecordStats{}}
+	d.pool.New = func() interface{} { return factory() }
+	return d
+}
+
+// NewRecordDecoder returns a RecordDecoder with the default options.
+func NewRecordDecoder(factory func() proto.Message) *RecordDecoder {
+	return new(Unmarshaler).NewRecordDecoder(factory)
+}
+
+// Decode decodes record, read from topic, into a message from the pool. Errors are returned as a
+// *RecordError carrying topic and id, which identifies the record for the caller, such as its
+// partition and offset. The message is nil if decoding failed.
+func (d *RecordDecoder) Decode(topic string, id string, record []byte) (proto.Message, error) {
+	pb := d.pool.Get().(proto.Message)
+	err := d.u.UnmarshalBytes(record, pb)
+	d.count(topic, err)
+	if err != nil {
+		d.Release(pb)
+		return nil, &RecordError{Topic: topic, ID: id, Err: err}
+	}
+	return pb, nil
+}
+
+// Release resets pb, a message returned by Decode, and puts it back in the pool. pb must not be
+// used afterwards.
+func (d *RecordDecoder) Release(pb proto.Message) {
+	pb.Reset()
+	d.pool.Put(pb)
+}
+
+func (d *RecordDecoder) count(topic string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := d.stats[topic]
+	if stats == nil {
+		stats = &RecordStats{FailedByCode: map[Code]int64{}}
+		d.stats[topic] = stats
+	}
+	stats.Records++
+	if err != nil {
+		stats.Failed++
+		stats.FailedByCode[ErrorCode(err)]++
+	}
+}
+
+// Stats returns a copy of the statistics of every topic Decode has seen, by topic.
+func (d *RecordDecoder) Stats() map[string]RecordStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	all := make(map[string]RecordStats, len(d.stats))
+	for topic, stats := range d.stats {
+		byCode := make(map[Code]int64, len(stats.FailedByCode))
+		for code, n := range stats.FailedByCode {
+			byCode[code] = n
+		}
+		all[topic] = RecordStats{Records: stats.Records, Failed: stats.Failed, FailedByCode: byCode}
+	}
+	return all
+}
+
+// RecordError is an error decoding the record of a topic, identified by ID.
+type RecordError struct {
+	Topic string
+	ID    string
+	Err   error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %s of topic %s: %s", e.ID, e.Topic, e.Err)
+}
+
+// Unwrap gives errors.Is, errors.As and the functions of the package, such as ErrorCode and
+// ErrorPointer, access to the error of the record.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
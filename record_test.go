@@ -0,0 +1,62 @@
+package nicejsonpb_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDecoder(t *testing.T) {
+	dec := nicejsonpb.NewRecordDecoder(func() proto.Message { return &validatortest.Message3{} })
+	pb, err := dec.Decode("orders", "0:41", []byte(`{"someString": "first"}`))
+	require.NoError(t, err)
+	require.Equal(t, "first", pb.(*validatortest.Message3).SomeString)
+	dec.Release(pb)
+
+	pb, err = dec.Decode("orders", "0:42", []byte(`{"someInt32": 1}`))
+	require.NoError(t, err)
+	require.Empty(t, pb.(*validatortest.Message3).SomeString)
+	dec.Release(pb)
+
+	pb, err = dec.Decode("orders", "0:43", []byte(`{"someInt32": "x"}`))
+	require.Nil(t, pb)
+	require.EqualError(t, err, "record 0:43 of topic orders: unparsable field SomeInt32: json: cannot unmarshal string into Go value of type int32")
+	var recErr *nicejsonpb.RecordError
+	require.ErrorAs(t, err, &recErr)
+	require.Equal(t, "0:43", recErr.ID)
+	require.Equal(t, "/someInt32", nicejsonpb.ErrorPointer(err))
+
+	_, err = dec.Decode("refunds", "7", []byte(`{"nope": 1}`))
+	require.Equal(t, nicejsonpb.CodeUnknownField, nicejsonpb.ErrorCode(err))
+
+	require.Equal(t, map[string]nicejsonpb.RecordStats{
+		"orders":  {Records: 3, Failed: 1, FailedByCode: map[nicejsonpb.Code]int64{nicejsonpb.CodeTypeMismatch: 1}},
+		"refunds": {Records: 1, Failed: 1, FailedByCode: map[nicejsonpb.Code]int64{nicejsonpb.CodeUnknownField: 1}},
+	}, dec.Stats())
+}
+
+func TestRecordDecoder_Concurrent(t *testing.T) {
+	dec := nicejsonpb.NewRecordDecoder(func() proto.Message { return &validatortest.Message3{} })
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				want := fmt.Sprintf("%d-%d", w, i)
+				pb, err := dec.Decode("events", want, []byte(`{"someString": "`+want+`"}`))
+				if err != nil || pb.(*validatortest.Message3).SomeString != want {
+					t.Errorf("record %s decoded as %v, %v", want, pb, err)
+				}
+				dec.Release(pb)
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.Equal(t, int64(800), dec.Stats()["events"].Records)
+}
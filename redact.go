@@ -0,0 +1,45 @@
+package nicejsonpb
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxEchoedValue is how many bytes of a value from the JSON input at most appear in an error
+// message or warning, so that a huge bad value doesn't make for a huge error.
+const maxEchoedValue = 64
+
+// echo returns s, a value taken from the JSON input, the way it appears in error messages and
+// warnings: formatted with format and cut short after maxEchoedValue bytes, or when redacting
+// replaced by a placeholder giving only what kind of value it was and its length.
+func (d *decodeState) echo(format string, kind string, s string) string {
+	if d.redacting() {
+		return fmt.Sprintf("<%s of %d bytes>", kind, len(s))
+	}
+	if len(s) <= maxEchoedValue {
+		return fmt.Sprintf(format, s)
+	}
+	cut := maxEchoedValue
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf(format, s[:cut]) + "…(" + formatSize(len(s)-cut) + " truncated)"
+}
+
+// echoesVerbatim reports whether echo gives back s unchanged, so that errors from other packages
+// that quote it can be passed on as they are.
+func (d *decodeState) echoesVerbatim(s string) bool {
+	return !d.redacting() && len(s) <= maxEchoedValue
+}
+
+// formatSize formats a number of bytes for humans, such as 1.2KB.
+func formatSize(n int) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%dB", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	}
+}
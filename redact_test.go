@@ -0,0 +1,48 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_RedactValues(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithRedactValues())
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`{"someStatus": "secret-token"}`, "unparsable field SomeStatus: unknown value '<string of 12 bytes>' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]"},
+		{`{"someUint32": 4294967296}`, "unparsable field SomeUint32: value <number of 10 bytes> out of range for uint32"},
+		{`{"someInt32": 1.5}`, "unparsable field SomeInt32: value <number of 3 bytes> is not an integer, expected int32"},
+		{`{"someInt32ToString": {"hunter2": "x"}}`, "unparsable field SomeInt32ToString: bad map key '<string of 7 bytes>' for map<int32, string>"},
+		{`{"someDuration": "hunter2"}`, "unparsable field SomeDuration: bad Duration: <string of 7 bytes> isn't a duration"},
+		{`{"someTimestamp": "hunter2"}`, "unparsable field SomeTimestamp: bad Timestamp: <string of 7 bytes> isn't an RFC 3339 timestamp"},
+	} {
+		err := u.Unmarshal(strings.NewReader(tc.input), &validatortest.Message3{})
+		require.EqualError(t, err, tc.err, tc.input)
+
+		_, err = u.UnmarshalDynamic(strings.NewReader(tc.input), message3Descriptor(t))
+		require.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestUnmarshal_RedactValuesKeepsMapKeysInPaths(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithRedactValues())
+	err := u.Unmarshal(strings.NewReader(`{"someStringToStatus": {"alice": "secret-token"}}`), &validatortest.Message3{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "alice")
+	require.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestUnmarshal_TruncatesLongValuesInErrors(t *testing.T) {
+	long := strings.Repeat("x", 64) + strings.Repeat("y", 1200)
+	err := nicejsonpb.UnmarshalString(`{"someStatus": "`+long+`"}`, &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeStatus: unknown value '"`+strings.Repeat("x", 64)+`"…(1.2KB truncated)' for enum validatortest.Status, expected one of [STATUS_UNKNOWN STATUS_ACTIVE STATUS_DISABLED]`)
+
+	err = nicejsonpb.UnmarshalString(`{"someTimestamp": "`+long+`"}`, &validatortest.Message3{})
+	require.EqualError(t, err, `unparsable field SomeTimestamp: bad Timestamp: "`+strings.Repeat("x", 64)+`"…(1.2KB truncated) isn't an RFC 3339 timestamp`)
+}
@@ -0,0 +1,107 @@
+package nicejsonpb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MultiError is returned for checks that report all the problems they find at once, such as missing
+// required fields.
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap gives errors.Is and errors.As access to all the errors.
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
+// errRequiredFieldMissing is reported for every required field that isn't set when CheckRequiredFields
+// is enabled.
+var errRequiredFieldMissing = newCodedError(CodeRequiredFieldMissing, "required field is missing")
+
+// finishDocument runs the steps that apply to a whole decoded message.
+func (d *decodeState) finishDocument(m protoreflect.Message) error {
+	if d.ApplyDefaults {
+		applyDefaults(m)
+	}
+	errs := append(MultiError{}, d.errs...)
+	errs = append(errs, d.missingRequired...)
+	if d.CheckRequiredFields {
+		checkRequiredFields(m, nil, &errs)
+	}
+	errs = d.capErrors(errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MoreErrors ends the MultiError of an unmarshal that found more than MaxErrors errors, counting
+// those left out.
+type MoreErrors struct {
+	Count int
+}
+
+func (m *MoreErrors) Error() string {
+	return fmt.Sprintf("and %d more errors", m.Count)
+}
+
+// capErrors cuts errs down to MaxErrors, adding a MoreErrors for those dropped there and in collect.
+func (d *decodeState) capErrors(errs MultiError) MultiError {
+	more := d.droppedErrs
+	if d.MaxErrors > 0 && len(errs) > d.MaxErrors {
+		more += len(errs) - d.MaxErrors
+		errs = errs[:d.MaxErrors]
+	}
+	if more > 0 {
+		errs = append(errs, &MoreErrors{Count: more})
+	}
+	return errs
+}
+
+// checkRequiredFields adds an error to errs for every proto2 required field not set in m or the
+// messages nested in it. path is that of m, named as in field errors.
+func checkRequiredFields(m protoreflect.Message, path []string, errs *MultiError) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fieldPath := append(path[:len(path):len(path)], goCamelCase(string(fd.Name())))
+		if fd.Cardinality() == protoreflect.Required && !m.Has(fd) {
+			*errs = append(*errs, &fieldError{fieldStack: fieldPath, nestedErr: errRequiredFieldMissing})
+			continue
+		}
+		if !m.Has(fd) {
+			continue
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := m.Get(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				checkRequiredFields(list.Get(i).Message(), append(fieldPath, fmt.Sprintf("[%d]", i)), errs)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			mapValue := m.Get(fd).Map()
+			keys := []protoreflect.MapKey{}
+			mapValue.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, k := range keys {
+				checkRequiredFields(mapValue.Get(k).Message(), append(fieldPath, fmt.Sprintf("['%s']value", k.String())), errs)
+			}
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			checkRequiredFields(m.Get(fd).Message(), fieldPath, errs)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package nicejsonpb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+const missingRequiredInput = `{"inner": {"label": "x"}, "items": [{"id": 1}, {}], "itemsByName": {"b": {}, "a": {"id": 2}}}`
+
+func TestUnmarshal_RequiredFieldsNotCheckedByDefault(t *testing.T) {
+	require.NoError(t, nicejsonpb.UnmarshalString(missingRequiredInput, &validatortest.Required2{}))
+}
+
+func TestUnmarshal_CheckRequiredFields(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{CheckRequiredFields: true}
+	err := u.Unmarshal(strings.NewReader(missingRequiredInput), &validatortest.Required2{})
+	var multi nicejsonpb.MultiError
+	require.True(t, errors.As(err, &multi))
+	require.Len(t, multi, 4)
+	require.EqualError(t, err, "unparsable field Name: required field is missing; "+
+		"unparsable field Inner.Id: required field is missing; "+
+		"unparsable field Items.[1].Id: required field is missing; "+
+		"unparsable field ItemsByName.['b']value.Id: required field is missing")
+
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"name": "x", "inner": {"id": 1}}`), &validatortest.Required2{}))
+}
+
+func TestUnmarshalMessage_CheckRequiredFields(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{CheckRequiredFields: true}
+	m := (&validatortest.Required2{}).ProtoReflect().Type().New().Interface()
+	err := u.UnmarshalMessage(strings.NewReader(`{"inner": {"id": 1}}`), m)
+	require.EqualError(t, err, "unparsable field Name: required field is missing")
+}
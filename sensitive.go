@@ -0,0 +1,56 @@
+package nicejsonpb
+
+import (
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// SensitiveExtension returns a SensitiveFields function marking the fields that have the boolean
+// field option xt set to true, for codebases with their own annotation in place of debug_redact.
+func SensitiveExtension(xt protoreflect.ExtensionType) func(fd protoreflect.FieldDescriptor) bool {
+	return func(fd protoreflect.FieldDescriptor) bool {
+		opts := fd.Options()
+		if opts == nil || !protov2.HasExtension(opts, xt) {
+			return false
+		}
+		set, _ := protov2.GetExtension(opts, xt).(bool)
+		return set
+	}
+}
+
+// isSensitive reports whether the values of fd and everything nested in it must not be echoed,
+// because it is annotated with debug_redact or picked by SensitiveFields.
+func (d *decodeState) isSensitive(fd protoreflect.FieldDescriptor) bool {
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+		return true
+	}
+	return d.SensitiveFields != nil && d.SensitiveFields(fd)
+}
+
+// redactingField is redactingDynamicField for the field with the given number of the message held
+// in target.
+func (d *decodeState) redactingField(target proto.Message, number int, fn func() error) error {
+	fd := proto.MessageReflect(target).Descriptor().Fields().ByNumber(protoreflect.FieldNumber(number))
+	if fd == nil {
+		return fn()
+	}
+	return d.redactingDynamicField(fd, fn)
+}
+
+// redactingDynamicField runs fn, which decodes fd, with every value taken from the JSON left out
+// of errors, warnings and the UnknownFieldSink if fd is sensitive.
+func (d *decodeState) redactingDynamicField(fd protoreflect.FieldDescriptor, fn func() error) error {
+	if !d.isSensitive(fd) {
+		return fn()
+	}
+	d.sensitive++
+	defer func() { d.sensitive-- }()
+	return fn()
+}
+
+// redacting reports whether values taken from the JSON must be left out of errors and warnings.
+func (d *decodeState) redacting() bool {
+	return d.RedactValues || d.sensitive > 0
+}
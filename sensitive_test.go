@@ -0,0 +1,52 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestUnmarshal_DebugRedactFields(t *testing.T) {
+	desc := (&validatortest.Credentials{}).ProtoReflect().Descriptor()
+	u := nicejsonpb.New()
+
+	input := `{"attempts": 99999999999}`
+	err := u.Unmarshal(strings.NewReader(input), &validatortest.Credentials{})
+	require.EqualError(t, err, "unparsable field Attempts: value 99999999999 out of range for int32")
+
+	input = `{"pin": 99999999999}`
+	err = u.Unmarshal(strings.NewReader(input), &validatortest.Credentials{})
+	require.EqualError(t, err, "unparsable field Pin: value <number of 11 bytes> out of range for int32")
+	require.Nil(t, nicejsonpb.ErrorRaw(err))
+	_, err = u.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.EqualError(t, err, "unparsable field Pin: value <number of 11 bytes> out of range for int32")
+
+	input = `{"previous": {"attempts": 99999999999}}`
+	err = u.Unmarshal(strings.NewReader(input), &validatortest.Credentials{})
+	require.EqualError(t, err, "unparsable field Previous.Attempts: value <number of 11 bytes> out of range for int32")
+	_, err = u.UnmarshalDynamic(strings.NewReader(input), desc)
+	require.EqualError(t, err, "unparsable field Previous.Attempts: value <number of 11 bytes> out of range for int32")
+}
+
+func TestUnmarshal_SensitiveFieldsSink(t *testing.T) {
+	sunk := map[string]json.RawMessage{}
+	u := nicejsonpb.New(nicejsonpb.WithUnknownFieldSink(func(path string, key string, raw json.RawMessage) {
+		sunk[path+"/"+key] = raw
+	}))
+	input := `{"extra": 1, "previous": {"extra": 2}}`
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), &validatortest.Credentials{}))
+	require.Equal(t, map[string]json.RawMessage{"/extra": json.RawMessage("1"), "Previous/extra": nil}, sunk)
+}
+
+func TestUnmarshal_SensitiveFields(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithSensitiveFields(func(fd protoreflect.FieldDescriptor) bool {
+		return fd.Name() == "attempts"
+	}))
+	err := u.Unmarshal(strings.NewReader(`{"attempts": 99999999999}`), &validatortest.Credentials{})
+	require.EqualError(t, err, "unparsable field Attempts: value <number of 11 bytes> out of range for int32")
+}
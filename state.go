@@ -0,0 +1,302 @@
+package nicejsonpb
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// decodeState holds everything specific to a single unmarshal call, leaving
+// the Unmarshaler itself to only carry configuration.
+type decodeState struct {
+	*Unmarshaler
+
+	// path is the stack of fields leading to the value being decoded, named
+	// the same way as in field errors, and keys holds their keys in the JSON.
+	path []string
+	keys []string
+
+	// recordFields enables collecting setFields, the proto paths of all
+	// fields present in the JSON. Only fields reached through singular
+	// message fields are recorded, as nothing deeper fits in a FieldMask.
+	recordFields bool
+	setFields    []string
+	// protoPath is the stack of proto names of the message fields leading to
+	// the value being decoded, and unmaskable counts the repeated fields, maps
+	// and extensions on the way there.
+	protoPath  []string
+	unmaskable int
+
+	// missingRequired collects the REQUIRED fields found missing, reported
+	// once the whole document is decoded.
+	missingRequired MultiError
+
+	// depth is the number of messages being decoded, nested in one another.
+	depth int
+	// errs collects the errors of failed fields when AllErrors is set, up
+	// to MaxErrors of them, and droppedErrs counts those beyond.
+	errs        MultiError
+	droppedErrs int
+
+	// rejectQuotedIntegers, canonicalTimestamps and canonicalDurations are
+	// the checks of ProfileStrictConformance that have no option of their own,
+	// and protoJSONDurations, rejectDuplicateKeys and rejectNullDocument
+	// those it shares with ProfileStandard.
+	rejectQuotedIntegers bool
+	canonicalTimestamps  bool
+	canonicalDurations   bool
+	protoJSONDurations   bool
+	rejectDuplicateKeys  bool
+	rejectNullDocument   bool
+
+	// lazyAnys, if set, receives the JSON of the Anys decoded, which are
+	// left holding no message.
+	lazyAnys LazyAnys
+
+	// nodes counts the messages, list elements and map entries decoded.
+	nodes int
+	// ctx is checked every so many nodes when decoding with UnmarshalContext.
+	ctx context.Context
+	// stats, if set, is filled in while decoding with UnmarshalWithStats or
+	// an Observer.
+	stats *Stats
+	// observerCtx is the context returned by Observer.OnStart, set while the
+	// Observer is being told about the document.
+	observerCtx context.Context
+	// sensitive counts the sensitive fields being decoded, nested in one
+	// another, whose values must not be echoed.
+	sensitive int
+}
+
+var decodeStatePool = sync.Pool{
+	New: func() interface{} { return new(decodeState) },
+}
+
+// newDecodeState returns a decodeState for a single call using the options
+// of u, which must be given back with release once the call is over.
+func (u *Unmarshaler) newDecodeState() *decodeState {
+	d := decodeStatePool.Get().(*decodeState)
+	d.Unmarshaler = u
+	if u.Profile != ProfileNone {
+		d.applyProfile()
+	}
+	return d
+}
+
+// release clears d and puts it back in the pool. Only the backing arrays of
+// its stacks are kept, as nothing decoded refers to them.
+func (d *decodeState) release() {
+	*d = decodeState{path: d.path[:0], keys: d.keys[:0], protoPath: d.protoPath[:0]}
+	decodeStatePool.Put(d)
+}
+
+// unmarshalDocument decodes a whole JSON document into pb using a pooled
+// decodeState.
+func (u *Unmarshaler) unmarshalDocument(pb proto.Message, inputValue json.RawMessage) error {
+	d := u.newDecodeState()
+	defer d.release()
+	return d.unmarshalDocument(pb, inputValue)
+}
+
+// unmarshalDynamicDocument is unmarshalDocument for dynamic messages.
+func (u *Unmarshaler) unmarshalDynamicDocument(m protoreflect.Message, inputValue json.RawMessage) error {
+	d := u.newDecodeState()
+	defer d.release()
+	return d.unmarshalDynamicDocument(m, inputValue)
+}
+
+// within runs fn with name, which has the given key in the JSON, pushed onto
+// the path, prefixing any error it returns with name.
+func (d *decodeState) within(name string, key string, fn func() error) error {
+	d.path = append(d.path, name)
+	d.keys = append(d.keys, key)
+	err := fn()
+	d.path = d.path[:len(d.path)-1]
+	d.keys = d.keys[:len(d.keys)-1]
+	if err != nil {
+		return fieldErrorAt(name, key, err)
+	}
+	return nil
+}
+
+// withinField is within for the message field called name, with the given
+// proto name, decoding raw.
+func (d *decodeState) withinField(name string, origName string, key string, raw json.RawMessage, fn func() error) error {
+	d.protoPath = append(d.protoPath, origName)
+	err := d.within(name, key, fn)
+	d.protoPath = d.protoPath[:len(d.protoPath)-1]
+	return d.withRaw(err, raw)
+}
+
+// withinElement is within for repeated field elements, map entries and
+// extensions, decoding raw.
+func (d *decodeState) withinElement(name string, key string, raw json.RawMessage, fn func() error) error {
+	d.unmaskable++
+	err := d.within(name, key, fn)
+	d.unmaskable--
+	return d.withRaw(err, raw)
+}
+
+// withRaw records a copy of raw as the value err is about, when err is
+// about the field raw is the value of rather than one nested in it. Values of
+// sensitive fields are never recorded.
+func (d *decodeState) withRaw(err error, raw json.RawMessage) error {
+	if fErr, ok := err.(*fieldError); ok && fErr.raw == nil && len(fErr.fieldStack) == 1 && d.sensitive == 0 {
+		fErr.raw = append(json.RawMessage{}, raw...)
+	}
+	return err
+}
+
+// enterMessage counts one more level of message nesting, failing if that
+// goes beyond MaxDepth. Every successful call must be paired with leaveMessage.
+func (d *decodeState) enterMessage() error {
+	if err := d.step(); err != nil {
+		return err
+	}
+	if d.MaxDepth > 0 && d.depth >= d.MaxDepth {
+		return codeErrorf(CodeLimitExceeded, "messages nested more than %d deep", d.MaxDepth)
+	}
+	d.depth++
+	if d.stats != nil && d.depth > d.stats.MaxDepth {
+		d.stats.MaxDepth = d.depth
+	}
+	return nil
+}
+
+func (d *decodeState) leaveMessage() {
+	d.depth--
+}
+
+// step is called for every message, list element and map entry decoded,
+// failing once there are more than MaxNodes of them or the context of the
+// unmarshal is done.
+func (d *decodeState) step() error {
+	d.nodes++
+	if d.overBudget() {
+		return codeErrorf(CodeLimitExceeded, "more than %d messages, list elements and map entries", d.MaxNodes)
+	}
+	return d.checkContext()
+}
+
+func (d *decodeState) overBudget() bool {
+	return d.MaxNodes > 0 && d.nodes > d.MaxNodes
+}
+
+// collect returns err unchanged, unless AllErrors is set, in which case it
+// records err with the full path of the value being decoded and returns nil,
+// so that decoding goes on with the next field. Running out of nodes or
+// time still stops decoding.
+func (d *decodeState) collect(err error) error {
+	if err == nil || !d.AllErrors || d.overBudget() || (d.ctx != nil && d.ctx.Err() != nil) {
+		return err
+	}
+	path := append([]string{}, d.path...)
+	keys := append([]string{}, d.keys...)
+	if fErr, ok := err.(*fieldError); ok {
+		err = &fieldError{fieldStack: append(path, fErr.fieldStack...), keys: append(keys, fErr.pointerKeys()...), nestedErr: fErr.nestedErr, raw: fErr.raw}
+	} else if len(path) > 0 {
+		err = &fieldError{fieldStack: path, keys: keys, nestedErr: err}
+	}
+	if d.MaxErrors > 0 && len(d.errs) >= d.MaxErrors {
+		d.droppedErrs++
+		return nil
+	}
+	d.errs = append(d.errs, err)
+	return nil
+}
+
+// splitRepeated splits the JSON array raw, the value of a repeated field,
+// into its elements. With WrapSingleValues, any other value is taken as the
+// only element.
+func (d *decodeState) splitRepeated(raw json.RawMessage) ([]json.RawMessage, error) {
+	if d.WrapSingleValues && len(raw) > 0 && raw[0] != '[' {
+		return []json.RawMessage{raw}, nil
+	}
+	return d.tokens().splitArray(raw)
+}
+
+// recordSetField notes that the field with the given proto name is present
+// in the JSON of the message being decoded.
+func (d *decodeState) recordSetField(origName string) {
+	d.countField()
+	if d.recordFields && d.unmaskable == 0 {
+		d.setFields = append(d.setFields, strings.Join(append(d.protoPath, origName), "."))
+	}
+}
+
+// unmarshalField decodes the value of a message field, which has the given
+// key in the JSON.
+func (d *decodeState) unmarshalField(prop *proto.Properties, key string, target reflect.Value, inputValue json.RawMessage) error {
+	return d.withinField(prop.Name, protoFieldName(prop), key, inputValue, func() error {
+		return d.unmarshalValue(target, inputValue, prop)
+	})
+}
+
+// unmarshalElement decodes a repeated field element, map entry or extension called name, which has
+// the given key in the JSON.
+func (d *decodeState) unmarshalElement(name string, key string, target reflect.Value, inputValue json.RawMessage, prop *proto.Properties) error {
+	return d.withinElement(name, key, inputValue, func() error {
+		return d.unmarshalValue(target, inputValue, prop)
+	})
+}
+
+// checkUnknownFields deals with the fields of an object that are left over
+// after decoding all known ones, either handing them to the UnknownFieldSink
+// or failing with the error built by mismatch.
+func (d *decodeState) checkUnknownFields(jsonFields map[string]json.RawMessage, mismatch func() error) error {
+	if len(jsonFields) == 0 {
+		return nil
+	}
+	if d.stats != nil {
+		d.stats.UnknownFields += len(jsonFields)
+	}
+	keys := sortedKeys(jsonFields)
+	if d.observerCtx != nil {
+		path := strings.Join(d.path, ".")
+		for _, k := range keys {
+			d.Observer.OnUnknownField(d.observerCtx, path, k)
+		}
+	}
+	if d.UnknownFieldPolicy != nil {
+		return d.applyUnknownFieldPolicy(jsonFields, keys, mismatch)
+	}
+	if d.UnknownFieldSink != nil {
+		path := strings.Join(d.path, ".")
+		for _, k := range keys {
+			d.UnknownFieldSink(path, k, d.sinkValue(jsonFields[k]))
+		}
+		return nil
+	}
+	if d.AllowUnknownFields {
+		for _, k := range keys {
+			d.warn(WarnUnknownField, k, "unknown field dropped")
+		}
+		return nil
+	}
+	return mismatch()
+}
+
+// sinkValue is raw as handed to the UnknownFieldSink, which is nil within
+// sensitive fields.
+func (d *decodeState) sinkValue(raw json.RawMessage) json.RawMessage {
+	if d.sensitive > 0 {
+		return nil
+	}
+	return raw
+}
+
+// sortedKeys returns the keys of a JSON object in order.
+func sortedKeys(fields map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
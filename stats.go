@@ -0,0 +1,58 @@
+package nicejsonpb
+
+import (
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Stats describes the shape of a decoded document, for services to export metrics about the
+// payloads they receive.
+type Stats struct {
+	// Bytes is the size of the JSON document.
+	Bytes int
+	// Fields is the number of message fields, including extensions, present in the document, at
+	// all levels of nesting.
+	Fields int
+	// UnknownFields is the number of object keys that matched no field, whether they were
+	// allowed or not.
+	UnknownFields int
+	// Nodes is the number of messages, list elements and map entries, as limited by MaxNodes.
+	Nodes int
+	// MaxDepth is how deeply messages were nested, as limited by MaxDepth.
+	MaxDepth int
+	// Duration is the time taken to read and decode the document.
+	Duration time.Duration
+}
+
+// UnmarshalWithStats is Unmarshal, filling in stats about the document, also when it fails to
+// decode.
+func (u *Unmarshaler) UnmarshalWithStats(r io.Reader, pb proto.Message, stats *Stats) error {
+	start := time.Now()
+	inputValue := getRawMessage()
+	defer putRawMessage(inputValue)
+	err := u.readDocument(r, inputValue)
+	if err == nil {
+		d := u.newDecodeState()
+		defer d.release()
+		d.stats = stats
+		err = d.unmarshalDocument(pb, *inputValue)
+		stats.Nodes = d.nodes
+	}
+	stats.Bytes = len(*inputValue)
+	stats.Duration = time.Since(start)
+	return err
+}
+
+// UnmarshalWithStats is Unmarshal, filling in stats about the document.
+func UnmarshalWithStats(r io.Reader, pb proto.Message, stats *Stats) error {
+	return new(Unmarshaler).UnmarshalWithStats(r, pb, stats)
+}
+
+// countField counts a field present in the document towards Stats.
+func (d *decodeState) countField() {
+	if d.stats != nil {
+		d.stats.Fields++
+	}
+}
@@ -0,0 +1,31 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalWithStats(t *testing.T) {
+	input := `{"someString": "a", "someIntRep": [1, 2], "someEmbedded": {"someValue": 3, "children": [{"identifier": "c"}]}, "extra": 1}`
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	var stats nicejsonpb.Stats
+	require.NoError(t, u.UnmarshalWithStats(strings.NewReader(input), &validatortest.Message3{}, &stats))
+	require.Equal(t, len(input), stats.Bytes)
+	require.Equal(t, 6, stats.Fields)
+	require.Equal(t, 1, stats.UnknownFields)
+	require.Equal(t, 6, stats.Nodes)
+	require.Equal(t, 3, stats.MaxDepth)
+	require.Greater(t, int64(stats.Duration), int64(0))
+}
+
+func TestUnmarshalWithStats_FailedDocument(t *testing.T) {
+	var stats nicejsonpb.Stats
+	err := nicejsonpb.UnmarshalWithStats(strings.NewReader(`{"someString": "a", "wat": 1}`), &validatortest.Message3{}, &stats)
+	require.Error(t, err)
+	require.Equal(t, 1, stats.Fields)
+	require.Equal(t, 1, stats.UnknownFields)
+}
@@ -0,0 +1,42 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// unmarshalStdTime decodes the JSON forms of google.protobuf.Timestamp and Duration into the
+// time.Time and time.Duration fields that gogo/protobuf generates for them with its stdtime and
+// stdduration options. It returns false for other targets, and for numbers held in time.Duration
+// fields, which may as well be plain integers.
+func (d *decodeState) unmarshalStdTime(target reflect.Value, inputValue json.RawMessage) (bool, error) {
+	switch target.Type() {
+	case timeType:
+		s, ns, err := d.parseTimestamp(inputValue)
+		if err == nil {
+			target.Set(reflect.ValueOf(time.Unix(s, int64(ns)).UTC()))
+		}
+		return true, err
+	case durationType:
+		if inputValue[0] != '"' {
+			return false, nil
+		}
+		s, ns, err := d.parseDuration(inputValue)
+		if err != nil {
+			return true, err
+		}
+		if s > math.MaxInt64/int64(time.Second)-1 || s < math.MinInt64/int64(time.Second)+1 {
+			return true, codeErrorf(CodeOutOfRange, "bad Duration: %s is out of range for a time.Duration, expected at most about 292 years", d.echo("%s", "string", string(inputValue)))
+		}
+		target.SetInt(s*1e9 + int64(ns))
+		return true, nil
+	}
+	return false, nil
+}
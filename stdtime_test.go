@@ -0,0 +1,43 @@
+package nicejsonpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/stretchr/testify/require"
+)
+
+// stdTimeMessage is a message as gogo/protobuf generates it with the stdtime and stdduration
+// options.
+type stdTimeMessage struct {
+	When     time.Time        `protobuf:"bytes,1,opt,name=when,proto3,stdtime" json:"when"`
+	Expires  *time.Time       `protobuf:"bytes,2,opt,name=expires,proto3,stdtime" json:"expires,omitempty"`
+	Timeout  time.Duration    `protobuf:"bytes,3,opt,name=timeout,proto3,stdduration" json:"timeout"`
+	Backoffs []*time.Duration `protobuf:"bytes,4,rep,name=backoffs,proto3,stdduration" json:"backoffs,omitempty"`
+}
+
+func (m *stdTimeMessage) Reset()         { *m = stdTimeMessage{} }
+func (m *stdTimeMessage) String() string { return "" }
+func (*stdTimeMessage) ProtoMessage()    {}
+
+func TestUnmarshal_StdTime(t *testing.T) {
+	m := &stdTimeMessage{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{
+		"when": "2024-03-01T10:00:00.5+02:00",
+		"expires": "2024-03-02T00:00:00Z",
+		"timeout": "1.5s",
+		"backoffs": ["1s", "2m"]
+	}`, m))
+	require.Equal(t, time.Date(2024, 3, 1, 8, 0, 0, 5e8, time.UTC), m.When)
+	require.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), *m.Expires)
+	require.Equal(t, 1500*time.Millisecond, m.Timeout)
+	require.Equal(t, 2*time.Minute, *m.Backoffs[1])
+
+	err := nicejsonpb.UnmarshalString(`{"when": "yesterday"}`, m)
+	require.Equal(t, nicejsonpb.CodeBadTimestamp, nicejsonpb.ErrorCode(err))
+	require.Equal(t, "/when", nicejsonpb.ErrorPointer(err))
+	err = nicejsonpb.New(nicejsonpb.WithExtendedDurations()).UnmarshalBytes([]byte(`{"backoffs": ["1s", "1000000d"]}`), m)
+	require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+	require.Equal(t, "/backoffs/1", nicejsonpb.ErrorPointer(err))
+}
@@ -0,0 +1,80 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// DocumentError is returned by UnmarshalNext for a document of a stream that couldn't be decoded.
+type DocumentError struct {
+	// Index is the ordinal of the document among those read by the Unmarshaler, starting at 1.
+	Index int
+	Err   error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document %d: %v", e.Index, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeArray reads a top-level JSON array of messages from dec one element at a time, without
+// buffering the whole array. For each element a fresh message is obtained from newMsg, populated and
+// handed to fn. Unmarshaling errors are prefixed with the element index, errors returned by fn are
+// passed through as-is and stop the decoding.
+func (u *Unmarshaler) DecodeArray(dec *json.Decoder, newMsg func() proto.Message, fn func(proto.Message) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	// Errors are formatted once the index is added to them.
+	plain := u.options()
+	plain.ErrorFormatter = nil
+	for i := 0; dec.More(); i++ {
+		msg := newMsg()
+		if err := plain.unmarshalNext(dec, msg); err != nil {
+			d := u.newDecodeState()
+			defer d.release()
+			return d.formatErrors(elementError(i, err))
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// DecodeArray reads a top-level JSON array of messages from dec one element at a time, handing each
+// one to fn.
+func DecodeArray(dec *json.Decoder, newMsg func() proto.Message, fn func(proto.Message) error) error {
+	return new(Unmarshaler).DecodeArray(dec, newMsg, fn)
+}
+
+// elementError prefixes err, or each of the errors of a MultiError, with the index i of the element
+// of the array it is about.
+func elementError(i int, err error) error {
+	name := fmt.Sprintf("[%d]", i)
+	if errs, ok := err.(MultiError); ok {
+		prefixed := make(MultiError, len(errs))
+		for j, e := range errs {
+			prefixed[j] = FieldError(name, e)
+		}
+		return prefixed
+	}
+	return FieldError(name, err)
+}
+
+// expectDelim consumes the next token from dec and checks that it is the given delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return codeErrorf(CodeSyntax, "expected '%v' in JSON array of messages, found %v", delim, tok)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidatorMessage3() proto.Message {
+	return &validatortest.ValidatorMessage3{}
+}
+
+func TestDecodeArray_YieldsEachMessage(t *testing.T) {
+	input := `[{"someString": "a"}, {"someString": "b"}, {"someString": "c"}]`
+	got := []string{}
+	err := nicejsonpb.DecodeArray(json.NewDecoder(strings.NewReader(input)), newValidatorMessage3, func(msg proto.Message) error {
+		got = append(got, msg.(*validatortest.ValidatorMessage3).SomeString)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, got)
+}
+
+func TestDecodeArray_ReportsElementIndex(t *testing.T) {
+	input := `[{"someString": "a"}, {"someEmbedded": {"identifier": 3.1}}]`
+	count := 0
+	err := nicejsonpb.DecodeArray(json.NewDecoder(strings.NewReader(input)), newValidatorMessage3, func(msg proto.Message) error {
+		count++
+		return nil
+	})
+	require.EqualError(t, err, "unparsable field [1].SomeEmbedded.Identifier: json: cannot unmarshal number into Go value of type string")
+	require.Equal(t, 1, count, "elements before the failing one must have been handed out")
+}
+
+func TestDecodeArray_RejectsNonArray(t *testing.T) {
+	input := `{"someString": "a"}`
+	err := nicejsonpb.DecodeArray(json.NewDecoder(strings.NewReader(input)), newValidatorMessage3, func(msg proto.Message) error {
+		return nil
+	})
+	require.EqualError(t, err, "expected '[' in JSON array of messages, found {")
+}
+
+func TestStreamDecoder_DecodesEachLine(t *testing.T) {
+	input := "{\"someString\": \"a\"}\n\n{\"someString\": \"b\"}\r\n{\"someString\": \"c\"}"
+	dec := nicejsonpb.NewStreamDecoder(strings.NewReader(input))
+	got := []string{}
+	for {
+		stuff := &validatortest.ValidatorMessage3{}
+		err := dec.Decode(stuff)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, stuff.SomeString)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, got)
+	require.Equal(t, 4, dec.Line())
+}
+
+func TestStreamDecoder_ReportsLineNumber(t *testing.T) {
+	input := "{\"someString\": \"a\"}\n{\"someEmbedded\": {\"identifier\": 3.1}}\n"
+	dec := nicejsonpb.NewStreamDecoder(strings.NewReader(input))
+	require.NoError(t, dec.Decode(&validatortest.ValidatorMessage3{}))
+	err := dec.Decode(&validatortest.ValidatorMessage3{})
+	require.EqualError(t, err, "line 2: unparsable field SomeEmbedded.Identifier: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshalNext_ReportsDocumentIndex(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{}
+	dec := json.NewDecoder(strings.NewReader(`{"someString": "a"} {"someString": "b"} {"someInt32": "x"} {"someString": "d"}`))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, u.UnmarshalNext(dec, &validatortest.Message3{}))
+	}
+	err := u.UnmarshalNext(dec, &validatortest.Message3{})
+	require.EqualError(t, err, "document 3: unparsable field SomeInt32: json: cannot unmarshal string into Go value of type int32")
+	var docErr *nicejsonpb.DocumentError
+	require.ErrorAs(t, err, &docErr)
+	require.Equal(t, 3, docErr.Index)
+	require.Equal(t, 3, u.DocumentCount())
+
+	require.NoError(t, u.UnmarshalNext(dec, &validatortest.Message3{}))
+	require.Equal(t, io.EOF, u.UnmarshalNext(dec, &validatortest.Message3{}))
+	require.Equal(t, 4, u.DocumentCount())
+	require.Equal(t, 0, u.Clone().DocumentCount())
+}
+
+// TestUnmarshalNext_ConcurrentCopies is meant for go test -race: UnmarshalNext counts documents in
+// an Unmarshaler that profiles and Clone copy at the same time.
+func TestUnmarshalNext_ConcurrentCopies(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{Profile: nicejsonpb.ProfileLenient}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		dec := json.NewDecoder(strings.NewReader(strings.Repeat(`{"someString": "a"} `, 100)))
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, u.UnmarshalNext(dec, &validatortest.Message3{}))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, u.UnmarshalBytes([]byte(`{"someInt32": "1"}`), &validatortest.Message3{}))
+			u.Clone()
+		}
+	}()
+	wg.Wait()
+	require.Equal(t, 100, u.DocumentCount())
+}
@@ -0,0 +1,86 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parseString decodes raw as a string, checking it for invalid UTF-8 if RejectInvalidUTF8 or
+// RepairInvalidUTF8 is set, and for control characters if RejectControlChars is. encoding/json
+// replaces invalid UTF-8 with U+FFFD, which without these options is kept silently.
+func (d *decodeState) parseString(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", err
+	}
+	if d.RejectControlChars {
+		if err := checkControlChars(s); err != nil {
+			return "", err
+		}
+	}
+	if !d.RejectInvalidUTF8 && !d.RepairInvalidUTF8 {
+		return s, nil
+	}
+	if offset := invalidUTF8Offset(raw); offset >= 0 {
+		if d.RepairInvalidUTF8 {
+			d.warn(WarnLossyConversion, "", "invalid UTF-8 at byte %d replaced with U+FFFD", offset)
+			return s, nil
+		}
+		return "", codeErrorf(CodeBadString, "invalid UTF-8 at byte %d of the string", offset)
+	}
+	return s, nil
+}
+
+// checkControlChars fails for the first C0 control character in s other than tab, line feed and
+// carriage return.
+func checkControlChars(s string) error {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 && c != '\t' && c != '\n' && c != '\r' {
+			return codeErrorf(CodeBadString, "control character %U at byte %d of the string", rune(c), i)
+		}
+	}
+	return nil
+}
+
+// invalidUTF8Offset returns the offset in the JSON string literal raw of the first byte that isn't
+// valid UTF-8, or of the first escape of a lone UTF-16 surrogate, or -1 if there is none.
+func invalidUTF8Offset(raw []byte) int {
+	if len(raw) < 2 || raw[0] != '"' {
+		return -1
+	}
+	end := len(raw) - 1
+	for i := 1; i < end; {
+		switch c := raw[i]; {
+		case c == '\\' && raw[i+1] == 'u':
+			r := unicodeEscape(raw[i:])
+			if !utf16.IsSurrogate(r) {
+				i += 6
+				continue
+			}
+			if i+12 <= end && raw[i+6] == '\\' && raw[i+7] == 'u' && utf16.DecodeRune(r, unicodeEscape(raw[i+6:])) != utf8.RuneError {
+				i += 12
+				continue
+			}
+			return i
+		case c == '\\':
+			i += 2
+		case c < utf8.RuneSelf:
+			i++
+		default:
+			r, size := utf8.DecodeRune(raw[i:end])
+			if r == utf8.RuneError && size == 1 {
+				return i
+			}
+			i += size
+		}
+	}
+	return -1
+}
+
+// unicodeEscape returns the rune of the \uXXXX escape b starts with, which must be well-formed.
+func unicodeEscape(b []byte) rune {
+	n, _ := strconv.ParseUint(string(b[2:6]), 16, 32)
+	return rune(n)
+}
@@ -0,0 +1,63 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_InvalidUTF8(t *testing.T) {
+	for input, want := range map[string]string{
+		"{\"someString\": \"ab\xffc\"}":                                       "unparsable field SomeString: invalid UTF-8 at byte 3 of the string",
+		`{"someString": "ab\ud800c"}`:                                         "unparsable field SomeString: invalid UTF-8 at byte 3 of the string",
+		"{\"someStringRep\": [\"ok\", \"\xc3\"]}":                             "unparsable field SomeStringRep.[1]: invalid UTF-8 at byte 1 of the string",
+		"{\"someStringToEmbedded\": {\"a\": {\"identifier\": \"\xe2\x82\"}}}": "unparsable field SomeStringToEmbedded.['a']value.Identifier: invalid UTF-8 at byte 1 of the string",
+	} {
+		u := nicejsonpb.New(nicejsonpb.WithRejectInvalidUTF8())
+		err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+		require.EqualError(t, err, want, input)
+		require.Equal(t, nicejsonpb.CodeBadString, nicejsonpb.ErrorCode(err))
+		_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+		require.EqualError(t, err, want, input)
+
+		require.NoError(t, nicejsonpb.UnmarshalString(input, &validatortest.Message3{}), input)
+	}
+
+	u := nicejsonpb.New(nicejsonpb.WithRejectInvalidUTF8())
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someString": "😀 \\ud800 é ok"}`), m))
+	require.Equal(t, "😀 \\ud800 é ok", m.SomeString)
+}
+
+func TestUnmarshal_RepairInvalidUTF8(t *testing.T) {
+	var warnings []nicejsonpb.Warning
+	u := nicejsonpb.New(nicejsonpb.WithProfile(nicejsonpb.ProfileStrictConformance), nicejsonpb.WithRepairInvalidUTF8(), nicejsonpb.WithOnWarning(func(w nicejsonpb.Warning) {
+		warnings = append(warnings, w)
+	}))
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader("{\"someString\": \"ab\xffc\"}"), m))
+	require.Equal(t, "ab�c", m.SomeString)
+	require.Equal(t, []nicejsonpb.Warning{{Kind: nicejsonpb.WarnLossyConversion, Path: "SomeString", Message: "invalid UTF-8 at byte 3 replaced with U+FFFD"}}, warnings)
+}
+
+func TestUnmarshal_RejectControlChars(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithRejectControlChars())
+	for input, want := range map[string]string{
+		`{"someString": "ab\u0000"}`:                                    "unparsable field SomeString: control character U+0000 at byte 2 of the string",
+		`{"someStringRep": ["a", "\u001b[31mred"]}`:                     "unparsable field SomeStringRep.[1]: control character U+001B at byte 0 of the string",
+		`{"someStringToInt64": {"\u0000": 1}, "someString": "é\u0007"}`: "unparsable field SomeString: control character U+0007 at byte 2 of the string",
+	} {
+		err := u.Unmarshal(strings.NewReader(input), &validatortest.Message3{})
+		require.EqualError(t, err, want, input)
+		require.Equal(t, nicejsonpb.CodeBadString, nicejsonpb.ErrorCode(err))
+		_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+		require.EqualError(t, err, want, input)
+	}
+
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someString": "line\r\n\tindented"}`), m))
+	require.Equal(t, "line\r\n\tindented", m.SomeString)
+}
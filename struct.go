@@ -0,0 +1,105 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// unmarshalStruct decodes the JSON forms of google.protobuf.Struct, Value and ListValue, any JSON
+// object, value and array, into m. With CheckStructAnys the objects in them with an "@type" are
+// first checked the way the Anys they stand for would be decoded.
+func (d *decodeState) unmarshalStruct(inputValue json.RawMessage, m protoreflect.Message) error {
+	if d.CheckStructAnys {
+		if err := d.checkStructAnys(inputValue); err != nil {
+			return err
+		}
+	}
+	var pb protov2.Message
+	var err error
+	switch name := m.Descriptor().FullName(); name.Name() {
+	case "Struct":
+		var obj map[string]interface{}
+		if err := json.Unmarshal(inputValue, &obj); err != nil {
+			return correctDynamicJsonType(err, "message "+string(name))
+		}
+		pb, err = structpb.NewStruct(obj)
+	case "ListValue":
+		var list []interface{}
+		if err := json.Unmarshal(inputValue, &list); err != nil {
+			return correctDynamicJsonType(err, "message "+string(name))
+		}
+		pb, err = structpb.NewList(list)
+	default:
+		var v interface{}
+		if err := json.Unmarshal(inputValue, &v); err != nil {
+			return err
+		}
+		pb, err = structpb.NewValue(v)
+	}
+	if err != nil {
+		return codeErrorf(CodeBadString, "%v", err)
+	}
+	// m may be a dynamic message, so the value is copied over in the wire format.
+	b, err := protov2.Marshal(pb)
+	if err != nil {
+		return err
+	}
+	return protov2.UnmarshalOptions{Merge: true}.Unmarshal(b, m.Interface())
+}
+
+// checkStructAnys checks the objects with an "@type" member found in inputValue, the JSON form of a
+// Struct, Value or ListValue, the way the Anys they stand for would be decoded.
+func (d *decodeState) checkStructAnys(inputValue json.RawMessage) error {
+	if len(inputValue) == 0 {
+		return nil
+	}
+	switch inputValue[0] {
+	case '{':
+		fields := map[string]json.RawMessage{}
+		if err := d.tokens().splitObject(inputValue, fields); err != nil {
+			return nil // Reported once the value is decoded.
+		}
+		if _, ok := fields[anyTypeField]; ok {
+			typeURL, err := d.anyTypeURL(fields)
+			if err != nil {
+				return err
+			}
+			_, err = d.unmarshalAnyPayload(typeURL, fields)
+			return err
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			raw := fields[key]
+			err := d.withinElement(fmt.Sprintf("['%s']value", key), key, raw, func() error {
+				return d.checkStructAnys(raw)
+			})
+			if err := d.collect(err); err != nil {
+				return err
+			}
+		}
+	case '[':
+		elems, err := d.splitRepeated(inputValue)
+		if err != nil {
+			return nil // Reported once the value is decoded.
+		}
+		for i, raw := range elems {
+			err := d.withinElement(fmt.Sprintf("[%d]", i), strconv.Itoa(i), raw, func() error {
+				return d.checkStructAnys(raw)
+			})
+			if err := d.collect(err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUnmarshal_Struct(t *testing.T) {
+	m := &validatortest.AnyHolder{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"details": {"a": [1, "b", null, {"c": true}]}, "extra": null}`, m))
+	require.Equal(t, []interface{}{1.0, "b", nil, map[string]interface{}{"c": true}}, m.Details.AsMap()["a"])
+	require.IsType(t, &structpb.Value_NullValue{}, m.Extra.Kind)
+
+	err := nicejsonpb.UnmarshalString(`{"details": [1]}`, m)
+	require.Equal(t, nicejsonpb.CodeTypeMismatch, nicejsonpb.ErrorCode(err))
+	require.Equal(t, "/details", nicejsonpb.ErrorPointer(err))
+
+	desc := validatortest.File_nicejsonpb_any_proto.Messages().ByName("AnyHolder")
+	dyn, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"extra": {"a": "b"}}`), desc)
+	require.NoError(t, err)
+	extra := dyn.Get(desc.Fields().ByName("extra")).Message()
+	require.True(t, extra.Has(extra.Descriptor().Fields().ByName("struct_value")))
+}
+
+func TestUnmarshal_CheckStructAnys(t *testing.T) {
+	doc := `{"details": {"ok": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": 1}, "events": [{"@type": "type.googleapis.com/validatortest.Nope"}]}}`
+	m := &validatortest.AnyHolder{}
+	require.NoError(t, nicejsonpb.UnmarshalString(doc, m))
+
+	u := nicejsonpb.New(nicejsonpb.WithCheckStructAnys())
+	err := u.UnmarshalBytes([]byte(doc), m)
+	require.Equal(t, nicejsonpb.CodeUnknownType, nicejsonpb.ErrorCode(err))
+	require.Equal(t, "/details/events/0/@type", nicejsonpb.ErrorPointer(err))
+	require.Equal(t, "details.events.0.@type", nicejsonpb.NewProblem(err).InvalidParams[0].Field)
+
+	err = u.UnmarshalBytes([]byte(`{"extra": {"@type": "type.googleapis.com/validatortest.Message3", "someInt32": "x"}}`), m)
+	require.Equal(t, "/extra/someInt32", nicejsonpb.ErrorPointer(err))
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_any.proto
+
+package validatortest
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AnyHolder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Single        *anypb.Any             `protobuf:"bytes,1,opt,name=single,proto3" json:"single,omitempty"`
+	Many          []*anypb.Any           `protobuf:"bytes,2,rep,name=many,proto3" json:"many,omitempty"`
+	Attributes    map[string]*anypb.Any  `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Details       *structpb.Struct       `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	Extra         *structpb.Value        `protobuf:"bytes,5,opt,name=extra,proto3" json:"extra,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnyHolder) Reset() {
+	*x = AnyHolder{}
+	mi := &file_nicejsonpb_any_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnyHolder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnyHolder) ProtoMessage() {}
+
+func (x *AnyHolder) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_any_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnyHolder.ProtoReflect.Descriptor instead.
+func (*AnyHolder) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_any_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnyHolder) GetSingle() *anypb.Any {
+	if x != nil {
+		return x.Single
+	}
+	return nil
+}
+
+func (x *AnyHolder) GetMany() []*anypb.Any {
+	if x != nil {
+		return x.Many
+	}
+	return nil
+}
+
+func (x *AnyHolder) GetAttributes() map[string]*anypb.Any {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *AnyHolder) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *AnyHolder) GetExtra() *structpb.Value {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+var File_nicejsonpb_any_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_any_proto_rawDesc = "" +
+	"\n" +
+	"\x14nicejsonpb_any.proto\x12\rvalidatortest\x1a\x19google/protobuf/any.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xe3\x02\n" +
+	"\tAnyHolder\x12,\n" +
+	"\x06single\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x06single\x12(\n" +
+	"\x04many\x18\x02 \x03(\v2\x14.google.protobuf.AnyR\x04many\x12H\n" +
+	"\n" +
+	"attributes\x18\x03 \x03(\v2(.validatortest.AnyHolder.AttributesEntryR\n" +
+	"attributes\x121\n" +
+	"\adetails\x18\x04 \x01(\v2\x17.google.protobuf.StructR\adetails\x12,\n" +
+	"\x05extra\x18\x05 \x01(\v2\x16.google.protobuf.ValueR\x05extra\x1aS\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01B5Z3github.com/mwitkow/go-nicejsonpb/test;validatortestb\x06proto3"
+
+var (
+	file_nicejsonpb_any_proto_rawDescOnce sync.Once
+	file_nicejsonpb_any_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_any_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_any_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_any_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_any_proto_rawDesc), len(file_nicejsonpb_any_proto_rawDesc)))
+	})
+	return file_nicejsonpb_any_proto_rawDescData
+}
+
+var file_nicejsonpb_any_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_nicejsonpb_any_proto_goTypes = []any{
+	(*AnyHolder)(nil),       // 0: validatortest.AnyHolder
+	nil,                     // 1: validatortest.AnyHolder.AttributesEntry
+	(*anypb.Any)(nil),       // 2: google.protobuf.Any
+	(*structpb.Struct)(nil), // 3: google.protobuf.Struct
+	(*structpb.Value)(nil),  // 4: google.protobuf.Value
+}
+var file_nicejsonpb_any_proto_depIdxs = []int32{
+	2, // 0: validatortest.AnyHolder.single:type_name -> google.protobuf.Any
+	2, // 1: validatortest.AnyHolder.many:type_name -> google.protobuf.Any
+	1, // 2: validatortest.AnyHolder.attributes:type_name -> validatortest.AnyHolder.AttributesEntry
+	3, // 3: validatortest.AnyHolder.details:type_name -> google.protobuf.Struct
+	4, // 4: validatortest.AnyHolder.extra:type_name -> google.protobuf.Value
+	2, // 5: validatortest.AnyHolder.AttributesEntry.value:type_name -> google.protobuf.Any
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_any_proto_init() }
+func file_nicejsonpb_any_proto_init() {
+	if File_nicejsonpb_any_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_any_proto_rawDesc), len(file_nicejsonpb_any_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_any_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_any_proto_depIdxs,
+		MessageInfos:      file_nicejsonpb_any_proto_msgTypes,
+	}.Build()
+	File_nicejsonpb_any_proto = out.File
+	file_nicejsonpb_any_proto_goTypes = nil
+	file_nicejsonpb_any_proto_depIdxs = nil
+}
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_behavior.proto
+
+package validatortest
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Item          *CreateRequest_Item    `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	Items         []*CreateRequest_Item  `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Comment       string                 `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	Id            string                 `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
+	Region        string                 `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_nicejsonpb_behavior_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_behavior_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_behavior_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetItem() *CreateRequest_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *CreateRequest) GetItems() []*CreateRequest_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type CreateRequest_Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest_Item) Reset() {
+	*x = CreateRequest_Item{}
+	mi := &file_nicejsonpb_behavior_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest_Item) ProtoMessage() {}
+
+func (x *CreateRequest_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_behavior_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest_Item.ProtoReflect.Descriptor instead.
+func (*CreateRequest_Item) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_behavior_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *CreateRequest_Item) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CreateRequest_Item) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+var File_nicejsonpb_behavior_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_behavior_proto_rawDesc = "" +
+	"\n" +
+	"\x19nicejsonpb_behavior.proto\x12\rvalidatortest\x1a\x1fgoogle/api/field_behavior.proto\"\xa4\x02\n" +
+	"\rCreateRequest\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tB\x03\xe0A\x02R\x04name\x12:\n" +
+	"\x04item\x18\x02 \x01(\v2!.validatortest.CreateRequest.ItemB\x03\xe0A\x02R\x04item\x127\n" +
+	"\x05items\x18\x03 \x03(\v2!.validatortest.CreateRequest.ItemR\x05items\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\x12\x13\n" +
+	"\x02id\x18\x05 \x01(\tB\x03\xe0A\x03R\x02id\x12\x1b\n" +
+	"\x06region\x18\x06 \x01(\tB\x03\xe0A\x05R\x06region\x1a9\n" +
+	"\x04Item\x12\x15\n" +
+	"\x03sku\x18\x01 \x01(\tB\x03\xe0A\x02R\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantityB5Z3github.com/mwitkow/go-nicejsonpb/test;validatortestb\x06proto3"
+
+var (
+	file_nicejsonpb_behavior_proto_rawDescOnce sync.Once
+	file_nicejsonpb_behavior_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_behavior_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_behavior_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_behavior_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_behavior_proto_rawDesc), len(file_nicejsonpb_behavior_proto_rawDesc)))
+	})
+	return file_nicejsonpb_behavior_proto_rawDescData
+}
+
+var file_nicejsonpb_behavior_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_nicejsonpb_behavior_proto_goTypes = []any{
+	(*CreateRequest)(nil),      // 0: validatortest.CreateRequest
+	(*CreateRequest_Item)(nil), // 1: validatortest.CreateRequest.Item
+}
+var file_nicejsonpb_behavior_proto_depIdxs = []int32{
+	1, // 0: validatortest.CreateRequest.item:type_name -> validatortest.CreateRequest.Item
+	1, // 1: validatortest.CreateRequest.items:type_name -> validatortest.CreateRequest.Item
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_behavior_proto_init() }
+func file_nicejsonpb_behavior_proto_init() {
+	if File_nicejsonpb_behavior_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_behavior_proto_rawDesc), len(file_nicejsonpb_behavior_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_behavior_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_behavior_proto_depIdxs,
+		MessageInfos:      file_nicejsonpb_behavior_proto_msgTypes,
+	}.Build()
+	File_nicejsonpb_behavior_proto = out.File
+	file_nicejsonpb_behavior_proto_goTypes = nil
+	file_nicejsonpb_behavior_proto_depIdxs = nil
+}
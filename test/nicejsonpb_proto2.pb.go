@@ -0,0 +1,588 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_proto2.proto
+
+package validatortest
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Message2 struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SomeString        *string                `protobuf:"bytes,1,opt,name=some_string,json=someString" json:"some_string,omitempty"`
+	SomeInt           *int64                 `protobuf:"varint,2,opt,name=some_int,json=someInt" json:"some_int,omitempty"`
+	SomeEmbedded      *Message2_Embedded     `protobuf:"bytes,3,opt,name=some_embedded,json=someEmbedded" json:"some_embedded,omitempty"`
+	SomeDefaultString *string                `protobuf:"bytes,4,opt,name=some_default_string,json=someDefaultString,def=hello" json:"some_default_string,omitempty"`
+	SomeDefaultInt    *int32                 `protobuf:"varint,5,opt,name=some_default_int,json=someDefaultInt,def=42" json:"some_default_int,omitempty"`
+	extensionFields   protoimpl.ExtensionFields
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+// Default values for Message2 fields.
+const (
+	Default_Message2_SomeDefaultString = string("hello")
+	Default_Message2_SomeDefaultInt    = int32(42)
+)
+
+func (x *Message2) Reset() {
+	*x = Message2{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message2) ProtoMessage() {}
+
+func (x *Message2) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message2.ProtoReflect.Descriptor instead.
+func (*Message2) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message2) GetSomeString() string {
+	if x != nil && x.SomeString != nil {
+		return *x.SomeString
+	}
+	return ""
+}
+
+func (x *Message2) GetSomeInt() int64 {
+	if x != nil && x.SomeInt != nil {
+		return *x.SomeInt
+	}
+	return 0
+}
+
+func (x *Message2) GetSomeEmbedded() *Message2_Embedded {
+	if x != nil {
+		return x.SomeEmbedded
+	}
+	return nil
+}
+
+func (x *Message2) GetSomeDefaultString() string {
+	if x != nil && x.SomeDefaultString != nil {
+		return *x.SomeDefaultString
+	}
+	return Default_Message2_SomeDefaultString
+}
+
+func (x *Message2) GetSomeDefaultInt() int32 {
+	if x != nil && x.SomeDefaultInt != nil {
+		return *x.SomeDefaultInt
+	}
+	return Default_Message2_SomeDefaultInt
+}
+
+type Required2 struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Name          *string                     `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Inner         *Required2_Inner            `protobuf:"bytes,2,opt,name=inner" json:"inner,omitempty"`
+	Items         []*Required2_Inner          `protobuf:"bytes,3,rep,name=items" json:"items,omitempty"`
+	ItemsByName   map[string]*Required2_Inner `protobuf:"bytes,4,rep,name=items_by_name,json=itemsByName" json:"items_by_name,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Required2) Reset() {
+	*x = Required2{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Required2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Required2) ProtoMessage() {}
+
+func (x *Required2) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Required2.ProtoReflect.Descriptor instead.
+func (*Required2) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Required2) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Required2) GetInner() *Required2_Inner {
+	if x != nil {
+		return x.Inner
+	}
+	return nil
+}
+
+func (x *Required2) GetItems() []*Required2_Inner {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Required2) GetItemsByName() map[string]*Required2_Inner {
+	if x != nil {
+		return x.ItemsByName
+	}
+	return nil
+}
+
+type Grouped2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Somegroup     *Grouped2_SomeGroup    `protobuf:"group,1,opt,name=SomeGroup,json=somegroup" json:"somegroup,omitempty"`
+	Item          []*Grouped2_Item       `protobuf:"group,4,rep,name=Item,json=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Grouped2) Reset() {
+	*x = Grouped2{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Grouped2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Grouped2) ProtoMessage() {}
+
+func (x *Grouped2) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Grouped2.ProtoReflect.Descriptor instead.
+func (*Grouped2) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Grouped2) GetSomegroup() *Grouped2_SomeGroup {
+	if x != nil {
+		return x.Somegroup
+	}
+	return nil
+}
+
+func (x *Grouped2) GetItem() []*Grouped2_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type Message2_Embedded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    *string                `protobuf:"bytes,1,opt,name=identifier" json:"identifier,omitempty"`
+	SomeValue     *int32                 `protobuf:"varint,2,opt,name=some_value,json=someValue" json:"some_value,omitempty"`
+	Enabled       *bool                  `protobuf:"varint,3,opt,name=enabled,def=1" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for Message2_Embedded fields.
+const (
+	Default_Message2_Embedded_Enabled = bool(true)
+)
+
+func (x *Message2_Embedded) Reset() {
+	*x = Message2_Embedded{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message2_Embedded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message2_Embedded) ProtoMessage() {}
+
+func (x *Message2_Embedded) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message2_Embedded.ProtoReflect.Descriptor instead.
+func (*Message2_Embedded) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Message2_Embedded) GetIdentifier() string {
+	if x != nil && x.Identifier != nil {
+		return *x.Identifier
+	}
+	return ""
+}
+
+func (x *Message2_Embedded) GetSomeValue() int32 {
+	if x != nil && x.SomeValue != nil {
+		return *x.SomeValue
+	}
+	return 0
+}
+
+func (x *Message2_Embedded) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return Default_Message2_Embedded_Enabled
+}
+
+type Required2_Inner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	Label         *string                `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Required2_Inner) Reset() {
+	*x = Required2_Inner{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Required2_Inner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Required2_Inner) ProtoMessage() {}
+
+func (x *Required2_Inner) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Required2_Inner.ProtoReflect.Descriptor instead.
+func (*Required2_Inner) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *Required2_Inner) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *Required2_Inner) GetLabel() string {
+	if x != nil && x.Label != nil {
+		return *x.Label
+	}
+	return ""
+}
+
+type Grouped2_SomeGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         *string                `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+	SomeValue     *int32                 `protobuf:"varint,3,opt,name=some_value,json=someValue" json:"some_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Grouped2_SomeGroup) Reset() {
+	*x = Grouped2_SomeGroup{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Grouped2_SomeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Grouped2_SomeGroup) ProtoMessage() {}
+
+func (x *Grouped2_SomeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Grouped2_SomeGroup.ProtoReflect.Descriptor instead.
+func (*Grouped2_SomeGroup) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *Grouped2_SomeGroup) GetLabel() string {
+	if x != nil && x.Label != nil {
+		return *x.Label
+	}
+	return ""
+}
+
+func (x *Grouped2_SomeGroup) GetSomeValue() int32 {
+	if x != nil && x.SomeValue != nil {
+		return *x.SomeValue
+	}
+	return 0
+}
+
+type Grouped2_Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,5,req,name=id" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Grouped2_Item) Reset() {
+	*x = Grouped2_Item{}
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Grouped2_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Grouped2_Item) ProtoMessage() {}
+
+func (x *Grouped2_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto2_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Grouped2_Item.ProtoReflect.Descriptor instead.
+func (*Grouped2_Item) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto2_proto_rawDescGZIP(), []int{2, 1}
+}
+
+func (x *Grouped2_Item) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+var file_nicejsonpb_proto2_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*Message2)(nil),
+		ExtensionType: (*int64)(nil),
+		Field:         100,
+		Name:          "validatortest.some_ext_int",
+		Tag:           "varint,100,opt,name=some_ext_int",
+		Filename:      "nicejsonpb_proto2.proto",
+	},
+	{
+		ExtendedType:  (*Message2)(nil),
+		ExtensionType: (*Message2_Embedded)(nil),
+		Field:         101,
+		Name:          "validatortest.some_ext_embedded",
+		Tag:           "bytes,101,opt,name=some_ext_embedded",
+		Filename:      "nicejsonpb_proto2.proto",
+	},
+	{
+		ExtendedType:  (*Message2)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         102,
+		Name:          "validatortest.some_ext_strings",
+		Tag:           "bytes,102,rep,name=some_ext_strings",
+		Filename:      "nicejsonpb_proto2.proto",
+	},
+}
+
+// Extension fields to Message2.
+var (
+	// optional int64 some_ext_int = 100;
+	E_SomeExtInt = &file_nicejsonpb_proto2_proto_extTypes[0]
+	// optional validatortest.Message2.Embedded some_ext_embedded = 101;
+	E_SomeExtEmbedded = &file_nicejsonpb_proto2_proto_extTypes[1]
+	// repeated string some_ext_strings = 102;
+	E_SomeExtStrings = &file_nicejsonpb_proto2_proto_extTypes[2]
+)
+
+var File_nicejsonpb_proto2_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_proto2_proto_rawDesc = "" +
+	"\n" +
+	"\x17nicejsonpb_proto2.proto\x12\rvalidatortest\"\xe4\x02\n" +
+	"\bMessage2\x12\x1f\n" +
+	"\vsome_string\x18\x01 \x01(\tR\n" +
+	"someString\x12\x19\n" +
+	"\bsome_int\x18\x02 \x01(\x03R\asomeInt\x12E\n" +
+	"\rsome_embedded\x18\x03 \x01(\v2 .validatortest.Message2.EmbeddedR\fsomeEmbedded\x125\n" +
+	"\x13some_default_string\x18\x04 \x01(\t:\x05helloR\x11someDefaultString\x12,\n" +
+	"\x10some_default_int\x18\x05 \x01(\x05:\x0242R\x0esomeDefaultInt\x1ai\n" +
+	"\bEmbedded\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x1d\n" +
+	"\n" +
+	"some_value\x18\x02 \x01(\x05R\tsomeValue\x12\x1e\n" +
+	"\aenabled\x18\x03 \x01(\b:\x04trueR\aenabled*\x05\bd\x10\xc8\x01\"\xe9\x02\n" +
+	"\tRequired2\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x124\n" +
+	"\x05inner\x18\x02 \x01(\v2\x1e.validatortest.Required2.InnerR\x05inner\x124\n" +
+	"\x05items\x18\x03 \x03(\v2\x1e.validatortest.Required2.InnerR\x05items\x12M\n" +
+	"\ritems_by_name\x18\x04 \x03(\v2).validatortest.Required2.ItemsByNameEntryR\vitemsByName\x1a^\n" +
+	"\x10ItemsByNameEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x124\n" +
+	"\x05value\x18\x02 \x01(\v2\x1e.validatortest.Required2.InnerR\x05value:\x028\x01\x1a-\n" +
+	"\x05Inner\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"\xd7\x01\n" +
+	"\bGrouped2\x12?\n" +
+	"\tsomegroup\x18\x01 \x01(\n" +
+	"2!.validatortest.Grouped2.SomeGroupR\tsomegroup\x120\n" +
+	"\x04item\x18\x04 \x03(\n" +
+	"2\x1c.validatortest.Grouped2.ItemR\x04item\x1a@\n" +
+	"\tSomeGroup\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x1d\n" +
+	"\n" +
+	"some_value\x18\x03 \x01(\x05R\tsomeValue\x1a\x16\n" +
+	"\x04Item\x12\x0e\n" +
+	"\x02id\x18\x05 \x02(\x05R\x02id:9\n" +
+	"\fsome_ext_int\x12\x17.validatortest.Message2\x18d \x01(\x03R\n" +
+	"someExtInt:e\n" +
+	"\x11some_ext_embedded\x12\x17.validatortest.Message2\x18e \x01(\v2 .validatortest.Message2.EmbeddedR\x0fsomeExtEmbedded:A\n" +
+	"\x10some_ext_strings\x12\x17.validatortest.Message2\x18f \x03(\tR\x0esomeExtStringsB5Z3github.com/mwitkow/go-nicejsonpb/test;validatortest"
+
+var (
+	file_nicejsonpb_proto2_proto_rawDescOnce sync.Once
+	file_nicejsonpb_proto2_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_proto2_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_proto2_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_proto2_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_proto2_proto_rawDesc), len(file_nicejsonpb_proto2_proto_rawDesc)))
+	})
+	return file_nicejsonpb_proto2_proto_rawDescData
+}
+
+var file_nicejsonpb_proto2_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_nicejsonpb_proto2_proto_goTypes = []any{
+	(*Message2)(nil),           // 0: validatortest.Message2
+	(*Required2)(nil),          // 1: validatortest.Required2
+	(*Grouped2)(nil),           // 2: validatortest.Grouped2
+	(*Message2_Embedded)(nil),  // 3: validatortest.Message2.Embedded
+	nil,                        // 4: validatortest.Required2.ItemsByNameEntry
+	(*Required2_Inner)(nil),    // 5: validatortest.Required2.Inner
+	(*Grouped2_SomeGroup)(nil), // 6: validatortest.Grouped2.SomeGroup
+	(*Grouped2_Item)(nil),      // 7: validatortest.Grouped2.Item
+}
+var file_nicejsonpb_proto2_proto_depIdxs = []int32{
+	3,  // 0: validatortest.Message2.some_embedded:type_name -> validatortest.Message2.Embedded
+	5,  // 1: validatortest.Required2.inner:type_name -> validatortest.Required2.Inner
+	5,  // 2: validatortest.Required2.items:type_name -> validatortest.Required2.Inner
+	4,  // 3: validatortest.Required2.items_by_name:type_name -> validatortest.Required2.ItemsByNameEntry
+	6,  // 4: validatortest.Grouped2.somegroup:type_name -> validatortest.Grouped2.SomeGroup
+	7,  // 5: validatortest.Grouped2.item:type_name -> validatortest.Grouped2.Item
+	5,  // 6: validatortest.Required2.ItemsByNameEntry.value:type_name -> validatortest.Required2.Inner
+	0,  // 7: validatortest.some_ext_int:extendee -> validatortest.Message2
+	0,  // 8: validatortest.some_ext_embedded:extendee -> validatortest.Message2
+	0,  // 9: validatortest.some_ext_strings:extendee -> validatortest.Message2
+	3,  // 10: validatortest.some_ext_embedded:type_name -> validatortest.Message2.Embedded
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	10, // [10:11] is the sub-list for extension type_name
+	7,  // [7:10] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_proto2_proto_init() }
+func file_nicejsonpb_proto2_proto_init() {
+	if File_nicejsonpb_proto2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_proto2_proto_rawDesc), len(file_nicejsonpb_proto2_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 3,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_proto2_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_proto2_proto_depIdxs,
+		MessageInfos:      file_nicejsonpb_proto2_proto_msgTypes,
+		ExtensionInfos:    file_nicejsonpb_proto2_proto_extTypes,
+	}.Build()
+	File_nicejsonpb_proto2_proto = out.File
+	file_nicejsonpb_proto2_proto_goTypes = nil
+	file_nicejsonpb_proto2_proto_depIdxs = nil
+}
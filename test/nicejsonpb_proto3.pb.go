@@ -0,0 +1,610 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_proto3.proto
+
+package validatortest
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNKNOWN  Status = 0
+	Status_STATUS_ACTIVE   Status = 1
+	Status_STATUS_DISABLED Status = 2
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNKNOWN",
+		1: "STATUS_ACTIVE",
+		2: "STATUS_DISABLED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNKNOWN":  0,
+		"STATUS_ACTIVE":   1,
+		"STATUS_DISABLED": 2,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_nicejsonpb_proto3_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_nicejsonpb_proto3_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto3_proto_rawDescGZIP(), []int{0}
+}
+
+type Message3 struct {
+	state                protoimpl.MessageState        `protogen:"open.v1"`
+	SomeString           string                        `protobuf:"bytes,1,opt,name=some_string,json=someString,proto3" json:"some_string,omitempty"`
+	SomeStringRep        []string                      `protobuf:"bytes,2,rep,name=some_string_rep,json=someStringRep,proto3" json:"some_string_rep,omitempty"`
+	SomeInt32            int32                         `protobuf:"varint,3,opt,name=some_int32,json=someInt32,proto3" json:"some_int32,omitempty"`
+	SomeInt64            int64                         `protobuf:"varint,4,opt,name=some_int64,json=someInt64,proto3" json:"some_int64,omitempty"`
+	SomeUint32           uint32                        `protobuf:"varint,5,opt,name=some_uint32,json=someUint32,proto3" json:"some_uint32,omitempty"`
+	SomeUint64           uint64                        `protobuf:"varint,6,opt,name=some_uint64,json=someUint64,proto3" json:"some_uint64,omitempty"`
+	SomeIntRep           []uint32                      `protobuf:"varint,7,rep,packed,name=some_int_rep,json=someIntRep,proto3" json:"some_int_rep,omitempty"`
+	SomeFloat            float32                       `protobuf:"fixed32,8,opt,name=some_float,json=someFloat,proto3" json:"some_float,omitempty"`
+	SomeDouble           float64                       `protobuf:"fixed64,9,opt,name=some_double,json=someDouble,proto3" json:"some_double,omitempty"`
+	SomeBool             bool                          `protobuf:"varint,10,opt,name=some_bool,json=someBool,proto3" json:"some_bool,omitempty"`
+	SomeBytes            []byte                        `protobuf:"bytes,11,opt,name=some_bytes,json=someBytes,proto3" json:"some_bytes,omitempty"`
+	SomeStatus           Status                        `protobuf:"varint,12,opt,name=some_status,json=someStatus,proto3,enum=validatortest.Status" json:"some_status,omitempty"`
+	SomeStatusRep        []Status                      `protobuf:"varint,13,rep,packed,name=some_status_rep,json=someStatusRep,proto3,enum=validatortest.Status" json:"some_status_rep,omitempty"`
+	SomeEmbedded         *Message3_Embedded            `protobuf:"bytes,14,opt,name=some_embedded,json=someEmbedded,proto3" json:"some_embedded,omitempty"`
+	SomeEmbeddedRep      []*Message3_Embedded          `protobuf:"bytes,15,rep,name=some_embedded_rep,json=someEmbeddedRep,proto3" json:"some_embedded_rep,omitempty"`
+	SomeStringToInt64    map[string]int64              `protobuf:"bytes,16,rep,name=some_string_to_int64,json=someStringToInt64,proto3" json:"some_string_to_int64,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	SomeInt32ToString    map[int32]string              `protobuf:"bytes,17,rep,name=some_int32_to_string,json=someInt32ToString,proto3" json:"some_int32_to_string,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SomeBoolToString     map[bool]string               `protobuf:"bytes,18,rep,name=some_bool_to_string,json=someBoolToString,proto3" json:"some_bool_to_string,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SomeStringToStatus   map[string]Status             `protobuf:"bytes,19,rep,name=some_string_to_status,json=someStringToStatus,proto3" json:"some_string_to_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=validatortest.Status"`
+	SomeStringToEmbedded map[string]*Message3_Embedded `protobuf:"bytes,20,rep,name=some_string_to_embedded,json=someStringToEmbedded,proto3" json:"some_string_to_embedded,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SomeTimestamp        *timestamppb.Timestamp        `protobuf:"bytes,21,opt,name=some_timestamp,json=someTimestamp,proto3" json:"some_timestamp,omitempty"`
+	SomeDuration         *durationpb.Duration          `protobuf:"bytes,22,opt,name=some_duration,json=someDuration,proto3" json:"some_duration,omitempty"`
+	SomeWrappedInt       *wrapperspb.Int64Value        `protobuf:"bytes,23,opt,name=some_wrapped_int,json=someWrappedInt,proto3" json:"some_wrapped_int,omitempty"`
+	SomeWrappedString    *wrapperspb.StringValue       `protobuf:"bytes,24,opt,name=some_wrapped_string,json=someWrappedString,proto3" json:"some_wrapped_string,omitempty"`
+	SomeOptionalString   *string                       `protobuf:"bytes,25,opt,name=some_optional_string,json=someOptionalString,proto3,oneof" json:"some_optional_string,omitempty"`
+	SomeOptionalInt32    *int32                        `protobuf:"varint,26,opt,name=some_optional_int32,json=someOptionalInt32,proto3,oneof" json:"some_optional_int32,omitempty"`
+	// Deprecated: Marked as deprecated in nicejsonpb_proto3.proto.
+	SomeDeprecated string `protobuf:"bytes,27,opt,name=some_deprecated,json=someDeprecated,proto3" json:"some_deprecated,omitempty"`
+	// Types that are valid to be assigned to Contact:
+	//
+	//	*Message3_Email
+	//	*Message3_Phone
+	//	*Message3_Address
+	Contact       isMessage3_Contact `protobuf_oneof:"contact"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message3) Reset() {
+	*x = Message3{}
+	mi := &file_nicejsonpb_proto3_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message3) ProtoMessage() {}
+
+func (x *Message3) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto3_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message3.ProtoReflect.Descriptor instead.
+func (*Message3) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto3_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message3) GetSomeString() string {
+	if x != nil {
+		return x.SomeString
+	}
+	return ""
+}
+
+func (x *Message3) GetSomeStringRep() []string {
+	if x != nil {
+		return x.SomeStringRep
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeInt32() int32 {
+	if x != nil {
+		return x.SomeInt32
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeInt64() int64 {
+	if x != nil {
+		return x.SomeInt64
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeUint32() uint32 {
+	if x != nil {
+		return x.SomeUint32
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeUint64() uint64 {
+	if x != nil {
+		return x.SomeUint64
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeIntRep() []uint32 {
+	if x != nil {
+		return x.SomeIntRep
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeFloat() float32 {
+	if x != nil {
+		return x.SomeFloat
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeDouble() float64 {
+	if x != nil {
+		return x.SomeDouble
+	}
+	return 0
+}
+
+func (x *Message3) GetSomeBool() bool {
+	if x != nil {
+		return x.SomeBool
+	}
+	return false
+}
+
+func (x *Message3) GetSomeBytes() []byte {
+	if x != nil {
+		return x.SomeBytes
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeStatus() Status {
+	if x != nil {
+		return x.SomeStatus
+	}
+	return Status_STATUS_UNKNOWN
+}
+
+func (x *Message3) GetSomeStatusRep() []Status {
+	if x != nil {
+		return x.SomeStatusRep
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeEmbedded() *Message3_Embedded {
+	if x != nil {
+		return x.SomeEmbedded
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeEmbeddedRep() []*Message3_Embedded {
+	if x != nil {
+		return x.SomeEmbeddedRep
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeStringToInt64() map[string]int64 {
+	if x != nil {
+		return x.SomeStringToInt64
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeInt32ToString() map[int32]string {
+	if x != nil {
+		return x.SomeInt32ToString
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeBoolToString() map[bool]string {
+	if x != nil {
+		return x.SomeBoolToString
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeStringToStatus() map[string]Status {
+	if x != nil {
+		return x.SomeStringToStatus
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeStringToEmbedded() map[string]*Message3_Embedded {
+	if x != nil {
+		return x.SomeStringToEmbedded
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SomeTimestamp
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeDuration() *durationpb.Duration {
+	if x != nil {
+		return x.SomeDuration
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeWrappedInt() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.SomeWrappedInt
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeWrappedString() *wrapperspb.StringValue {
+	if x != nil {
+		return x.SomeWrappedString
+	}
+	return nil
+}
+
+func (x *Message3) GetSomeOptionalString() string {
+	if x != nil && x.SomeOptionalString != nil {
+		return *x.SomeOptionalString
+	}
+	return ""
+}
+
+func (x *Message3) GetSomeOptionalInt32() int32 {
+	if x != nil && x.SomeOptionalInt32 != nil {
+		return *x.SomeOptionalInt32
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in nicejsonpb_proto3.proto.
+func (x *Message3) GetSomeDeprecated() string {
+	if x != nil {
+		return x.SomeDeprecated
+	}
+	return ""
+}
+
+func (x *Message3) GetContact() isMessage3_Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *Message3) GetEmail() string {
+	if x != nil {
+		if x, ok := x.Contact.(*Message3_Email); ok {
+			return x.Email
+		}
+	}
+	return ""
+}
+
+func (x *Message3) GetPhone() string {
+	if x != nil {
+		if x, ok := x.Contact.(*Message3_Phone); ok {
+			return x.Phone
+		}
+	}
+	return ""
+}
+
+func (x *Message3) GetAddress() *Message3_Embedded {
+	if x != nil {
+		if x, ok := x.Contact.(*Message3_Address); ok {
+			return x.Address
+		}
+	}
+	return nil
+}
+
+type isMessage3_Contact interface {
+	isMessage3_Contact()
+}
+
+type Message3_Email struct {
+	Email string `protobuf:"bytes,30,opt,name=email,proto3,oneof"`
+}
+
+type Message3_Phone struct {
+	Phone string `protobuf:"bytes,31,opt,name=phone,proto3,oneof"`
+}
+
+type Message3_Address struct {
+	Address *Message3_Embedded `protobuf:"bytes,32,opt,name=address,proto3,oneof"`
+}
+
+func (*Message3_Email) isMessage3_Contact() {}
+
+func (*Message3_Phone) isMessage3_Contact() {}
+
+func (*Message3_Address) isMessage3_Contact() {}
+
+type Message3_Embedded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	SomeValue     int64                  `protobuf:"varint,2,opt,name=some_value,json=someValue,proto3" json:"some_value,omitempty"`
+	Children      []*Message3_Embedded   `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message3_Embedded) Reset() {
+	*x = Message3_Embedded{}
+	mi := &file_nicejsonpb_proto3_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message3_Embedded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message3_Embedded) ProtoMessage() {}
+
+func (x *Message3_Embedded) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_proto3_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message3_Embedded.ProtoReflect.Descriptor instead.
+func (*Message3_Embedded) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_proto3_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *Message3_Embedded) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *Message3_Embedded) GetSomeValue() int64 {
+	if x != nil {
+		return x.SomeValue
+	}
+	return 0
+}
+
+func (x *Message3_Embedded) GetChildren() []*Message3_Embedded {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+var File_nicejsonpb_proto3_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_proto3_proto_rawDesc = "" +
+	"\n" +
+	"\x17nicejsonpb_proto3.proto\x12\rvalidatortest\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xea\x11\n" +
+	"\bMessage3\x12\x1f\n" +
+	"\vsome_string\x18\x01 \x01(\tR\n" +
+	"someString\x12&\n" +
+	"\x0fsome_string_rep\x18\x02 \x03(\tR\rsomeStringRep\x12\x1d\n" +
+	"\n" +
+	"some_int32\x18\x03 \x01(\x05R\tsomeInt32\x12\x1d\n" +
+	"\n" +
+	"some_int64\x18\x04 \x01(\x03R\tsomeInt64\x12\x1f\n" +
+	"\vsome_uint32\x18\x05 \x01(\rR\n" +
+	"someUint32\x12\x1f\n" +
+	"\vsome_uint64\x18\x06 \x01(\x04R\n" +
+	"someUint64\x12 \n" +
+	"\fsome_int_rep\x18\a \x03(\rR\n" +
+	"someIntRep\x12\x1d\n" +
+	"\n" +
+	"some_float\x18\b \x01(\x02R\tsomeFloat\x12\x1f\n" +
+	"\vsome_double\x18\t \x01(\x01R\n" +
+	"someDouble\x12\x1b\n" +
+	"\tsome_bool\x18\n" +
+	" \x01(\bR\bsomeBool\x12\x1d\n" +
+	"\n" +
+	"some_bytes\x18\v \x01(\fR\tsomeBytes\x126\n" +
+	"\vsome_status\x18\f \x01(\x0e2\x15.validatortest.StatusR\n" +
+	"someStatus\x12=\n" +
+	"\x0fsome_status_rep\x18\r \x03(\x0e2\x15.validatortest.StatusR\rsomeStatusRep\x12E\n" +
+	"\rsome_embedded\x18\x0e \x01(\v2 .validatortest.Message3.EmbeddedR\fsomeEmbedded\x12L\n" +
+	"\x11some_embedded_rep\x18\x0f \x03(\v2 .validatortest.Message3.EmbeddedR\x0fsomeEmbeddedRep\x12_\n" +
+	"\x14some_string_to_int64\x18\x10 \x03(\v2..validatortest.Message3.SomeStringToInt64EntryR\x11someStringToInt64\x12_\n" +
+	"\x14some_int32_to_string\x18\x11 \x03(\v2..validatortest.Message3.SomeInt32ToStringEntryR\x11someInt32ToString\x12\\\n" +
+	"\x13some_bool_to_string\x18\x12 \x03(\v2-.validatortest.Message3.SomeBoolToStringEntryR\x10someBoolToString\x12b\n" +
+	"\x15some_string_to_status\x18\x13 \x03(\v2/.validatortest.Message3.SomeStringToStatusEntryR\x12someStringToStatus\x12h\n" +
+	"\x17some_string_to_embedded\x18\x14 \x03(\v21.validatortest.Message3.SomeStringToEmbeddedEntryR\x14someStringToEmbedded\x12A\n" +
+	"\x0esome_timestamp\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\rsomeTimestamp\x12>\n" +
+	"\rsome_duration\x18\x16 \x01(\v2\x19.google.protobuf.DurationR\fsomeDuration\x12E\n" +
+	"\x10some_wrapped_int\x18\x17 \x01(\v2\x1b.google.protobuf.Int64ValueR\x0esomeWrappedInt\x12L\n" +
+	"\x13some_wrapped_string\x18\x18 \x01(\v2\x1c.google.protobuf.StringValueR\x11someWrappedString\x125\n" +
+	"\x14some_optional_string\x18\x19 \x01(\tH\x01R\x12someOptionalString\x88\x01\x01\x123\n" +
+	"\x13some_optional_int32\x18\x1a \x01(\x05H\x02R\x11someOptionalInt32\x88\x01\x01\x12+\n" +
+	"\x0fsome_deprecated\x18\x1b \x01(\tB\x02\x18\x01R\x0esomeDeprecated\x12\x16\n" +
+	"\x05email\x18\x1e \x01(\tH\x00R\x05email\x12\x16\n" +
+	"\x05phone\x18\x1f \x01(\tH\x00R\x05phone\x12<\n" +
+	"\aaddress\x18  \x01(\v2 .validatortest.Message3.EmbeddedH\x00R\aaddress\x1aD\n" +
+	"\x16SomeStringToInt64Entry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\x1aD\n" +
+	"\x16SomeInt32ToStringEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15SomeBoolToStringEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\bR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\\\n" +
+	"\x17SomeStringToStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x15.validatortest.StatusR\x05value:\x028\x01\x1ai\n" +
+	"\x19SomeStringToEmbeddedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x126\n" +
+	"\x05value\x18\x02 \x01(\v2 .validatortest.Message3.EmbeddedR\x05value:\x028\x01\x1a\x87\x01\n" +
+	"\bEmbedded\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x1d\n" +
+	"\n" +
+	"some_value\x18\x02 \x01(\x03R\tsomeValue\x12<\n" +
+	"\bchildren\x18\x03 \x03(\v2 .validatortest.Message3.EmbeddedR\bchildrenB\t\n" +
+	"\acontactB\x17\n" +
+	"\x15_some_optional_stringB\x16\n" +
+	"\x14_some_optional_int32*D\n" +
+	"\x06Status\x12\x12\n" +
+	"\x0eSTATUS_UNKNOWN\x10\x00\x12\x11\n" +
+	"\rSTATUS_ACTIVE\x10\x01\x12\x13\n" +
+	"\x0fSTATUS_DISABLED\x10\x02B5Z3github.com/mwitkow/go-nicejsonpb/test;validatortestb\x06proto3"
+
+var (
+	file_nicejsonpb_proto3_proto_rawDescOnce sync.Once
+	file_nicejsonpb_proto3_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_proto3_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_proto3_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_proto3_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_proto3_proto_rawDesc), len(file_nicejsonpb_proto3_proto_rawDesc)))
+	})
+	return file_nicejsonpb_proto3_proto_rawDescData
+}
+
+var file_nicejsonpb_proto3_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_nicejsonpb_proto3_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_nicejsonpb_proto3_proto_goTypes = []any{
+	(Status)(0),                    // 0: validatortest.Status
+	(*Message3)(nil),               // 1: validatortest.Message3
+	nil,                            // 2: validatortest.Message3.SomeStringToInt64Entry
+	nil,                            // 3: validatortest.Message3.SomeInt32ToStringEntry
+	nil,                            // 4: validatortest.Message3.SomeBoolToStringEntry
+	nil,                            // 5: validatortest.Message3.SomeStringToStatusEntry
+	nil,                            // 6: validatortest.Message3.SomeStringToEmbeddedEntry
+	(*Message3_Embedded)(nil),      // 7: validatortest.Message3.Embedded
+	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 9: google.protobuf.Duration
+	(*wrapperspb.Int64Value)(nil),  // 10: google.protobuf.Int64Value
+	(*wrapperspb.StringValue)(nil), // 11: google.protobuf.StringValue
+}
+var file_nicejsonpb_proto3_proto_depIdxs = []int32{
+	0,  // 0: validatortest.Message3.some_status:type_name -> validatortest.Status
+	0,  // 1: validatortest.Message3.some_status_rep:type_name -> validatortest.Status
+	7,  // 2: validatortest.Message3.some_embedded:type_name -> validatortest.Message3.Embedded
+	7,  // 3: validatortest.Message3.some_embedded_rep:type_name -> validatortest.Message3.Embedded
+	2,  // 4: validatortest.Message3.some_string_to_int64:type_name -> validatortest.Message3.SomeStringToInt64Entry
+	3,  // 5: validatortest.Message3.some_int32_to_string:type_name -> validatortest.Message3.SomeInt32ToStringEntry
+	4,  // 6: validatortest.Message3.some_bool_to_string:type_name -> validatortest.Message3.SomeBoolToStringEntry
+	5,  // 7: validatortest.Message3.some_string_to_status:type_name -> validatortest.Message3.SomeStringToStatusEntry
+	6,  // 8: validatortest.Message3.some_string_to_embedded:type_name -> validatortest.Message3.SomeStringToEmbeddedEntry
+	8,  // 9: validatortest.Message3.some_timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 10: validatortest.Message3.some_duration:type_name -> google.protobuf.Duration
+	10, // 11: validatortest.Message3.some_wrapped_int:type_name -> google.protobuf.Int64Value
+	11, // 12: validatortest.Message3.some_wrapped_string:type_name -> google.protobuf.StringValue
+	7,  // 13: validatortest.Message3.address:type_name -> validatortest.Message3.Embedded
+	0,  // 14: validatortest.Message3.SomeStringToStatusEntry.value:type_name -> validatortest.Status
+	7,  // 15: validatortest.Message3.SomeStringToEmbeddedEntry.value:type_name -> validatortest.Message3.Embedded
+	7,  // 16: validatortest.Message3.Embedded.children:type_name -> validatortest.Message3.Embedded
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_proto3_proto_init() }
+func file_nicejsonpb_proto3_proto_init() {
+	if File_nicejsonpb_proto3_proto != nil {
+		return
+	}
+	file_nicejsonpb_proto3_proto_msgTypes[0].OneofWrappers = []any{
+		(*Message3_Email)(nil),
+		(*Message3_Phone)(nil),
+		(*Message3_Address)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_proto3_proto_rawDesc), len(file_nicejsonpb_proto3_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_proto3_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_proto3_proto_depIdxs,
+		EnumInfos:         file_nicejsonpb_proto3_proto_enumTypes,
+		MessageInfos:      file_nicejsonpb_proto3_proto_msgTypes,
+	}.Build()
+	File_nicejsonpb_proto3_proto = out.File
+	file_nicejsonpb_proto3_proto_goTypes = nil
+	file_nicejsonpb_proto3_proto_depIdxs = nil
+}
@@ -0,0 +1,150 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_sensitive.proto
+
+package validatortest
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Credentials struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Pin           int32                  `protobuf:"varint,2,opt,name=pin,proto3" json:"pin,omitempty"`
+	Attempts      int32                  `protobuf:"varint,3,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Previous      *Credentials           `protobuf:"bytes,4,opt,name=previous,proto3" json:"previous,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credentials) Reset() {
+	*x = Credentials{}
+	mi := &file_nicejsonpb_sensitive_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credentials) ProtoMessage() {}
+
+func (x *Credentials) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_sensitive_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credentials.ProtoReflect.Descriptor instead.
+func (*Credentials) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_sensitive_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Credentials) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Credentials) GetPin() int32 {
+	if x != nil {
+		return x.Pin
+	}
+	return 0
+}
+
+func (x *Credentials) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Credentials) GetPrevious() *Credentials {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+var File_nicejsonpb_sensitive_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_sensitive_proto_rawDesc = "" +
+	"\n" +
+	"\x1anicejsonpb_sensitive.proto\x12\rvalidatortest\"\x91\x01\n" +
+	"\vCredentials\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x15\n" +
+	"\x03pin\x18\x02 \x01(\x05B\x03\x80\x01\x01R\x03pin\x12\x1a\n" +
+	"\battempts\x18\x03 \x01(\x05R\battempts\x12;\n" +
+	"\bprevious\x18\x04 \x01(\v2\x1a.validatortest.CredentialsB\x03\x80\x01\x01R\bpreviousB5Z3github.com/mwitkow/go-nicejsonpb/test;validatortestb\x06proto3"
+
+var (
+	file_nicejsonpb_sensitive_proto_rawDescOnce sync.Once
+	file_nicejsonpb_sensitive_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_sensitive_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_sensitive_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_sensitive_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_sensitive_proto_rawDesc), len(file_nicejsonpb_sensitive_proto_rawDesc)))
+	})
+	return file_nicejsonpb_sensitive_proto_rawDescData
+}
+
+var file_nicejsonpb_sensitive_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_nicejsonpb_sensitive_proto_goTypes = []any{
+	(*Credentials)(nil), // 0: validatortest.Credentials
+}
+var file_nicejsonpb_sensitive_proto_depIdxs = []int32{
+	0, // 0: validatortest.Credentials.previous:type_name -> validatortest.Credentials
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_sensitive_proto_init() }
+func file_nicejsonpb_sensitive_proto_init() {
+	if File_nicejsonpb_sensitive_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_sensitive_proto_rawDesc), len(file_nicejsonpb_sensitive_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_sensitive_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_sensitive_proto_depIdxs,
+		MessageInfos:      file_nicejsonpb_sensitive_proto_msgTypes,
+	}.Build()
+	File_nicejsonpb_sensitive_proto = out.File
+	file_nicejsonpb_sensitive_proto_goTypes = nil
+	file_nicejsonpb_sensitive_proto_depIdxs = nil
+}
@@ -0,0 +1,210 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v3.21.12
+// source: nicejsonpb_wkt.proto
+
+package validatortest
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Price struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyCode  string                 `protobuf:"bytes,1,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	Units         int64                  `protobuf:"varint,2,opt,name=units,proto3" json:"units,omitempty"`
+	Nanos         int32                  `protobuf:"varint,3,opt,name=nanos,proto3" json:"nanos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_nicejsonpb_wkt_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_wkt_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_wkt_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Price) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *Price) GetUnits() int64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *Price) GetNanos() int32 {
+	if x != nil {
+		return x.Nanos
+	}
+	return 0
+}
+
+type PriceList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         *Price                 `protobuf:"bytes,1,opt,name=total,proto3" json:"total,omitempty"`
+	Items         []*Price               `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Extra         *anypb.Any             `protobuf:"bytes,3,opt,name=extra,proto3" json:"extra,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceList) Reset() {
+	*x = PriceList{}
+	mi := &file_nicejsonpb_wkt_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceList) ProtoMessage() {}
+
+func (x *PriceList) ProtoReflect() protoreflect.Message {
+	mi := &file_nicejsonpb_wkt_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceList.ProtoReflect.Descriptor instead.
+func (*PriceList) Descriptor() ([]byte, []int) {
+	return file_nicejsonpb_wkt_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PriceList) GetTotal() *Price {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *PriceList) GetItems() []*Price {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PriceList) GetExtra() *anypb.Any {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+var File_nicejsonpb_wkt_proto protoreflect.FileDescriptor
+
+const file_nicejsonpb_wkt_proto_rawDesc = "" +
+	"\n" +
+	"\x14nicejsonpb_wkt.proto\x12\rvalidatortest\x1a\x19google/protobuf/any.proto\"X\n" +
+	"\x05Price\x12#\n" +
+	"\rcurrency_code\x18\x01 \x01(\tR\fcurrencyCode\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\x03R\x05units\x12\x14\n" +
+	"\x05nanos\x18\x03 \x01(\x05R\x05nanos\"\x8f\x01\n" +
+	"\tPriceList\x12*\n" +
+	"\x05total\x18\x01 \x01(\v2\x14.validatortest.PriceR\x05total\x12*\n" +
+	"\x05items\x18\x02 \x03(\v2\x14.validatortest.PriceR\x05items\x12*\n" +
+	"\x05extra\x18\x03 \x01(\v2\x14.google.protobuf.AnyR\x05extraB5Z3github.com/mwitkow/go-nicejsonpb/test;validatortestb\x06proto3"
+
+var (
+	file_nicejsonpb_wkt_proto_rawDescOnce sync.Once
+	file_nicejsonpb_wkt_proto_rawDescData []byte
+)
+
+func file_nicejsonpb_wkt_proto_rawDescGZIP() []byte {
+	file_nicejsonpb_wkt_proto_rawDescOnce.Do(func() {
+		file_nicejsonpb_wkt_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nicejsonpb_wkt_proto_rawDesc), len(file_nicejsonpb_wkt_proto_rawDesc)))
+	})
+	return file_nicejsonpb_wkt_proto_rawDescData
+}
+
+var file_nicejsonpb_wkt_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_nicejsonpb_wkt_proto_goTypes = []any{
+	(*Price)(nil),     // 0: validatortest.Price
+	(*PriceList)(nil), // 1: validatortest.PriceList
+	(*anypb.Any)(nil), // 2: google.protobuf.Any
+}
+var file_nicejsonpb_wkt_proto_depIdxs = []int32{
+	0, // 0: validatortest.PriceList.total:type_name -> validatortest.Price
+	0, // 1: validatortest.PriceList.items:type_name -> validatortest.Price
+	2, // 2: validatortest.PriceList.extra:type_name -> google.protobuf.Any
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_nicejsonpb_wkt_proto_init() }
+func file_nicejsonpb_wkt_proto_init() {
+	if File_nicejsonpb_wkt_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nicejsonpb_wkt_proto_rawDesc), len(file_nicejsonpb_wkt_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nicejsonpb_wkt_proto_goTypes,
+		DependencyIndexes: file_nicejsonpb_wkt_proto_depIdxs,
+		MessageInfos:      file_nicejsonpb_wkt_proto_msgTypes,
+	}.Build()
+	File_nicejsonpb_wkt_proto = out.File
+	file_nicejsonpb_wkt_proto_goTypes = nil
+	file_nicejsonpb_wkt_proto_depIdxs = nil
+}
@@ -0,0 +1,996 @@
+// Package textproto decodes protocol buffers written in the text format, as configuration files
+// often are. Documents are converted to JSON, guided by the descriptor of the message, and decoded
+// by a nicejsonpb.Unmarshaler, so fields are decoded as from JSON and field errors read as they do
+// for JSON, with the line and column of the value in the document added:
+//
+//	err := textproto.UnmarshalText(r, pb)
+//
+// All of the text format is understood: comments, the optional colon before messages, both
+// {} and <> delimiters, lists, repeated and map fields written once per element, extensions,
+// expanded google.protobuf.Any values, and the escapes, concatenation and number forms of values.
+// Well-known types, which JSON holds in forms of their own, are written as messages, as in any
+// other text format document.
+package textproto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// UnmarshalText decodes the text format document held in r into pb with the default options.
+func UnmarshalText(r io.Reader, pb proto.Message) error {
+	return UnmarshalTextWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalTextWith decodes the text format document held in r into pb with the options of u.
+// Errors about fields are returned as an *Error, or a nicejsonpb.MultiError of them.
+func UnmarshalTextWith(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c := &converter{in: b, u: u}
+	if err := c.tokenize(); err != nil {
+		return err
+	}
+	doc, err := c.document(proto.MessageReflect(pb).Descriptor())
+	if err != nil {
+		return err
+	}
+	return doc.locate(u.UnmarshalBytes(doc.json, pb))
+}
+
+// SyntaxError is returned for documents that aren't valid text format.
+type SyntaxError struct {
+	// Line and Column, both starting at 1, locate the problem. Columns count characters.
+	Line, Column int
+	Msg          string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("textproto: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Error is an error about the field of a text format document at Line and Column, both starting
+// at 1.
+type Error struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Err)
+}
+
+// Unwrap gives errors.Is, errors.As and the functions of nicejsonpb, such as ErrorCode and
+// ErrorPointer, access to the error of the field.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+// token is a token of a text format document. For strings, value holds the decoded contents of
+// the token and any strings following it, which the text format concatenates.
+type token struct {
+	kind     tokenKind
+	text     string
+	value    string
+	pos, end int
+}
+
+// node is a value converted to JSON, with the offsets in the document of the values it holds by
+// their JSON Pointer relative to it.
+type node struct {
+	json    []byte
+	offsets map[string]int
+}
+
+// converter converts a text format document to JSON.
+type converter struct {
+	in []byte
+	// u is the Unmarshaler the JSON is for.
+	u      *nicejsonpb.Unmarshaler
+	tokens []token
+	// i is the index in tokens of the next token.
+	i int
+}
+
+// document converts the whole document, holding a message of the type md.
+func (c *converter) document(md protoreflect.MessageDescriptor) (*located, error) {
+	var n *node
+	var err error
+	if hasJSONForm(md) {
+		n, err = c.wellKnown(md, 0, len(c.in))
+		c.i = len(c.tokens) - 1
+	} else {
+		n, err = c.message(md, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tok := c.peek(); tok.kind != tokenEOF {
+		return nil, c.errorAt(tok.pos, "unexpected %s", describe(tok))
+	}
+	return &located{in: c.in, json: n.json, offsets: n.offsets}, nil
+}
+
+// field is a field of a message being converted, with all the values it was given.
+type field struct {
+	key    string
+	fd     protoreflect.FieldDescriptor
+	offset int
+	values []*node
+	// mapKeys are the keys of values, for map fields.
+	mapKeys []string
+}
+
+// message converts the fields of a message of the type md, nil if unknown, up to the token
+// closing it.
+func (c *converter) message(md protoreflect.MessageDescriptor, closing string) (*node, error) {
+	var fields []*field
+	byKey := map[string]*field{}
+	var typeURL string
+	var expanded *node
+	for {
+		tok := c.next()
+		if tok.kind == tokenEOF && closing == "" || tok.kind == tokenPunct && tok.text == closing {
+			break
+		}
+		if tok.kind == tokenEOF {
+			return nil, c.errorAt(tok.pos, "expected %q before the end of the document", closing)
+		}
+		key, fd, err := c.fieldName(md, tok)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(key, "/") {
+			if typeURL != "" || len(fields) > 0 {
+				return nil, c.errorAt(tok.pos, "an expanded Any must be its only field")
+			}
+			typeURL = key[1 : len(key)-1]
+			if expanded, err = c.any(typeURL, tok.pos); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if typeURL != "" {
+			return nil, c.errorAt(tok.pos, "an expanded Any must be its only field")
+		}
+		f := byKey[key]
+		if f == nil {
+			f = &field{key: key, fd: fd, offset: tok.pos}
+			byKey[key] = f
+			fields = append(fields, f)
+		} else if fd != nil && fd.Cardinality() != protoreflect.Repeated {
+			return nil, c.errorAt(tok.pos, "non-repeated field %q is set more than once", key)
+		}
+		if err := c.fieldValues(f); err != nil {
+			return nil, err
+		}
+		if tok := c.peek(); tok.kind == tokenPunct && (tok.text == "," || tok.text == ";") {
+			c.next()
+		}
+	}
+	if typeURL != "" {
+		return expanded, nil
+	}
+	return render(fields), nil
+}
+
+// fieldName reads the name of a field starting with tok, returning its key in the JSON and its
+// descriptor, nil if unknown.
+func (c *converter) fieldName(md protoreflect.MessageDescriptor, tok token) (string, protoreflect.FieldDescriptor, error) {
+	if tok.kind == tokenIdent {
+		if md == nil {
+			return tok.text, nil, nil
+		}
+		fd := md.Fields().ByTextName(tok.text)
+		if fd == nil {
+			return tok.text, nil, nil
+		}
+		return string(fd.Name()), fd, nil
+	}
+	if tok.kind != tokenPunct || tok.text != "[" {
+		return "", nil, c.errorAt(tok.pos, "expected a field name, found %s", describe(tok))
+	}
+	var name strings.Builder
+	for {
+		tok := c.next()
+		if tok.kind == tokenPunct && tok.text == "]" {
+			break
+		}
+		if tok.kind != tokenIdent && (tok.kind != tokenPunct || tok.text != "." && tok.text != "/") {
+			return "", nil, c.errorAt(tok.pos, "expected an extension name or type URL, found %s", describe(tok))
+		}
+		name.WriteString(tok.text)
+	}
+	key := "[" + name.String() + "]"
+	if strings.Contains(key, "/") {
+		if md == nil || md.FullName() != "google.protobuf.Any" {
+			return "", nil, c.errorAt(tok.pos, "only google.protobuf.Any values can be expanded")
+		}
+		return key, nil, nil
+	}
+	xt, err := protoregistry.GlobalTypes.FindExtensionByName(protoreflect.FullName(name.String()))
+	if err != nil {
+		return key, nil, nil
+	}
+	return key, xt.TypeDescriptor(), nil
+}
+
+// fieldValues reads the values given to f after its name, a single one or a list of them.
+func (c *converter) fieldValues(f *field) error {
+	isMessage := f.fd != nil && f.fd.Message() != nil
+	tok := c.peek()
+	if tok.kind == tokenPunct && tok.text == ":" {
+		c.next()
+		tok = c.peek()
+	} else if tok.kind != tokenPunct || tok.text != "{" && tok.text != "<" {
+		return c.errorAt(tok.pos, "expected \":\" after the field name, found %s", describe(tok))
+	}
+	if tok.kind != tokenPunct || tok.text != "[" {
+		return c.fieldValue(f)
+	}
+	c.next()
+	if tok := c.peek(); tok.kind == tokenPunct && tok.text == "]" {
+		c.next()
+		return nil
+	}
+	for {
+		if err := c.fieldValue(f); err != nil {
+			return err
+		}
+		tok := c.next()
+		if tok.kind == tokenPunct && tok.text == "]" {
+			return nil
+		}
+		if tok.kind != tokenPunct || tok.text != "," {
+			kind := "values"
+			if isMessage {
+				kind = "messages"
+			}
+			return c.errorAt(tok.pos, "expected \",\" or \"]\" in a list of %s, found %s", kind, describe(tok))
+		}
+	}
+}
+
+// fieldValue reads a value of f and adds it to its values.
+func (c *converter) fieldValue(f *field) error {
+	offset := c.peek().pos
+	if f.fd != nil && f.fd.IsMap() {
+		return c.mapEntry(f)
+	}
+	var n *node
+	var err error
+	if f.fd != nil && f.fd.Message() != nil || f.fd == nil && c.opensMessage() {
+		var md protoreflect.MessageDescriptor
+		if f.fd != nil {
+			md = f.fd.Message()
+		}
+		n, err = c.messageValue(md)
+	} else {
+		n, err = c.scalar(f.fd)
+	}
+	if err != nil {
+		return err
+	}
+	n.offsets[""] = offset
+	f.values = append(f.values, n)
+	return nil
+}
+
+// mapEntry reads an entry of the map field f, written as a message with key and value fields.
+func (c *converter) mapEntry(f *field) error {
+	offset := c.peek().pos
+	entry := f.fd.Message()
+	open := c.next()
+	if !isOpening(open) {
+		return c.errorAt(open.pos, "expected a map entry, found %s", describe(open))
+	}
+	var key, value *field
+	for {
+		tok := c.next()
+		if tok.kind == tokenPunct && tok.text == closingOf(open.text) {
+			break
+		}
+		if tok.kind != tokenIdent || tok.text != "key" && tok.text != "value" {
+			return c.errorAt(tok.pos, "expected \"key\" or \"value\" in a map entry, found %s", describe(tok))
+		}
+		fd := entry.Fields().ByName(protoreflect.Name(tok.text))
+		target := &key
+		if tok.text == "value" {
+			target = &value
+		}
+		if *target != nil {
+			return c.errorAt(tok.pos, "%q is set more than once in a map entry", tok.text)
+		}
+		*target = &field{key: tok.text, fd: fd, offset: tok.pos}
+		if err := c.fieldValues(*target); err != nil {
+			return err
+		}
+		if len((*target).values) != 1 {
+			return c.errorAt(tok.pos, "%q of a map entry must have a single value", tok.text)
+		}
+		if tok := c.peek(); tok.kind == tokenPunct && (tok.text == "," || tok.text == ";") {
+			c.next()
+		}
+	}
+	mapKey := ""
+	if key != nil {
+		mapKey = mapKeyOf(key.values[0].json)
+	} else if f.fd.MapKey().Kind() == protoreflect.BoolKind {
+		mapKey = "false"
+	} else if f.fd.MapKey().Kind() != protoreflect.StringKind {
+		mapKey = "0"
+	}
+	n := &node{json: zeroValue(f.fd.MapValue()), offsets: map[string]int{"": offset}}
+	if value != nil {
+		n = value.values[0]
+		n.offsets[""] = value.offset
+	}
+	f.values = append(f.values, n)
+	f.mapKeys = append(f.mapKeys, mapKey)
+	return nil
+}
+
+// zeroValue returns the JSON form of the default value of fd, that of map entries without a value.
+func zeroValue(fd protoreflect.FieldDescriptor) []byte {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return []byte("{}")
+	case protoreflect.BoolKind:
+		return []byte("false")
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return []byte(`""`)
+	case protoreflect.EnumKind:
+		b, _ := json.Marshal(string(fd.Enum().Values().Get(0).Name()))
+		return b
+	}
+	return []byte("0")
+}
+
+// mapKeyOf returns the JSON object key for the map key converted to j.
+func mapKeyOf(j []byte) string {
+	var s string
+	if json.Unmarshal(j, &s) == nil {
+		return s
+	}
+	return string(j)
+}
+
+// messageValue reads a message of the type md, nil if unknown, from its opening token on.
+func (c *converter) messageValue(md protoreflect.MessageDescriptor) (*node, error) {
+	open := c.next()
+	if !isOpening(open) {
+		return nil, c.errorAt(open.pos, "expected a message, found %s", describe(open))
+	}
+	if md != nil && hasJSONForm(md) {
+		end, err := c.skipMessage(open.text)
+		if err != nil {
+			return nil, err
+		}
+		return c.wellKnown(md, open.end, end)
+	}
+	return c.message(md, closingOf(open.text))
+}
+
+// skipMessage skips to the end of the message opened with the token open, returning the offset
+// of the token closing it.
+func (c *converter) skipMessage(open string) (int, error) {
+	stack := []string{closingOf(open)}
+	for {
+		tok := c.next()
+		switch {
+		case tok.kind == tokenEOF:
+			return 0, c.errorAt(tok.pos, "expected %q before the end of the document", stack[len(stack)-1])
+		case isOpening(tok):
+			stack = append(stack, closingOf(tok.text))
+		case tok.kind == tokenPunct && tok.text == stack[len(stack)-1]:
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return tok.pos, nil
+			}
+		}
+	}
+}
+
+// hasJSONForm reports whether messages of the type md are held in JSON as something other than an
+// object of their fields.
+func hasJSONForm(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration", "google.protobuf.FieldMask",
+		"google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.ListValue",
+		"google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return true
+	}
+	return false
+}
+
+// wellKnown converts the fields of a well-known type md written in c.in[start:end] to the JSON form
+// of the type.
+func (c *converter) wellKnown(md protoreflect.MessageDescriptor, start, end int) (*node, error) {
+	m := dynamicpb.NewMessage(md)
+	if err := prototext.Unmarshal(c.in[start:end], m); err != nil {
+		msg := strings.TrimSpace(protoMessage(err))
+		offset := start
+		if match := prototextPosition.FindStringSubmatch(msg); match != nil {
+			// Positions are within the fields given to prototext, make them within the document.
+			line, _ := strconv.Atoi(match[1])
+			column, _ := strconv.Atoi(match[2])
+			for ; line > 1 && offset < end; offset++ {
+				if c.in[offset] == '\n' {
+					line--
+				}
+			}
+			offset += column - 1
+			msg = match[3]
+		}
+		return nil, c.errorAt(offset, "%s: %s", md.FullName(), msg)
+	}
+	j, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, c.errorAt(start, "%s: %s", md.FullName(), protoMessage(err))
+	}
+	return &node{json: j, offsets: map[string]int{}}, nil
+}
+
+// protoMessage returns the message of an error of the protobuf module without its "proto:" prefix.
+func protoMessage(err error) string {
+	// The space after the prefix is varied on purpose, to keep the errors from being parsed.
+	return strings.TrimLeft(strings.TrimPrefix(err.Error(), "proto:"), " \u00a0")
+}
+
+// prototextPosition matches the position prototext starts its errors with.
+var prototextPosition = regexp.MustCompile(`^\(line (\d+):(\d+)\): (.*)$`)
+
+// any reads the message of an expanded google.protobuf.Any with the given type URL.
+func (c *converter) any(typeURL string, offset int) (*node, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, c.errorAt(offset, "unknown type %q", typeURL)
+	}
+	if tok := c.peek(); tok.kind == tokenPunct && tok.text == ":" {
+		c.next()
+	}
+	inner, err := c.messageValue(mt.Descriptor())
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(`{"@type":`)
+	writeString(&b, typeURL)
+	switch {
+	case hasJSONForm(mt.Descriptor()):
+		b.WriteString(`,"value":`)
+		b.Write(inner.json)
+		b.WriteByte('}')
+		inner.offsets = map[string]int{"/value": offset}
+	case string(inner.json) == "{}":
+		b.WriteByte('}')
+	default:
+		b.WriteByte(',')
+		b.Write(inner.json[1:])
+	}
+	inner.json = b.Bytes()
+	inner.offsets["/@type"] = offset
+	return inner, nil
+}
+
+// render converts the fields of a message to a JSON object.
+func render(fields []*field) *node {
+	n := &node{offsets: map[string]int{}}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeString(&b, f.key)
+		b.WriteByte(':')
+		pointer := "/" + pointerEscaper.Replace(f.key)
+		n.offsets[pointer] = f.offset
+		switch {
+		case f.fd != nil && f.fd.IsMap():
+			b.WriteByte('{')
+			for j, v := range f.values {
+				if j > 0 {
+					b.WriteByte(',')
+				}
+				writeString(&b, f.mapKeys[j])
+				b.WriteByte(':')
+				b.Write(v.json)
+				n.add(pointer+"/"+pointerEscaper.Replace(f.mapKeys[j]), v)
+			}
+			b.WriteByte('}')
+		case f.fd != nil && f.fd.Cardinality() == protoreflect.Repeated || len(f.values) != 1:
+			b.WriteByte('[')
+			for j, v := range f.values {
+				if j > 0 {
+					b.WriteByte(',')
+				}
+				b.Write(v.json)
+				n.add(pointer+"/"+strconv.Itoa(j), v)
+			}
+			b.WriteByte(']')
+		default:
+			b.Write(f.values[0].json)
+			n.add(pointer, f.values[0])
+		}
+	}
+	b.WriteByte('}')
+	n.json = b.Bytes()
+	return n
+}
+
+// add records the offsets of the values of v, placed at pointer within n.
+func (n *node) add(pointer string, v *node) {
+	for p, offset := range v.offsets {
+		n.offsets[pointer+p] = offset
+	}
+}
+
+// scalar reads a value of a field that isn't a message, with the descriptor fd, nil if unknown.
+func (c *converter) scalar(fd protoreflect.FieldDescriptor) (*node, error) {
+	tok := c.next()
+	negative := false
+	if tok.kind == tokenPunct && tok.text == "-" {
+		negative = true
+		tok = c.next()
+		if tok.kind != tokenNumber && tok.kind != tokenIdent {
+			return nil, c.errorAt(tok.pos, "expected a number after \"-\", found %s", describe(tok))
+		}
+	}
+	if tok.kind != tokenNumber && tok.kind != tokenIdent && tok.kind != tokenString {
+		return nil, c.errorAt(tok.pos, "expected a value, found %s", describe(tok))
+	}
+	var b bytes.Buffer
+	if fd == nil || !c.scalarValue(&b, fd.Kind(), tok, negative) {
+		genericValue(&b, tok, negative)
+	}
+	return &node{json: b.Bytes(), offsets: map[string]int{}}, nil
+}
+
+// scalarValue writes the JSON form of the value tok, negated if negative, for a field of the given
+// kind. It reports false if tok isn't a value of the kind, for the unmarshaler to report.
+func (c *converter) scalarValue(b *bytes.Buffer, kind protoreflect.Kind, tok token, negative bool) bool {
+	switch kind {
+	case protoreflect.BoolKind:
+		if negative {
+			return false
+		}
+		switch tok.text {
+		case "true", "True", "t", "1":
+			b.WriteString("true")
+		case "false", "False", "f", "0":
+			b.WriteString("false")
+		default:
+			return false
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		i, ok := parseInt(tok)
+		if !ok {
+			return false
+		}
+		if negative {
+			i.Neg(i)
+		}
+		b.WriteString(i.String())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return floatValue(b, tok, negative)
+	case protoreflect.EnumKind:
+		switch {
+		case tok.kind == tokenIdent && !negative:
+			writeString(b, tok.text)
+		case tok.kind == tokenNumber:
+			i, ok := parseInt(tok)
+			if !ok {
+				return false
+			}
+			if negative {
+				i.Neg(i)
+			}
+			b.WriteString(i.String())
+		default:
+			return false
+		}
+	case protoreflect.StringKind:
+		if tok.kind != tokenString {
+			return false
+		}
+		writeRawString(b, tok.value)
+	case protoreflect.BytesKind:
+		if tok.kind != tokenString {
+			return false
+		}
+		writeString(b, c.u.EncodeBytes([]byte(tok.value)))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseInt parses an integer in decimal, or in hexadecimal or octal with the prefixes of C.
+func parseInt(tok token) (*big.Int, bool) {
+	if tok.kind != tokenNumber {
+		return nil, false
+	}
+	text, base := tok.text, 10
+	switch {
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X"):
+		text, base = text[2:], 16
+	case len(text) > 1 && text[0] == '0':
+		text, base = text[1:], 8
+	}
+	return new(big.Int).SetString(text, base)
+}
+
+// floatValue writes the JSON form of the float tok, negated if negative.
+func floatValue(b *bytes.Buffer, tok token, negative bool) bool {
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	if tok.kind == tokenIdent {
+		switch strings.ToLower(tok.text) {
+		case "inf", "infinity":
+			writeString(b, sign+"Infinity")
+		case "nan":
+			writeString(b, "NaN")
+		default:
+			return false
+		}
+		return true
+	}
+	if tok.kind != tokenNumber {
+		return false
+	}
+	if i, ok := parseInt(tok); ok {
+		b.WriteString(sign + i.String())
+		return true
+	}
+	text := strings.TrimRight(tok.text, "fF")
+	if strings.HasPrefix(text, ".") {
+		text = "0" + text
+	}
+	if strings.HasSuffix(text, ".") {
+		text += "0"
+	}
+	if !json.Valid([]byte(text)) {
+		return false
+	}
+	b.WriteString(sign + text)
+	return true
+}
+
+// genericValue writes tok, negated if negative, as the JSON value closest to it, for values the
+// unmarshaler rejects or of fields it doesn't know.
+func genericValue(b *bytes.Buffer, tok token, negative bool) {
+	switch {
+	case tok.kind == tokenString:
+		writeRawString(b, tok.value)
+	case tok.kind == tokenIdent && (tok.text == "true" || tok.text == "false") && !negative:
+		b.WriteString(tok.text)
+	case tok.kind == tokenNumber && floatValue(b, tok, negative):
+	case negative:
+		writeString(b, "-"+tok.text)
+	default:
+		writeString(b, tok.text)
+	}
+}
+
+// opensMessage reports whether the next token, after an optional colon, opens a message.
+func (c *converter) opensMessage() bool {
+	return isOpening(c.peek())
+}
+
+func isOpening(tok token) bool {
+	return tok.kind == tokenPunct && (tok.text == "{" || tok.text == "<")
+}
+
+func closingOf(open string) string {
+	if open == "<" {
+		return ">"
+	}
+	return "}"
+}
+
+// describe names tok, for errors.
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenEOF:
+		return "end of document"
+	case tokenString:
+		return "string " + tok.text
+	default:
+		return strconv.Quote(tok.text)
+	}
+}
+
+func (c *converter) peek() token {
+	return c.tokens[c.i]
+}
+
+func (c *converter) next() token {
+	tok := c.tokens[c.i]
+	if tok.kind != tokenEOF {
+		c.i++
+	}
+	return tok
+}
+
+// tokenize splits the document into tokens, ending with one of kind tokenEOF.
+func (c *converter) tokenize() error {
+	pos := 0
+	for {
+		for pos < len(c.in) {
+			if ch := c.in[pos]; ch == '#' {
+				for pos < len(c.in) && c.in[pos] != '\n' {
+					pos++
+				}
+			} else if strings.IndexByte(" \t\n\v\f\r", ch) >= 0 {
+				pos++
+			} else {
+				break
+			}
+		}
+		if pos == len(c.in) {
+			c.tokens = append(c.tokens, token{kind: tokenEOF, pos: pos, end: pos})
+			return nil
+		}
+		tok := token{pos: pos}
+		switch ch := c.in[pos]; {
+		case ch == '_' || isLetter(ch):
+			tok.kind = tokenIdent
+			for pos < len(c.in) && (c.in[pos] == '_' || isLetter(c.in[pos]) || isDigit(c.in[pos])) {
+				pos++
+			}
+		case isDigit(ch) || ch == '.' && pos+1 < len(c.in) && isDigit(c.in[pos+1]):
+			tok.kind = tokenNumber
+			hex := bytes.HasPrefix(c.in[pos:], []byte("0x")) || bytes.HasPrefix(c.in[pos:], []byte("0X"))
+			for pos < len(c.in) {
+				ch := c.in[pos]
+				exponentSign := (ch == '+' || ch == '-') && !hex && (c.in[pos-1] == 'e' || c.in[pos-1] == 'E')
+				if !isLetter(ch) && !isDigit(ch) && ch != '.' && ch != '_' && !exponentSign {
+					break
+				}
+				pos++
+			}
+		case ch == '"' || ch == '\'':
+			tok.kind = tokenString
+			value, end, err := c.unquote(pos)
+			if err != nil {
+				return err
+			}
+			tok.value, pos = value, end
+			if last := len(c.tokens) - 1; last >= 0 && c.tokens[last].kind == tokenString {
+				// Adjacent strings are concatenated.
+				c.tokens[last].value += tok.value
+				c.tokens[last].end = pos
+				continue
+			}
+		case strings.IndexByte("{}<>[]:,;/.-", ch) >= 0:
+			tok.kind = tokenPunct
+			pos++
+		default:
+			r, _ := utf8.DecodeRune(c.in[pos:])
+			return c.errorAt(pos, "unexpected character %q", r)
+		}
+		tok.text, tok.end = string(c.in[tok.pos:pos]), pos
+		c.tokens = append(c.tokens, tok)
+	}
+}
+
+func isLetter(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z'
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// unquote decodes the string starting with the quote at start, returning its value and the offset
+// after it.
+func (c *converter) unquote(start int) (string, int, error) {
+	quote := c.in[start]
+	var b []byte
+	pos := start + 1
+	for {
+		if pos == len(c.in) || c.in[pos] == '\n' {
+			return "", 0, c.errorAt(start, "unterminated string")
+		}
+		ch := c.in[pos]
+		if ch == quote {
+			return string(b), pos + 1, nil
+		}
+		if ch != '\\' {
+			b = append(b, ch)
+			pos++
+			continue
+		}
+		if pos+1 == len(c.in) {
+			return "", 0, c.errorAt(start, "unterminated string")
+		}
+		escape := c.in[pos+1]
+		pos += 2
+		switch escape {
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '\\', '\'', '"', '?':
+			b = append(b, escape)
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			n := int(escape - '0')
+			for i := 0; i < 2 && pos < len(c.in) && '0' <= c.in[pos] && c.in[pos] <= '7'; i++ {
+				n = n*8 + int(c.in[pos]-'0')
+				pos++
+			}
+			if n > 0xff {
+				return "", 0, c.errorAt(pos-4, "octal escape out of range")
+			}
+			b = append(b, byte(n))
+		case 'x', 'X':
+			n, digits := hexDigits(c.in[pos:], 2)
+			if digits == 0 {
+				return "", 0, c.errorAt(pos-2, "\\x escape without hexadecimal digits")
+			}
+			b = append(b, byte(n))
+			pos += digits
+		case 'u', 'U':
+			size := 4
+			if escape == 'U' {
+				size = 8
+			}
+			r, digits := hexDigits(c.in[pos:], size)
+			if digits != size {
+				return "", 0, c.errorAt(pos-2, "\\%c escape needs %d hexadecimal digits", escape, size)
+			}
+			pos += digits
+			if 0xd800 <= r && r < 0xdc00 && bytes.HasPrefix(c.in[pos:], []byte(`\u`)) {
+				// A surrogate pair.
+				if low, digits := hexDigits(c.in[pos+2:], 4); digits == 4 && 0xdc00 <= low && low < 0xe000 {
+					r = 0x10000 + (r-0xd800)<<10 + low - 0xdc00
+					pos += 6
+				}
+			}
+			b = utf8.AppendRune(b, rune(r))
+		default:
+			return "", 0, c.errorAt(pos-2, "unknown escape \\%c", escape)
+		}
+	}
+}
+
+// hexDigits parses up to max hexadecimal digits at the start of b, returning their value and how
+// many there were.
+func hexDigits(b []byte, max int) (int, int) {
+	n, digits := 0, 0
+	for digits < max && digits < len(b) {
+		v := strings.IndexByte("0123456789abcdef0123456789ABCDEF", b[digits]) % 16
+		if v < 0 {
+			break
+		}
+		n = n*16 + v
+		digits++
+	}
+	return n, digits
+}
+
+// errorAt returns a SyntaxError about the given offset of the document.
+func (c *converter) errorAt(offset int, format string, args ...interface{}) error {
+	line, column := lineColumn(c.in, offset)
+	return &SyntaxError{Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// lineColumn returns the line and column of the given offset of in, both starting at 1.
+func lineColumn(in []byte, offset int) (int, int) {
+	line, column := 1, 1
+	for _, r := range string(in[:offset]) {
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	b.Truncate(b.Len() - 1) // Encode ends with a newline.
+}
+
+// writeRawString writes s as a JSON string, keeping any invalid UTF-8 in it for the unmarshaler to
+// deal with as it does in JSON.
+func writeRawString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch < 0x20:
+			fmt.Fprintf(b, `\u%04x`, ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	b.WriteByte('"')
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// located is a document converted to JSON, with the offsets in the document of the values in it
+// by their JSON Pointer.
+type located struct {
+	in      []byte
+	json    []byte
+	offsets map[string]int
+}
+
+// locate adds the position in the document of the value each field error is about to err.
+func (l *located) locate(err error) error {
+	var multi nicejsonpb.MultiError
+	if errors.As(err, &multi) {
+		located := make(nicejsonpb.MultiError, len(multi))
+		for i, err := range multi {
+			located[i] = l.locate(err)
+		}
+		return located
+	}
+	pointer := nicejsonpb.ErrorPointer(err)
+	if err == nil || pointer == "" {
+		return err
+	}
+	for {
+		if offset, ok := l.offsets[pointer]; ok {
+			line, column := lineColumn(l.in, offset)
+			return &Error{Line: line, Column: column, Err: err}
+		}
+		slash := strings.LastIndexByte(pointer, '/')
+		if slash < 0 {
+			return err
+		}
+		pointer = pointer[:slash]
+	}
+}
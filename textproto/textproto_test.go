@@ -0,0 +1,129 @@
+package textproto_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/mwitkow/go-nicejsonpb/textproto"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `
+# A hand-written configuration.
+some_string: "multi" 'part\n'
+some_string_rep: ["a", "b"]
+some_string_rep: "c"
+some_int32: -0x10
+some_uint64: 017
+some_float: -inf
+some_double: 1.5f
+some_bool: t
+some_bytes: "\000\377"
+some_status: STATUS_ACTIVE
+some_status_rep: [1, STATUS_ACTIVE]
+some_embedded <
+  identifier: "x"
+  children { identifier: "y" }
+>
+some_string_to_int64 { key: "one" value: 1 }
+some_string_to_int64 { key: "two", value: 2 };
+some_int32_to_string { key: 3 value: "three" }
+some_timestamp { seconds: 1 nanos: 500000000 }
+some_duration { seconds: 90 }
+some_wrapped_int { value: 7 }
+`
+
+func TestUnmarshalText(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, textproto.UnmarshalText(strings.NewReader(config), m))
+	require.Equal(t, "multipart\n", m.SomeString)
+	require.Equal(t, []string{"a", "b", "c"}, m.SomeStringRep)
+	require.Equal(t, int32(-16), m.SomeInt32)
+	require.Equal(t, uint64(15), m.SomeUint64)
+	require.True(t, m.SomeFloat < 0 && m.SomeFloat*2 == m.SomeFloat)
+	require.Equal(t, 1.5, m.SomeDouble)
+	require.True(t, m.SomeBool)
+	require.Equal(t, []byte{0, 0xff}, m.SomeBytes)
+	require.Equal(t, validatortest.Status_STATUS_ACTIVE, m.SomeStatus)
+	require.Equal(t, []validatortest.Status{validatortest.Status_STATUS_ACTIVE, validatortest.Status_STATUS_ACTIVE}, m.SomeStatusRep)
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Equal(t, "y", m.SomeEmbedded.Children[0].Identifier)
+	require.Equal(t, map[string]int64{"one": 1, "two": 2}, m.SomeStringToInt64)
+	require.Equal(t, map[int32]string{3: "three"}, m.SomeInt32ToString)
+	require.Equal(t, time.Unix(1, 5e8).UTC(), m.SomeTimestamp.AsTime())
+	require.Equal(t, 90*time.Second, m.SomeDuration.AsDuration())
+	require.Equal(t, int64(7), m.SomeWrappedInt.Value)
+}
+
+func TestUnmarshalText_BytesEncoding(t *testing.T) {
+	for _, u := range []*nicejsonpb.Unmarshaler{
+		{BytesEncoding: nicejsonpb.BytesHex},
+		{Profile: nicejsonpb.ProfileLenient},
+	} {
+		m := &validatortest.Message3{}
+		require.NoError(t, textproto.UnmarshalTextWith(u, strings.NewReader(`some_bytes: "\000\377"`), m))
+		require.Equal(t, []byte{0, 0xff}, m.SomeBytes)
+		// In base64, "abcd", which also reads as hex.
+		require.NoError(t, textproto.UnmarshalTextWith(u, strings.NewReader(`some_bytes: "\x69\xb7\x1d"`), m))
+		require.Equal(t, []byte{0x69, 0xb7, 0x1d}, m.SomeBytes)
+	}
+}
+
+func TestUnmarshalText_MapEntriesWithoutValue(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, textproto.UnmarshalText(strings.NewReader(`
+some_string_to_int64 { key: "a" }
+some_int32_to_string { key: 1 }
+some_string_to_embedded { key: "b" }
+some_string_to_status { key: "c" }
+`), m))
+	require.Equal(t, map[string]int64{"a": 0}, m.SomeStringToInt64)
+	require.Equal(t, map[int32]string{1: ""}, m.SomeInt32ToString)
+	require.NotNil(t, m.SomeStringToEmbedded["b"])
+	require.Equal(t, map[string]validatortest.Status{"c": validatortest.Status_STATUS_UNKNOWN}, m.SomeStringToStatus)
+}
+
+func TestUnmarshalText_FieldErrors(t *testing.T) {
+	doc := "some_embedded {\n  identifier: \"x\"\n  some_value: \"many\"\n}\nsome_int_rep: [1, -2]\n"
+	err := textproto.UnmarshalTextWith(nicejsonpb.New(nicejsonpb.WithAllErrors()), strings.NewReader(doc), &validatortest.Message3{})
+	require.Error(t, err)
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 2)
+	byPointer := map[string]*textproto.Error{}
+	for _, err := range multi {
+		var located *textproto.Error
+		require.ErrorAs(t, err, &located)
+		byPointer[nicejsonpb.ErrorPointer(err)] = located
+	}
+	require.Contains(t, byPointer["/some_embedded/some_value"].Error(), "line 3, column 15: unparsable field SomeEmbedded.SomeValue: ")
+	require.Equal(t, 5, byPointer["/some_int_rep/1"].Line)
+	require.Equal(t, 19, byPointer["/some_int_rep/1"].Column)
+}
+
+func TestUnmarshalText_UnknownField(t *testing.T) {
+	err := textproto.UnmarshalText(strings.NewReader("some_string: \"x\"\n\nsome_embedded { nope { a: 1 } }"), &validatortest.Message3{})
+	require.Equal(t, nicejsonpb.CodeUnknownField, nicejsonpb.ErrorCode(err))
+	require.Contains(t, err.Error(), "line 3, column 15: unparsable field SomeEmbedded: fields [nope] do not exist")
+}
+
+func TestUnmarshalText_SyntaxErrors(t *testing.T) {
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{"some_string: \"x", "textproto: line 1, column 14: unterminated string"},
+		{"some_string \"x\"", `textproto: line 1, column 13: expected ":" after the field name, found string "x"`},
+		{"some_string: \"x\"\nsome_string: \"y\"", `textproto: line 2, column 1: non-repeated field "some_string" is set more than once`},
+		{"some_embedded { identifier: \"x\"", `textproto: line 1, column 32: expected "}" before the end of the document`},
+		{"some_string_rep: [\"a\" \"b\" 1]", `textproto: line 1, column 27: expected "," or "]" in a list of values, found "1"`},
+		{"some_timestamp { seconds: \"x\" }", `textproto: line 1, column 27: google.protobuf.Timestamp: invalid value for int64 type: "x"`},
+		{"some_string: @", `textproto: line 1, column 14: unexpected character '@'`},
+	} {
+		err := textproto.UnmarshalText(strings.NewReader(tc.doc), &validatortest.Message3{})
+		require.EqualError(t, err, tc.err, tc.doc)
+	}
+}
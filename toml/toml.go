@@ -0,0 +1,69 @@
+// Package toml decodes TOML documents into protocol buffers, so that services with configuration
+// defined in .proto files can read it from TOML. Documents are converted to JSON and decoded by a
+// nicejsonpb.Unmarshaler, so tables decode into messages and maps, arrays into repeated fields, and
+// keys are matched against both the original and the JSON names of fields as they are in JSON:
+//
+//	err := toml.UnmarshalTOML(r, pb)
+//
+// Datetimes become RFC 3339 strings, as google.protobuf.Timestamp fields take them, and inf and nan
+// the "Infinity" and "NaN" strings of float and double fields.
+package toml
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/BurntSushi/toml"
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+)
+
+// UnmarshalTOML decodes the TOML document held in r into pb with the default options.
+func UnmarshalTOML(r io.Reader, pb proto.Message) error {
+	return UnmarshalTOMLWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalTOMLWith decodes the TOML document held in r into pb with the options of u.
+func UnmarshalTOMLWith(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	var doc map[string]interface{}
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	b, err := json.Marshal(jsonValue(doc))
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBytes(b, pb)
+}
+
+// jsonValue replaces the values of v, a decoded TOML value, that encoding/json can't marshal the
+// way the JSON mapping of protocol buffers wants them.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = jsonValue(elem)
+		}
+	case []map[string]interface{}:
+		elems := make([]interface{}, len(v))
+		for i, elem := range v {
+			elems[i] = jsonValue(elem)
+		}
+		return elems
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = jsonValue(elem)
+		}
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN"
+		case math.IsInf(v, 1):
+			return "Infinity"
+		case math.IsInf(v, -1):
+			return "-Infinity"
+		}
+	}
+	return v
+}
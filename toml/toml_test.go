@@ -0,0 +1,58 @@
+package toml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/mwitkow/go-nicejsonpb/toml"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `
+some_string = "hello"
+someInt64 = 9_007_199_254_740_993
+someDouble = -inf
+someStringRep = ["a", "b"]
+someTimestamp = 2024-05-01T10:00:00.5Z
+
+[someEmbedded]
+identifier = "x"
+
+[[someEmbedded.children]]
+identifier = "first"
+
+[[someEmbedded.children]]
+some_value = 2
+
+[someStringToInt64]
+a = 1
+`
+
+func TestUnmarshalTOML(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, toml.UnmarshalTOML(strings.NewReader(config), m))
+	require.Equal(t, "hello", m.SomeString)
+	require.Equal(t, int64(9007199254740993), m.SomeInt64)
+	require.True(t, m.SomeDouble < -1e308)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, int64(1714557600), m.SomeTimestamp.Seconds)
+	require.Equal(t, int32(500000000), m.SomeTimestamp.Nanos)
+	require.Equal(t, "x", m.SomeEmbedded.Identifier)
+	require.Len(t, m.SomeEmbedded.Children, 2)
+	require.Equal(t, int64(2), m.SomeEmbedded.Children[1].SomeValue)
+	require.Equal(t, map[string]int64{"a": 1}, m.SomeStringToInt64)
+}
+
+func TestUnmarshalTOML_Errors(t *testing.T) {
+	err := toml.UnmarshalTOML(strings.NewReader("[someEmbedded]\nsomeValue = true\n"), &validatortest.Message3{})
+	require.Error(t, err)
+	require.Equal(t, "/someEmbedded/someValue", nicejsonpb.ErrorPointer(err))
+
+	err = toml.UnmarshalTOML(strings.NewReader("someString = \n"), &validatortest.Message3{})
+	require.Error(t, err)
+
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	require.NoError(t, toml.UnmarshalTOMLWith(u, strings.NewReader("other = 1\n"), &validatortest.Message3{}))
+}
@@ -0,0 +1,53 @@
+package nicejsonpb
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// readDocument reads the JSON document held in r into inputValue, in UTF-8 as InputEncoding says,
+// checking that nothing follows it if DisallowTrailingData is set.
+func (u *Unmarshaler) readDocument(r io.Reader, inputValue *json.RawMessage) error {
+	if u.InputEncoding == EncodingAuto {
+		var err error
+		if r, err = textReader(u.rawLimitReader(r)); err != nil {
+			return err
+		}
+	}
+	r = u.LimitReader(r)
+	if u.allowsJSONC() {
+		var err error
+		if r, err = u.jsoncReader(r); err != nil {
+			return err
+		}
+	}
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(inputValue); err != nil {
+		return err
+	}
+	if u.DisallowTrailingData {
+		return checkTrailingData(dec, r)
+	}
+	return nil
+}
+
+// checkTrailingData reads what is left of r after the document decoded by dec, failing if it isn't
+// only whitespace.
+func checkTrailingData(dec *json.Decoder, r io.Reader) error {
+	rest := bufio.NewReader(io.MultiReader(dec.Buffered(), r))
+	offset := dec.InputOffset()
+	for {
+		b, err := rest.ReadByte()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if strings.IndexByte(jsonWhitespace, b) < 0 {
+			return codeErrorf(CodeSyntax, "unexpected data after the JSON document at offset %d", offset)
+		}
+		offset++
+	}
+}
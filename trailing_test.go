@@ -0,0 +1,37 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_TrailingDataIgnoredByDefault(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someString": "a"}{"someString": "b"}`, m))
+	require.Equal(t, "a", m.SomeString)
+}
+
+func TestUnmarshal_DisallowTrailingData(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithDisallowTrailingData())
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader("{\"someString\": \"a\"} \n\t"), m))
+	require.Equal(t, "a", m.SomeString)
+
+	for input, offset := range map[string]string{
+		`{"someString": "a"}{"someString": "b"}`: "19",
+		`{"someString": "a"}  garbage`:           "21",
+	} {
+		m := &validatortest.Message3{}
+		err := u.Unmarshal(strings.NewReader(input), m)
+		require.EqualError(t, err, "unexpected data after the JSON document at offset "+offset)
+		require.Empty(t, m.SomeString, "the target is left alone")
+
+		_, err = u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+		require.Error(t, err)
+		require.Error(t, u.UnmarshalBytes([]byte(input), m))
+	}
+}
@@ -0,0 +1,44 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// UnknownFieldAction is what an UnknownFieldPolicy does with an unknown field.
+type UnknownFieldAction int
+
+const (
+	// RejectUnknownField fails the unmarshal, as when unknown fields aren't allowed.
+	RejectUnknownField UnknownFieldAction = iota
+	// DropUnknownField drops the field with a warning, as AllowUnknownFields does.
+	DropUnknownField
+	// KeepUnknownField hands the field to the UnknownFieldSink, or drops it silently if there is
+	// none.
+	KeepUnknownField
+)
+
+// applyUnknownFieldPolicy is checkUnknownFields for an UnknownFieldPolicy, failing with the error
+// built by mismatch for the fields it rejects, which are all that is left of jsonFields then.
+func (d *decodeState) applyUnknownFieldPolicy(jsonFields map[string]json.RawMessage, keys []string, mismatch func() error) error {
+	path := strings.Join(d.path, ".")
+	rejected := false
+	for _, k := range keys {
+		switch d.UnknownFieldPolicy(path, k) {
+		case RejectUnknownField:
+			rejected = true
+			continue
+		case DropUnknownField:
+			d.warn(WarnUnknownField, k, "unknown field dropped")
+		case KeepUnknownField:
+			if d.UnknownFieldSink != nil {
+				d.UnknownFieldSink(path, k, d.sinkValue(jsonFields[k]))
+			}
+		}
+		delete(jsonFields, k)
+	}
+	if rejected {
+		return mismatch()
+	}
+	return nil
+}
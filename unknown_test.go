@@ -0,0 +1,50 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+// toleratedUnderEmbedded drops unknown fields of SomeEmbedded, keeps "x-" ones everywhere and
+// rejects all others.
+func toleratedUnderEmbedded(path string, key string) nicejsonpb.UnknownFieldAction {
+	switch {
+	case strings.HasPrefix(key, "x-"):
+		return nicejsonpb.KeepUnknownField
+	case path == "SomeEmbedded":
+		return nicejsonpb.DropUnknownField
+	}
+	return nicejsonpb.RejectUnknownField
+}
+
+func TestUnmarshal_UnknownFieldPolicy(t *testing.T) {
+	var kept []string
+	u := nicejsonpb.New(
+		nicejsonpb.WithUnknownFieldPolicy(toleratedUnderEmbedded),
+		nicejsonpb.WithUnknownFieldSink(func(path string, key string, raw json.RawMessage) {
+			kept = append(kept, path+":"+key+"="+string(raw))
+		}),
+	)
+	m := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"x-trace": 1, "someEmbedded": {"someValue": 2, "legacy": true}}`), m))
+	require.Equal(t, int64(2), m.SomeEmbedded.SomeValue)
+	require.Equal(t, []string{":x-trace=1"}, kept)
+
+	for _, decode := range []func(string) error{
+		func(input string) error { return u.Unmarshal(strings.NewReader(input), &validatortest.Message3{}) },
+		func(input string) error {
+			_, err := u.UnmarshalDynamic(strings.NewReader(input), message3Descriptor(t))
+			return err
+		},
+	} {
+		err := decode(`{"legacy": true, "x-trace": 1, "someEmbedded": {"legacy": true}}`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fields [legacy] do not exist")
+		require.Equal(t, nicejsonpb.CodeUnknownField, nicejsonpb.ErrorCode(err))
+	}
+}
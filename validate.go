@@ -0,0 +1,103 @@
+package nicejsonpb
+
+import (
+	"io"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// UnmarshalAndValidate unmarshals a JSON object stream into a protocol buffer, and then validates it
+// with its ValidateAll() or Validate() method, as generated by protoc-gen-validate or
+// go-proto-validators, if it has one. protoc-gen-validate errors are converted to the field errors
+// returned for parse failures, with their path, so that callers deal with a single error shape. Several
+// errors, as returned by ValidateAll(), come back in a MultiError.
+func (u *Unmarshaler) UnmarshalAndValidate(r io.Reader, pb proto.Message) error {
+	if err := u.Unmarshal(r, pb); err != nil {
+		return err
+	}
+	return validateMessage(pb)
+}
+
+// UnmarshalAndValidate unmarshals a JSON object stream into a protocol buffer and validates it.
+func UnmarshalAndValidate(r io.Reader, pb proto.Message) error {
+	return new(Unmarshaler).UnmarshalAndValidate(r, pb)
+}
+
+type allValidator interface {
+	ValidateAll() error
+}
+
+type validator interface {
+	Validate() error
+}
+
+// pgvValidationError is implemented by the per-message error types of protoc-gen-validate.
+type pgvValidationError interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// pgvMultiError is implemented by the errors protoc-gen-validate returns from ValidateAll().
+type pgvMultiError interface {
+	AllErrors() []error
+}
+
+func validateMessage(pb proto.Message) error {
+	var err error
+	switch v := pb.(type) {
+	case allValidator:
+		err = v.ValidateAll()
+	case validator:
+		err = v.Validate()
+	}
+	if err == nil {
+		return nil
+	}
+	errs := validationErrors(err)
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return MultiError(errs)
+}
+
+// validationErrors flattens a protoc-gen-validate error into field errors. Other errors are returned
+// as they are.
+func validationErrors(err error) []error {
+	if multi, ok := err.(pgvMultiError); ok {
+		var errs []error
+		for _, err := range multi.AllErrors() {
+			errs = append(errs, validationErrors(err)...)
+		}
+		return errs
+	}
+	verr, ok := err.(pgvValidationError)
+	if !ok {
+		return []error{err}
+	}
+	path := splitValidationField(verr.Field())
+	if verr.Cause() == nil {
+		return []error{&fieldError{fieldStack: path, nestedErr: newCodedError(CodeValidation, verr.Reason())}}
+	}
+	// Nested messages failing validation are reported with the errors of their own fields as cause.
+	var errs []error
+	for _, err := range validationErrors(verr.Cause()) {
+		if fErr, ok := err.(*fieldError); ok {
+			err = &fieldError{fieldStack: append(append([]string{}, path...), fErr.fieldStack...), nestedErr: fErr.nestedErr}
+		} else {
+			err = &fieldError{fieldStack: path, nestedErr: err}
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// splitValidationField converts the field names of protoc-gen-validate, such as "Items[3]", to the
+// path elements of field errors.
+func splitValidationField(field string) []string {
+	if i := strings.IndexByte(field, '['); i > 0 {
+		return []string{field[:i], field[i:]}
+	}
+	return []string{field}
+}
@@ -0,0 +1,72 @@
+package nicejsonpb_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+// pgvError and pgvMultiError mirror the error types generated by protoc-gen-validate.
+type pgvError struct {
+	field  string
+	reason string
+	cause  error
+}
+
+func (e pgvError) Field() string  { return e.field }
+func (e pgvError) Reason() string { return e.reason }
+func (e pgvError) Cause() error   { return e.cause }
+func (e pgvError) Key() bool      { return false }
+func (e pgvError) Error() string  { return fmt.Sprintf("invalid %s: %s", e.field, e.reason) }
+
+type pgvMultiError []error
+
+func (m pgvMultiError) Error() string      { return fmt.Sprintf("%d errors", len(m)) }
+func (m pgvMultiError) AllErrors() []error { return m }
+
+// validatedMessage has a ValidateAll method in the style of protoc-gen-validate.
+type validatedMessage struct {
+	validatortest.Message3
+}
+
+func (m *validatedMessage) UnmarshalJSONPB(u *nicejsonpb.Unmarshaler, b []byte) error {
+	return u.Unmarshal(bytes.NewReader(b), &m.Message3)
+}
+
+func (m *validatedMessage) ValidateAll() error {
+	var errs pgvMultiError
+	if m.SomeString == "" {
+		errs = append(errs, pgvError{field: "SomeString", reason: "value length must be at least 1 runes"})
+	}
+	for i, e := range m.SomeEmbeddedRep {
+		if e.SomeValue < 0 {
+			cause := pgvMultiError{pgvError{field: "SomeValue", reason: "value must be greater than or equal to 0"}}
+			errs = append(errs, pgvError{field: fmt.Sprintf("SomeEmbeddedRep[%d]", i), reason: "embedded message failed validation", cause: cause})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func TestUnmarshalAndValidate_ConvertsValidationErrors(t *testing.T) {
+	err := nicejsonpb.UnmarshalAndValidate(strings.NewReader(`{"someEmbeddedRep": [{}, {"someValue": -1}]}`), &validatedMessage{})
+	var multi nicejsonpb.MultiError
+	require.True(t, errors.As(err, &multi))
+	require.EqualError(t, err, "unparsable field SomeString: value length must be at least 1 runes; "+
+		"unparsable field SomeEmbeddedRep.[1].SomeValue: value must be greater than or equal to 0")
+
+	require.NoError(t, nicejsonpb.UnmarshalAndValidate(strings.NewReader(`{"someString": "x"}`), &validatedMessage{}))
+}
+
+func TestUnmarshalAndValidate_ReturnsParseErrors(t *testing.T) {
+	err := nicejsonpb.UnmarshalAndValidate(strings.NewReader(`{"someString": 1}`), &validatedMessage{})
+	require.EqualError(t, err, "unparsable field SomeString: json: cannot unmarshal number into Go value of type string")
+}
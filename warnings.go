@@ -0,0 +1,75 @@
+package nicejsonpb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// WarningKind classifies the non-fatal issues reported through Unmarshaler.OnWarning.
+type WarningKind int
+
+const (
+	// WarnUnknownField is reported for every unknown field that AllowUnknownFields lets through.
+	WarnUnknownField WarningKind = iota + 1
+	// WarnDeprecatedField is reported when a field marked as deprecated in its .proto is set.
+	WarnDeprecatedField
+	// WarnDuplicateName is reported when a field is set under both its original and JSON name.
+	WarnDuplicateName
+	// WarnUnknownEnumValue is reported for every unknown enum name that IgnoreUnknownEnumValues lets
+	// through.
+	WarnUnknownEnumValue
+	// WarnLossyConversion is reported when a value is changed to fit its field, such as a fractional
+	// number truncated by CoerceFloatsToInts.
+	WarnLossyConversion
+)
+
+// Warning describes a condition that was tolerated while decoding, but likely points at a problem
+// with the client sending the JSON.
+type Warning struct {
+	Kind WarningKind
+	// Path is the path of the field concerned, named the same way as in field errors.
+	Path    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return "field " + w.Path + ": " + w.Message
+}
+
+// warn reports a warning about the field called name within the value being decoded, or about the
+// value itself if name is empty.
+func (d *decodeState) warn(kind WarningKind, name string, format string, args ...interface{}) {
+	if d.OnWarning == nil {
+		return
+	}
+	path := append([]string{}, d.path...)
+	if name != "" {
+		path = append(path, name)
+	}
+	d.OnWarning(Warning{Kind: kind, Path: strings.Join(path, "."), Message: fmt.Sprintf(format, args...)})
+}
+
+// warnIfDeprecated reports a warning if the field with the given number of the message held in target
+// is deprecated.
+func (d *decodeState) warnIfDeprecated(target proto.Message, name string, number int) {
+	if d.OnWarning == nil {
+		return
+	}
+	fd := proto.MessageReflect(target).Descriptor().Fields().ByNumber(protoreflect.FieldNumber(number))
+	if fd != nil {
+		d.warnIfDeprecatedField(fd, name)
+	}
+}
+
+func (d *decodeState) warnIfDeprecatedField(fd protoreflect.FieldDescriptor, name string) {
+	if d.OnWarning == nil {
+		return
+	}
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDeprecated() {
+		d.warn(WarnDeprecatedField, name, "field %s is deprecated", fd.Name())
+	}
+}
@@ -0,0 +1,29 @@
+package nicejsonpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal_ReportsWarnings(t *testing.T) {
+	warnings := []string{}
+	u := &nicejsonpb.Unmarshaler{
+		AllowUnknownFields: true,
+		OnWarning: func(w nicejsonpb.Warning) {
+			warnings = append(warnings, w.String())
+		},
+	}
+	input := `{"someDeprecated": "x", "someEmbedded": {"some_value": 1, "someValue": 2, "someUnknown": 3}}`
+	stuff := &validatortest.Message3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, int64(2), stuff.SomeEmbedded.SomeValue)
+	require.ElementsMatch(t, []string{
+		"field SomeDeprecated: field some_deprecated is deprecated",
+		`field SomeEmbedded.SomeValue: both "some_value" and "someValue" are set, using "someValue"`,
+		"field SomeEmbedded.someUnknown: unknown field dropped",
+	}, warnings)
+}
@@ -0,0 +1,423 @@
+package nicejsonpb
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WellKnownHandler decodes raw, the JSON form of a message with a JSON form of its own, such as a
+// google.protobuf.Timestamp or a google.type.Date, into m with the options of u. m may be generated
+// or dynamic. Errors returned are prefixed with the path of the field being decoded.
+type WellKnownHandler func(u *Unmarshaler, raw json.RawMessage, m protoreflect.Message) error
+
+// wellKnownHandler is a WellKnownHandler given the state of the unmarshal.
+type wellKnownHandler func(d *decodeState, raw json.RawMessage, m protoreflect.Message) error
+
+// wellKnownHandlers decode the messages with a JSON form of their own, keyed by their full name.
+// An Any holds them in its "value" member.
+var wellKnownHandlers = map[protoreflect.FullName]wellKnownHandler{}
+
+func init() {
+	for _, name := range []protoreflect.FullName{
+		"google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue",
+	} {
+		wellKnownHandlers[name] = (*decodeState).unmarshalWrapper
+	}
+	wellKnownHandlers["google.protobuf.Duration"] = func(d *decodeState, raw json.RawMessage, m protoreflect.Message) error {
+		return d.unmarshalSecondsNanos(raw, m, d.parseDuration)
+	}
+	wellKnownHandlers["google.protobuf.Timestamp"] = func(d *decodeState, raw json.RawMessage, m protoreflect.Message) error {
+		return d.unmarshalSecondsNanos(raw, m, d.parseTimestamp)
+	}
+	wellKnownHandlers["google.protobuf.Any"] = (*decodeState).unmarshalAny
+	wellKnownHandlers["google.protobuf.Struct"] = (*decodeState).unmarshalStruct
+	wellKnownHandlers["google.protobuf.Value"] = (*decodeState).unmarshalStruct
+	wellKnownHandlers["google.protobuf.ListValue"] = (*decodeState).unmarshalStruct
+	wellKnownHandlers["google.protobuf.FieldMask"] = (*decodeState).unmarshalFieldMask
+}
+
+// RegisterWellKnownType makes all Unmarshalers decode the messages with the given full name with
+// fn, replacing the built-in handling of the well-known types of google.protobuf, such as
+// "google.protobuf.Duration", or adding types with a JSON form of their own, such as
+// "google.type.Date". It isn't safe to call while decoding, so it belongs in init functions.
+func RegisterWellKnownType(name protoreflect.FullName, fn WellKnownHandler) {
+	wellKnownHandlers[name] = func(d *decodeState, raw json.RawMessage, m protoreflect.Message) error {
+		return fn(d.Unmarshaler, raw, m)
+	}
+}
+
+// LookupWellKnownType returns the handler the messages with the given full name are decoded with,
+// or false if they are decoded field by field. Handlers replacing a built-in one can fall back to it.
+func LookupWellKnownType(name protoreflect.FullName) (WellKnownHandler, bool) {
+	handler, ok := wellKnownHandlers[name]
+	if !ok {
+		return nil, false
+	}
+	return func(u *Unmarshaler, raw json.RawMessage, m protoreflect.Message) error {
+		d := u.newDecodeState()
+		defer d.release()
+		return handler(d, raw, m)
+	}, true
+}
+
+// unmarshalWrapper decodes the JSON form of the wrapper types, that of the value they wrap.
+func (d *decodeState) unmarshalWrapper(raw json.RawMessage, m protoreflect.Message) error {
+	fd := m.Descriptor().Fields().ByName("value")
+	v, err := d.dynamicValue(fd, protoreflect.Value{}, raw)
+	if err == nil {
+		m.Set(fd, v)
+	}
+	return err
+}
+
+// unmarshalSecondsNanos decodes a Duration or a Timestamp into m with parse.
+func (d *decodeState) unmarshalSecondsNanos(raw json.RawMessage, m protoreflect.Message, parse func(json.RawMessage) (int64, int32, error)) error {
+	s, ns, err := parse(raw)
+	if err != nil {
+		return err
+	}
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(s))
+	m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(ns))
+	return nil
+}
+
+// unmarshalFieldMask decodes the JSON form of a google.protobuf.FieldMask, its paths in lowerCamelCase
+// separated by commas.
+func (d *decodeState) unmarshalFieldMask(raw json.RawMessage, m protoreflect.Message) error {
+	var unq string
+	if err := json.Unmarshal(raw, &unq); err != nil {
+		return correctDynamicJsonType(err, "message google.protobuf.FieldMask")
+	}
+	fd := m.Descriptor().Fields().ByName("paths")
+	if !d.MergeInto {
+		m.Clear(fd)
+	}
+	if unq == "" {
+		return nil
+	}
+	paths := m.Mutable(fd).List()
+	for _, path := range strings.Split(unq, ",") {
+		paths.Append(protoreflect.ValueOfString(snakeCase(path)))
+	}
+	return nil
+}
+
+// snakeCase returns the proto name of a lowerCamelCase field path.
+func snakeCase(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// parseDuration parses the JSON string form of a google.protobuf.Duration into seconds and nanos.
+func (d *decodeState) parseDuration(inputValue json.RawMessage) (int64, int32, error) {
+	if d.DurationNumbers && inputValue[0] != '"' {
+		s, ns, err := parseUnitDuration(string(inputValue), "s", numberUnits)
+		if err == errBadDuration {
+			return 0, 0, codeErrorf(CodeBadDuration, "bad Duration: %s isn't a string or a number of seconds", d.echo("%s", "value", string(inputValue)))
+		}
+		return s, ns, d.durationError(err, string(inputValue))
+	}
+	unq, err := strconv.Unquote(string(inputValue))
+	if err != nil {
+		return 0, 0, err
+	}
+	if d.canonicalDurations && !isCanonicalDuration(unq) {
+		return 0, 0, codeErrorf(CodeBadDuration, "bad Duration: %s isn't in the canonical form, seconds with an \"s\" suffix and 0, 3, 6 or 9 fractional digits", d.echo("%q", "string", unq))
+	}
+	if d.protoJSONDurations && !isProtoJSONDuration(unq) {
+		return 0, 0, codeErrorf(CodeBadDuration, "bad Duration: %s isn't a number of seconds with an \"s\" suffix, such as \"1.5s\"", d.echo("%q", "string", unq))
+	}
+	if d.ExtendedDurations {
+		s, ns, err := parseUnitDuration(unq, "", extendedUnits)
+		if err == errBadDuration {
+			return 0, 0, codeErrorf(CodeBadDuration, "bad Duration: %s isn't a duration, such as \"1d12h\" or \"1.5s\"", d.echo("%q", "string", unq))
+		}
+		return s, ns, d.durationError(err, strconv.Quote(unq))
+	}
+	// Durations are written the way time.ParseDuration takes them, but may be longer than a
+	// time.Duration holds.
+	s, ns, err := parseUnitDuration(unq, "", goUnits)
+	if err == errBadDuration {
+		return 0, 0, codeErrorf(CodeBadDuration, "bad Duration: %s isn't a duration", d.echo("%q", "string", unq))
+	}
+	return s, ns, d.durationError(err, strconv.Quote(unq))
+}
+
+// durationError returns the error for a Duration written as value that parseUnitDuration returned
+// err for.
+func (d *decodeState) durationError(err error, value string) error {
+	if err == errDurationOutOfRange {
+		return codeErrorf(CodeOutOfRange, "bad Duration: %s is out of range, expected at most %ds, about 10,000 years", d.echo("%s", "value", value), int64(maxDurationSeconds))
+	}
+	return err
+}
+
+// maxDurationSeconds is the magnitude of the longest google.protobuf.Duration, about 10,000 years.
+const maxDurationSeconds = 315576000000
+
+// numberUnits, goUnits and extendedUnits are the lengths of the units parseUnitDuration takes, in
+// nanoseconds. goUnits are those of time.ParseDuration. Days are always 24 hours and weeks 7 days.
+var (
+	numberUnits = map[string]int64{"s": 1e9}
+	goUnits     = map[string]int64{
+		"ns": 1, "us": 1e3, "µs": 1e3, "μs": 1e3, "ms": 1e6, "s": 1e9, "m": 60e9, "h": 3600e9,
+	}
+	extendedUnits = map[string]int64{
+		"ns": 1, "us": 1e3, "µs": 1e3, "μs": 1e3, "ms": 1e6, "s": 1e9,
+		"m": 60e9, "h": 3600e9, "d": 86400e9, "w": 7 * 86400e9,
+	}
+)
+
+// errBadDuration and errDurationOutOfRange are returned by parseUnitDuration for malformed
+// durations and those longer than a google.protobuf.Duration holds.
+var (
+	errBadDuration        = errors.New("bad duration")
+	errDurationOutOfRange = errors.New("duration out of range")
+)
+
+// parseUnitDuration parses a signed sequence of decimal numbers followed by units, such as "1d12h",
+// into seconds and nanos, without the bounds of time.Duration. unit, if set, is that of a single
+// number with no unit written.
+func parseUnitDuration(s string, unit string, units map[string]int64) (int64, int32, error) {
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	total := new(big.Rat)
+	for first := true; first || s != ""; first = false {
+		n := 0
+		for n < len(s) && (isASCIIDigit(s[n]) || s[n] == '.') {
+			n++
+		}
+		number := s[:n]
+		if number == "" || strings.Count(number, ".") > 1 || number == "." {
+			return 0, 0, errBadDuration
+		}
+		s = s[n:]
+		n = 0
+		for n < len(s) && !isASCIIDigit(s[n]) && s[n] != '.' {
+			n++
+		}
+		name := s[:n]
+		s = s[n:]
+		if name == "" && s == "" && first {
+			// A single number is in unit, or may be 0 with no unit.
+			name = unit
+			if strings.Trim(number, "0.") == "" {
+				name = "s"
+			}
+		}
+		nanos, ok := units[name]
+		if !ok {
+			return 0, 0, errBadDuration
+		}
+		r, _ := new(big.Rat).SetString(number)
+		total.Add(total, r.Mul(r, new(big.Rat).SetInt64(nanos)))
+	}
+	// Nanoseconds beyond the last whole one are dropped, as time.ParseDuration does.
+	ns := new(big.Int).Quo(total.Num(), total.Denom())
+	secs, rem := new(big.Int).QuoRem(ns, big.NewInt(1e9), new(big.Int))
+	if !secs.IsInt64() || secs.Int64() > maxDurationSeconds {
+		return 0, 0, errDurationOutOfRange
+	}
+	if negative {
+		return -secs.Int64(), -int32(rem.Int64()), nil
+	}
+	return secs.Int64(), int32(rem.Int64()), nil
+}
+
+// isProtoJSONDuration reports whether s is a duration as protojson takes them: a number of seconds
+// with an "s" suffix, an optional sign, no leading zeros and at most 9 fractional digits.
+func isProtoJSONDuration(s string) bool {
+	if !strings.HasSuffix(s, "s") {
+		return false
+	}
+	s = s[:len(s)-1]
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	switch {
+	case whole == "":
+		// Only ".5s" may leave out the whole seconds.
+		if !hasFrac {
+			return false
+		}
+	case !isDigits(whole), len(whole) > 1 && whole[0] == '0':
+		return false
+	}
+	return len(frac) <= 9 && (frac == "" || isDigits(frac))
+}
+
+// isCanonicalDuration reports whether s is a duration written the way the proto3 JSON mapping
+// generates them, in seconds with 0, 3, 6 or 9 fractional digits.
+func isCanonicalDuration(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if !strings.HasSuffix(s, "s") {
+		return false
+	}
+	whole, frac, hasFrac := strings.Cut(s[:len(s)-1], ".")
+	if !isDigits(whole) {
+		return false
+	}
+	if !hasFrac {
+		return true
+	}
+	return (len(frac) == 3 || len(frac) == 6 || len(frac) == 9) && isDigits(frac)
+}
+
+// TimestampOffsets controls how Timestamps written with an offset from UTC are treated.
+type TimestampOffsets int
+
+const (
+	// OffsetsToUTC decodes a Timestamp written with any offset to the instant it stands for, the
+	// offset itself being lost. This matches protojson and is the default.
+	OffsetsToUTC TimestampOffsets = iota
+	// OffsetsRejectNonUTC fails the unmarshal on Timestamps not written in UTC with a "Z" suffix, for
+	// APIs that only take canonical timestamps.
+	OffsetsRejectNonUTC
+)
+
+// EpochTimestamps controls whether Timestamps may be written as numbers of seconds or milliseconds
+// since the Unix epoch, such as those Date.now() gives in JavaScript.
+type EpochTimestamps int
+
+const (
+	// EpochNone only takes RFC 3339 strings, as protojson does. It is the default.
+	EpochNone EpochTimestamps = iota
+	// EpochSeconds takes numbers as seconds since the epoch.
+	EpochSeconds
+	// EpochMillis takes numbers as milliseconds since the epoch.
+	EpochMillis
+	// EpochAuto takes numbers of at least 1e11 in magnitude, which as seconds would be after the
+	// year 5000, as milliseconds since the epoch, and smaller ones as seconds.
+	EpochAuto
+)
+
+// units names the unit of the numbers e takes.
+func (e EpochTimestamps) units() string {
+	switch e {
+	case EpochSeconds:
+		return "seconds"
+	case EpochMillis:
+		return "milliseconds"
+	}
+	return "seconds or milliseconds"
+}
+
+// parseEpochTimestamp parses a number of seconds or milliseconds since the epoch, as set by
+// EpochTimestamps, into the seconds and nanos of a google.protobuf.Timestamp.
+func (d *decodeState) parseEpochTimestamp(inputValue json.RawMessage) (int64, int32, error) {
+	unit := d.EpochTimestamps
+	f, err := strconv.ParseFloat(string(inputValue), 64)
+	if err != nil {
+		return 0, 0, codeErrorf(CodeTypeMismatch, "bad Timestamp: %s is neither an RFC 3339 string nor a number of %s since the epoch", d.echo("%s", "value", string(inputValue)), unit.units())
+	}
+	if unit == EpochAuto {
+		unit = EpochSeconds
+		if math.Abs(f) >= 1e11 {
+			unit = EpochMillis
+		}
+	}
+	secs := f
+	if unit == EpochMillis {
+		secs /= 1e3
+	}
+	if secs < float64(minTimestamp.Unix()) || secs >= float64(maxTimestamp.Unix()+1) {
+		return 0, 0, codeErrorf(CodeOutOfRange, "bad Timestamp: timestamp out of range, %s %s since the epoch isn't between 0001-01-01T00:00:00Z and 9999-12-31T23:59:59.999999999Z", d.echo("%s", "number", string(inputValue)), unit.units())
+	}
+	// Integers are converted exactly.
+	if i, err := strconv.ParseInt(string(inputValue), 10, 64); err == nil {
+		if unit == EpochMillis {
+			t := time.UnixMilli(i)
+			return t.Unix(), int32(t.Nanosecond()), nil
+		}
+		return i, 0, nil
+	}
+	whole := math.Floor(secs)
+	nanos := math.Round((secs - whole) * 1e9)
+	if nanos >= 1e9 {
+		whole, nanos = whole+1, nanos-1e9
+	}
+	return int64(whole), int32(nanos), nil
+}
+
+// parseTimestamp parses the RFC 3339 JSON string form of a google.protobuf.Timestamp into seconds and nanos.
+func (d *decodeState) parseTimestamp(inputValue json.RawMessage) (int64, int32, error) {
+	if d.EpochTimestamps != EpochNone && inputValue[0] != '"' {
+		return d.parseEpochTimestamp(inputValue)
+	}
+	unq, err := strconv.Unquote(string(inputValue))
+	if err != nil {
+		return 0, 0, err
+	}
+	if d.canonicalTimestamps && !isCanonicalTimestamp(unq) {
+		return 0, 0, codeErrorf(CodeBadTimestamp, "bad Timestamp: %s isn't in the canonical form, in UTC (\"Z\") with 0, 3, 6 or 9 fractional digits", d.echo("%q", "string", unq))
+	}
+	t, err := time.Parse(time.RFC3339Nano, unq)
+	if err != nil {
+		if !d.echoesVerbatim(unq) {
+			return 0, 0, codeErrorf(CodeBadTimestamp, "bad Timestamp: %s isn't an RFC 3339 timestamp", d.echo("%q", "string", unq))
+		}
+		return 0, 0, codeErrorf(CodeBadTimestamp, "bad Timestamp: %v", err)
+	}
+	// time.Parse takes any number of fractional digits and drops those past the nanosecond.
+	if fractionalDigits(unq) > 9 {
+		return 0, 0, codeErrorf(CodeBadTimestamp, "bad Timestamp: %s has more than 9 fractional digits", d.echo("%q", "string", unq))
+	}
+	if d.TimestampOffsets == OffsetsRejectNonUTC && !strings.HasSuffix(unq, "Z") {
+		// RFC 3339 offsets other than "Z" are written as +hh:mm or -hh:mm.
+		offset := unq[len(unq)-len("+00:00"):]
+		return 0, 0, codeErrorf(CodeBadTimestamp, "bad Timestamp: offset %s isn't UTC, expected a timestamp ending in \"Z\"", d.echo("%q", "string", offset))
+	}
+	if t.Before(minTimestamp) || t.After(maxTimestamp) {
+		return 0, 0, codeErrorf(CodeOutOfRange, "bad Timestamp: timestamp out of range, %s isn't between 0001-01-01T00:00:00Z and 9999-12-31T23:59:59.999999999Z", d.echo("%q", "string", unq))
+	}
+	// Nanos count forward from the second, also before 1970.
+	return t.Unix(), int32(t.Nanosecond()), nil
+}
+
+// minTimestamp and maxTimestamp are the first and last instants a google.protobuf.Timestamp may hold.
+var (
+	minTimestamp = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTimestamp = time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+)
+
+// fractionalDigits returns the number of fractional digits of the seconds of s, an RFC 3339 timestamp.
+func fractionalDigits(s string) int {
+	frac := s[len("2006-01-02T15:04:05"):]
+	if !strings.HasPrefix(frac, ".") {
+		return 0
+	}
+	return len(frac) - len(strings.TrimLeft(frac[1:], "0123456789")) - 1
+}
+
+// isCanonicalTimestamp reports whether s is a timestamp written the way the proto3 JSON mapping
+// generates them, normalized to UTC and with 0, 3, 6 or 9 fractional digits.
+func isCanonicalTimestamp(s string) bool {
+	if !strings.HasSuffix(s, "Z") || len(s) < len("2006-01-02T15:04:05Z") {
+		return false
+	}
+	switch frac := s[len("2006-01-02T15:04:05") : len(s)-1]; len(frac) {
+	case 0:
+		return true
+	case 4, 7, 10:
+		return frac[0] == '.'
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package nicejsonpb_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func init() {
+	// Prices are written as "12.50 EUR".
+	nicejsonpb.RegisterWellKnownType("validatortest.Price", func(u *nicejsonpb.Unmarshaler, raw json.RawMessage, m protoreflect.Message) error {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		var units, cents int64
+		var currency string
+		if _, err := fmt.Sscanf(s, "%d.%02d %s", &units, &cents, &currency); err != nil {
+			return fmt.Errorf("bad price %q", s)
+		}
+		fields := m.Descriptor().Fields()
+		m.Set(fields.ByName("currency_code"), protoreflect.ValueOfString(currency))
+		m.Set(fields.ByName("units"), protoreflect.ValueOfInt64(units))
+		m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(cents*1e7)))
+		return nil
+	})
+}
+
+func TestRegisterWellKnownType(t *testing.T) {
+	m := &validatortest.PriceList{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{
+		"total": "12.50 EUR",
+		"items": ["10.00 EUR", "2.50 EUR"],
+		"extra": {"@type": "type.googleapis.com/validatortest.Price", "value": "1.00 USD"}
+	}`, m))
+	require.Equal(t, "EUR", m.Total.CurrencyCode)
+	require.Equal(t, int64(12), m.Total.Units)
+	require.Equal(t, int32(5e8), m.Total.Nanos)
+	require.Equal(t, int64(2), m.Items[1].Units)
+	extra, err := m.Extra.UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, "USD", extra.(*validatortest.Price).CurrencyCode)
+
+	err = nicejsonpb.UnmarshalString(`{"items": ["10.00 EUR", "ten"]}`, m)
+	require.EqualError(t, err, `unparsable field Items.[1]: bad price "ten"`)
+
+	desc := validatortest.File_nicejsonpb_wkt_proto.Messages().ByName("PriceList")
+	dyn, err := nicejsonpb.UnmarshalDynamic(strings.NewReader(`{"total": "3.00 GBP"}`), desc)
+	require.NoError(t, err)
+	total := dyn.Get(desc.Fields().ByName("total")).Message()
+	require.Equal(t, "GBP", total.Get(total.Descriptor().Fields().ByName("currency_code")).String())
+}
+
+func TestLookupWellKnownType(t *testing.T) {
+	handler, ok := nicejsonpb.LookupWellKnownType("google.protobuf.Duration")
+	require.True(t, ok)
+	d := &durationpb.Duration{}
+	require.NoError(t, handler(nicejsonpb.New(), json.RawMessage(`"1.5s"`), d.ProtoReflect()))
+	require.Equal(t, 1500*time.Millisecond, d.AsDuration())
+
+	_, ok = nicejsonpb.LookupWellKnownType("validatortest.Message3")
+	require.False(t, ok)
+}
+
+func TestUnmarshal_FieldMask(t *testing.T) {
+	handler, ok := nicejsonpb.LookupWellKnownType("google.protobuf.FieldMask")
+	require.True(t, ok)
+	mask := &fieldmaskpb.FieldMask{}
+	require.NoError(t, handler(nicejsonpb.New(), json.RawMessage(`"someString,someEmbedded.someValue"`), mask.ProtoReflect()))
+	require.Equal(t, []string{"some_string", "some_embedded.some_value"}, mask.Paths)
+}
+
+func TestUnmarshal_TimestampRange(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "1969-12-31T23:59:59.25Z"}`, m))
+	require.Equal(t, int64(-1), m.SomeTimestamp.Seconds)
+	require.Equal(t, int32(250000000), m.SomeTimestamp.Nanos)
+	require.NoError(t, m.SomeTimestamp.CheckValid())
+
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "0001-01-01T00:00:00Z"}`, m))
+	require.Equal(t, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), m.SomeTimestamp.AsTime())
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "9999-12-31T23:59:59.999999999Z"}`, m))
+	require.NoError(t, m.SomeTimestamp.CheckValid())
+
+	for _, ts := range []string{"0001-01-01T00:00:00+01:00", "9999-12-31T23:59:59-00:01"} {
+		err := nicejsonpb.UnmarshalString(`{"someTimestamp": "`+ts+`"}`, m)
+		require.EqualError(t, err, `unparsable field SomeTimestamp: bad Timestamp: timestamp out of range, "`+ts+`" isn't between 0001-01-01T00:00:00Z and 9999-12-31T23:59:59.999999999Z`)
+		require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+		require.Equal(t, "/someTimestamp", nicejsonpb.ErrorPointer(err))
+	}
+}
+
+func TestUnmarshal_TimestampFractionalDigits(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "2024-03-01T10:00:00.123456789+02:00"}`, m))
+	require.Equal(t, int32(123456789), m.SomeTimestamp.Nanos)
+	for _, ts := range []string{"2024-03-01T10:00:00.1234567891Z", "2024-03-01T10:00:00.0000000000+02:00"} {
+		err := nicejsonpb.UnmarshalString(`{"someTimestamp": "`+ts+`"}`, m)
+		require.EqualError(t, err, `unparsable field SomeTimestamp: bad Timestamp: "`+ts+`" has more than 9 fractional digits`)
+		require.Equal(t, nicejsonpb.CodeBadTimestamp, nicejsonpb.ErrorCode(err))
+	}
+}
+
+func TestUnmarshal_TimestampOffsets(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someTimestamp": "2024-03-01T10:00:00+02:00"}`, m))
+	require.Equal(t, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), m.SomeTimestamp.AsTime())
+
+	u := nicejsonpb.New(nicejsonpb.WithTimestampOffsets(nicejsonpb.OffsetsRejectNonUTC))
+	require.NoError(t, u.UnmarshalBytes([]byte(`{"someTimestamp": "2024-03-01T10:00:00.5Z"}`), m))
+	for _, offset := range []string{"+02:00", "+00:00", "-07:30"} {
+		err := u.UnmarshalBytes([]byte(`{"someTimestamp": "2024-03-01T10:00:00`+offset+`"}`), m)
+		require.EqualError(t, err, `unparsable field SomeTimestamp: bad Timestamp: offset "`+offset+`" isn't UTC, expected a timestamp ending in "Z"`)
+		require.Equal(t, nicejsonpb.CodeBadTimestamp, nicejsonpb.ErrorCode(err))
+	}
+}
+
+func TestUnmarshal_EpochTimestamps(t *testing.T) {
+	for _, tc := range []struct {
+		unit nicejsonpb.EpochTimestamps
+		doc  string
+		want time.Time
+	}{
+		{nicejsonpb.EpochSeconds, `1709280000`, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{nicejsonpb.EpochSeconds, `1709280000.25`, time.Date(2024, 3, 1, 8, 0, 0, 25e7, time.UTC)},
+		{nicejsonpb.EpochSeconds, `-1.5`, time.Date(1969, 12, 31, 23, 59, 58, 5e8, time.UTC)},
+		{nicejsonpb.EpochMillis, `1709280000123`, time.Date(2024, 3, 1, 8, 0, 0, 123e6, time.UTC)},
+		{nicejsonpb.EpochMillis, `-1`, time.Date(1969, 12, 31, 23, 59, 59, 999e6, time.UTC)},
+		{nicejsonpb.EpochAuto, `1709280000123`, time.Date(2024, 3, 1, 8, 0, 0, 123e6, time.UTC)},
+		{nicejsonpb.EpochAuto, `1709280000`, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{nicejsonpb.EpochAuto, `"2024-03-01T08:00:00Z"`, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)},
+	} {
+		m := &validatortest.Message3{}
+		u := nicejsonpb.New(nicejsonpb.WithEpochTimestamps(tc.unit))
+		require.NoError(t, u.UnmarshalBytes([]byte(`{"someTimestamp": `+tc.doc+`}`), m), tc.doc)
+		require.Equal(t, tc.want, m.SomeTimestamp.AsTime(), tc.doc)
+		require.NoError(t, m.SomeTimestamp.CheckValid())
+	}
+
+	u := nicejsonpb.New(nicejsonpb.WithEpochTimestamps(nicejsonpb.EpochMillis))
+	err := u.UnmarshalBytes([]byte(`{"someTimestamp": true}`), &validatortest.Message3{})
+	require.EqualError(t, err, "unparsable field SomeTimestamp: bad Timestamp: true is neither an RFC 3339 string nor a number of milliseconds since the epoch")
+	err = u.UnmarshalBytes([]byte(`{"someTimestamp": 1e300}`), &validatortest.Message3{})
+	require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+	require.Contains(t, err.Error(), "1e300 milliseconds since the epoch isn't between")
+	require.Error(t, nicejsonpb.UnmarshalString(`{"someTimestamp": 1709280000}`, &validatortest.Message3{}))
+}
+
+func TestUnmarshal_LenientDurations(t *testing.T) {
+	u := nicejsonpb.New(nicejsonpb.WithDurationNumbers(), nicejsonpb.WithExtendedDurations())
+	for doc, want := range map[string]time.Duration{
+		`90`:             90 * time.Second,
+		`-1.5`:           -1500 * time.Millisecond,
+		`"1d12h"`:        36 * time.Hour,
+		`"2w"`:           14 * 24 * time.Hour,
+		`"1.5h30m"`:      2 * time.Hour,
+		`"-0.5ms"`:       -500 * time.Microsecond,
+		`"0"`:            0,
+		`"1.000000001s"`: time.Second + 1,
+	} {
+		m := &validatortest.Message3{}
+		require.NoError(t, u.UnmarshalBytes([]byte(`{"someDuration": `+doc+`}`), m), doc)
+		require.Equal(t, want, m.SomeDuration.AsDuration(), doc)
+		require.NoError(t, m.SomeDuration.CheckValid(), doc)
+	}
+
+	m := &validatortest.Message3{}
+	require.NoError(t, u.UnmarshalBytes([]byte(`{"someDuration": "3652500d"}`), m))
+	require.Equal(t, int64(315576000000), m.SomeDuration.Seconds)
+	err := u.UnmarshalBytes([]byte(`{"someDuration": "3652501d"}`), m)
+	require.EqualError(t, err, `unparsable field SomeDuration: bad Duration: "3652501d" is out of range, expected at most 315576000000s, about 10,000 years`)
+	require.Equal(t, nicejsonpb.CodeOutOfRange, nicejsonpb.ErrorCode(err))
+	err = u.UnmarshalBytes([]byte(`{"someDuration": "1y"}`), m)
+	require.EqualError(t, err, `unparsable field SomeDuration: bad Duration: "1y" isn't a duration, such as "1d12h" or "1.5s"`)
+	err = u.UnmarshalBytes([]byte(`{"someDuration": true}`), m)
+	require.EqualError(t, err, `unparsable field SomeDuration: bad Duration: true isn't a string or a number of seconds`)
+
+	require.Error(t, nicejsonpb.UnmarshalString(`{"someDuration": "1d"}`, m))
+	// Longer than a time.Duration, about 292 years, holds.
+	require.NoError(t, nicejsonpb.UnmarshalString(`{"someDuration": "3000000h"}`, m))
+	require.Equal(t, int64(10800000000), m.SomeDuration.Seconds)
+	require.Error(t, nicejsonpb.UnmarshalString(`{"someDuration": 90}`, m))
+}
+
+func TestUnmarshal_StrictConformanceDurations(t *testing.T) {
+	strict := nicejsonpb.New(nicejsonpb.WithProfile(nicejsonpb.ProfileStrictConformance))
+	m := &validatortest.Message3{}
+	require.NoError(t, strict.UnmarshalBytes([]byte(`{"someDuration": "1.500s"}`), m))
+	err := strict.UnmarshalBytes([]byte(`{"someDuration": "1m"}`), m)
+	require.EqualError(t, err, `unparsable field SomeDuration: bad Duration: "1m" isn't in the canonical form, seconds with an "s" suffix and 0, 3, 6 or 9 fractional digits`)
+	require.Error(t, strict.UnmarshalBytes([]byte(`{"someDuration": "1.5s"}`), m))
+
+	extended := strict.With(nicejsonpb.WithExtendedDurations())
+	require.NoError(t, extended.UnmarshalBytes([]byte(`{"someDuration": "1d"}`), m))
+}
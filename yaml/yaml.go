@@ -0,0 +1,265 @@
+// Package yaml decodes YAML documents, such as Kubernetes-style configuration files, into protocol
+// buffers. Documents are converted to JSON and decoded by a nicejsonpb.Unmarshaler, so fields are
+// decoded as from JSON, and field errors give the line and column of the value in the YAML:
+//
+//	err := yaml.UnmarshalYAML(r, pb)
+//
+// Integers may be written in any base YAML allows, .inf and .nan become the "Infinity" and "NaN"
+// strings float and double fields take in JSON, and aliases and merge keys ("<<") are expanded.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mwitkow/go-nicejsonpb"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// maxNodes is how many values a document may hold once its aliases are expanded, so that a small
+// document can't make for a huge one.
+const maxNodes = 1 << 20
+
+// UnmarshalYAML decodes the first YAML document held in r into pb with the default options.
+func UnmarshalYAML(r io.Reader, pb proto.Message) error {
+	return UnmarshalYAMLWith(new(nicejsonpb.Unmarshaler), r, pb)
+}
+
+// UnmarshalYAMLWith decodes the first YAML document held in r into pb with the options of u.
+// Errors about fields are returned as an *Error, or a nicejsonpb.MultiError of them.
+func UnmarshalYAMLWith(u *nicejsonpb.Unmarshaler, r io.Reader, pb proto.Message) error {
+	var doc yamlv3.Node
+	if err := yamlv3.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return err
+	}
+	c := &converter{positions: map[string]position{}}
+	if err := c.convert(&doc, ""); err != nil {
+		return err
+	}
+	return c.locate(u.UnmarshalBytes(c.out.Bytes(), pb))
+}
+
+// Error is an error about the field of a YAML document at Line and Column, both starting at 1.
+type Error struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Err)
+}
+
+// Unwrap gives errors.Is, errors.As and the functions of nicejsonpb, such as ErrorCode and
+// ErrorPointer, access to the error of the field.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type position struct {
+	line, column int
+}
+
+// converter converts a YAML document to JSON, recording where every value is in the YAML by its
+// JSON Pointer.
+type converter struct {
+	out       bytes.Buffer
+	positions map[string]position
+	nodes     int
+}
+
+func (c *converter) convert(n *yamlv3.Node, pointer string) error {
+	c.nodes++
+	if c.nodes > maxNodes {
+		return fmt.Errorf("yaml: more than %d values once aliases are expanded", maxNodes)
+	}
+	if _, ok := c.positions[pointer]; !ok {
+		c.positions[pointer] = position{n.Line, n.Column}
+	}
+	switch n.Kind {
+	case 0:
+		// An empty document.
+		c.out.WriteString("{}")
+	case yamlv3.DocumentNode:
+		return c.convert(n.Content[0], pointer)
+	case yamlv3.AliasNode:
+		return c.convert(n.Alias, pointer)
+	case yamlv3.MappingNode:
+		entries, err := c.mappingEntries(n)
+		if err != nil {
+			return err
+		}
+		c.out.WriteByte('{')
+		for i, entry := range entries {
+			if i > 0 {
+				c.out.WriteByte(',')
+			}
+			writeString(&c.out, entry.key.Value)
+			c.out.WriteByte(':')
+			if err := c.convert(entry.value, pointer+"/"+pointerEscaper.Replace(entry.key.Value)); err != nil {
+				return err
+			}
+		}
+		c.out.WriteByte('}')
+	case yamlv3.SequenceNode:
+		c.out.WriteByte('[')
+		for i, elem := range n.Content {
+			if i > 0 {
+				c.out.WriteByte(',')
+			}
+			if err := c.convert(elem, pointer+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+		c.out.WriteByte(']')
+	case yamlv3.ScalarNode:
+		return c.scalar(n)
+	}
+	return nil
+}
+
+// entry is a key of a mapping and its value.
+type entry struct {
+	key, value *yamlv3.Node
+}
+
+// mappingEntries returns the entries of the mapping n, with those of the mappings merged into it by
+// "<<" keys in place of these. Keys set in n win over merged ones, and keys of mappings merged first
+// over those of mappings merged later.
+func (c *converter) mappingEntries(n *yamlv3.Node) ([]entry, error) {
+	var entries, merged []entry
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Kind != yamlv3.ScalarNode {
+			return nil, fmt.Errorf("yaml: line %d: mapping keys must be scalars", key.Line)
+		}
+		if key.ShortTag() != "!!merge" {
+			entries = append(entries, entry{key, value})
+			continue
+		}
+		sources := []*yamlv3.Node{value}
+		if resolveAlias(value).Kind == yamlv3.SequenceNode {
+			sources = resolveAlias(value).Content
+		}
+		for _, source := range sources {
+			c.nodes++
+			if c.nodes > maxNodes {
+				return nil, fmt.Errorf("yaml: more than %d values once aliases are expanded", maxNodes)
+			}
+			if source = resolveAlias(source); source.Kind != yamlv3.MappingNode {
+				return nil, fmt.Errorf("yaml: line %d: only mappings can be merged", source.Line)
+			}
+			sourceEntries, err := c.mappingEntries(source)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, sourceEntries...)
+		}
+	}
+	set := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		set[e.key.Value] = true
+	}
+	for _, e := range merged {
+		if !set[e.key.Value] {
+			set[e.key.Value] = true
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// resolveAlias returns the node n stands for if it is an alias, and n otherwise.
+func resolveAlias(n *yamlv3.Node) *yamlv3.Node {
+	for n.Kind == yamlv3.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// scalar writes the JSON form of the YAML scalar n.
+func (c *converter) scalar(n *yamlv3.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		c.out.WriteString("null")
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return err
+		}
+		c.out.WriteString(strconv.FormatBool(b))
+	case "!!int":
+		i, ok := new(big.Int).SetString(n.Value, 0)
+		if !ok {
+			return fmt.Errorf("yaml: line %d: bad integer %q", n.Line, n.Value)
+		}
+		c.out.WriteString(i.String())
+	case "!!float":
+		switch strings.ToLower(n.Value) {
+		case ".inf", "+.inf":
+			writeString(&c.out, "Infinity")
+			return nil
+		case "-.inf":
+			writeString(&c.out, "-Infinity")
+			return nil
+		case ".nan":
+			writeString(&c.out, "NaN")
+			return nil
+		}
+		if json.Valid([]byte(n.Value)) {
+			c.out.WriteString(n.Value)
+			return nil
+		}
+		f, err := strconv.ParseFloat(n.Value, 64)
+		if err != nil {
+			return fmt.Errorf("yaml: line %d: bad number %q", n.Line, n.Value)
+		}
+		c.out.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	case "!!binary":
+		writeString(&c.out, strings.Join(strings.Fields(n.Value), ""))
+	default:
+		writeString(&c.out, n.Value)
+	}
+	return nil
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	b.Truncate(b.Len() - 1) // Encode ends with a newline.
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// locate adds the position in the YAML of the value each field error is about to err.
+func (c *converter) locate(err error) error {
+	var multi nicejsonpb.MultiError
+	if errors.As(err, &multi) {
+		located := make(nicejsonpb.MultiError, len(multi))
+		for i, err := range multi {
+			located[i] = c.locate(err)
+		}
+		return located
+	}
+	pointer := nicejsonpb.ErrorPointer(err)
+	if err == nil || pointer == "" {
+		return err
+	}
+	for {
+		if pos, ok := c.positions[pointer]; ok {
+			return &Error{Line: pos.line, Column: pos.column, Err: err}
+		}
+		slash := strings.LastIndexByte(pointer, '/')
+		if slash < 0 {
+			return err
+		}
+		pointer = pointer[:slash]
+	}
+}
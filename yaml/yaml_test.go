@@ -0,0 +1,98 @@
+package yaml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mwitkow/go-nicejsonpb"
+	"github.com/mwitkow/go-nicejsonpb/test"
+	"github.com/mwitkow/go-nicejsonpb/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+const config = `# A configuration.
+someString: hello
+some_int32: 0x1F
+someDouble: .inf
+someBool: true
+someStringRep:
+  - a
+  - "b"
+someEmbedded: &embedded
+  identifier: x
+  someValue: 1_000
+someEmbeddedRep:
+  - *embedded
+someInt32ToString:
+  1: one
+someTimestamp: 2024-05-01T10:00:00Z
+someBytes: !!binary |
+  aGVs
+  bG8=
+`
+
+func TestUnmarshalYAML(t *testing.T) {
+	m := &validatortest.Message3{}
+	require.NoError(t, yaml.UnmarshalYAML(strings.NewReader(config), m))
+	require.Equal(t, "hello", m.SomeString)
+	require.Equal(t, int32(31), m.SomeInt32)
+	require.True(t, m.SomeDouble > 1e308)
+	require.True(t, m.SomeBool)
+	require.Equal(t, []string{"a", "b"}, m.SomeStringRep)
+	require.Equal(t, int64(1000), m.SomeEmbedded.SomeValue)
+	require.Equal(t, "x", m.SomeEmbeddedRep[0].Identifier)
+	require.Equal(t, map[int32]string{1: "one"}, m.SomeInt32ToString)
+	require.Equal(t, int64(1714557600), m.SomeTimestamp.Seconds)
+	require.Equal(t, []byte("hello"), m.SomeBytes)
+
+	require.NoError(t, yaml.UnmarshalYAML(strings.NewReader(""), &validatortest.Message3{}))
+}
+
+func TestUnmarshalYAML_MergeKeys(t *testing.T) {
+	input := `
+base: &base
+  identifier: base
+  someValue: 1
+extra: &extra
+  someValue: 2
+  children: [{identifier: c}]
+someEmbedded:
+  <<: *base
+  identifier: own
+someEmbeddedRep:
+  - <<: [*extra, *base]
+  - {<<: *base, someValue: 3}
+`
+	u := nicejsonpb.New(nicejsonpb.WithAllowUnknownFields())
+	m := &validatortest.Message3{}
+	require.NoError(t, yaml.UnmarshalYAMLWith(u, strings.NewReader(input), m))
+	require.Equal(t, "own", m.SomeEmbedded.Identifier)
+	require.Equal(t, int64(1), m.SomeEmbedded.SomeValue)
+	require.Equal(t, "base", m.SomeEmbeddedRep[0].Identifier)
+	require.Equal(t, int64(2), m.SomeEmbeddedRep[0].SomeValue)
+	require.Equal(t, "c", m.SomeEmbeddedRep[0].Children[0].Identifier)
+	require.Equal(t, int64(3), m.SomeEmbeddedRep[1].SomeValue)
+
+	err := yaml.UnmarshalYAML(strings.NewReader("someEmbedded:\n  <<: 3\n"), m)
+	require.EqualError(t, err, "yaml: line 2: only mappings can be merged")
+}
+
+func TestUnmarshalYAML_ErrorLines(t *testing.T) {
+	input := "someString: ok\nsomeEmbedded:\n  children:\n    - identifier: a\n    - someValue: nope\n"
+	err := yaml.UnmarshalYAML(strings.NewReader(input), &validatortest.Message3{})
+	require.EqualError(t, err, "line 5, column 18: unparsable field SomeEmbedded.Children.[1].SomeValue: invalid character 'o' in literal null (expecting 'u') while looking for an integer in a string")
+	var yErr *yaml.Error
+	require.ErrorAs(t, err, &yErr)
+	require.Equal(t, 5, yErr.Line)
+	require.Equal(t, "/someEmbedded/children/1/someValue", nicejsonpb.ErrorPointer(err))
+
+	u := nicejsonpb.New(nicejsonpb.WithAllErrors())
+	err = yaml.UnmarshalYAMLWith(u, strings.NewReader("someInt32: x\nsomeBool: 3\nsomeStatus: bad\n"), &validatortest.Message3{})
+	var multi nicejsonpb.MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi, 3)
+	for i, err := range multi {
+		require.ErrorAs(t, err, &yErr)
+		require.Equal(t, i+1, yErr.Line)
+	}
+}
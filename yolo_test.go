@@ -1,8 +1,11 @@
 package nicejsonpb_test
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/mwitkow/go-nicejsonpb"
 	"github.com/mwitkow/go-nicejsonpb/test"
 	"github.com/stretchr/testify/require"
@@ -40,5 +43,90 @@ func TestUnmarshal_UnknownFieldErrors(t *testing.T) {
 	input := `{"someEmbedded": {"someValue": 3, "someUnknown": 1, "anotherUnknown": "foo"}}`
 	stuff := &validatortest.ValidatorMessage3{}
 	err := nicejsonpb.UnmarshalString(input, stuff)
-	require.EqualError(t, err, "unparsable field SomeEmbedded: fields [someUnknown anotherUnknown] do not exist in set of known fields [identifier someValue]")
+	require.EqualError(t, err, "unparsable field SomeEmbedded: fields [anotherUnknown someUnknown] do not exist in set of known fields [Identifier/identifier SomeValue/someValue]")
+}
+
+func TestUnmarshalBytes_DecodesWithoutReader(t *testing.T) {
+	input := []byte(` {"someString": "foo", "someIntRep": [1, 2], "someEmbedded": {"someValue": "3"}} `)
+	stuff := &validatortest.ValidatorMessage3{}
+	require.NoError(t, nicejsonpb.UnmarshalBytes(input, stuff))
+	require.Equal(t, "foo", stuff.SomeString)
+	require.Equal(t, []uint32{1, 2}, stuff.SomeIntRep)
+	require.Equal(t, int64(3), stuff.SomeEmbedded.SomeValue)
+}
+
+func TestUnmarshalBytes_FindsErrorsInNested(t *testing.T) {
+	input := []byte(`{"someEmbedded": {"identifier": 3.1}}`)
+	stuff := &validatortest.ValidatorMessage3{}
+	err := nicejsonpb.UnmarshalBytes(input, stuff)
+	require.EqualError(t, err, "unparsable field SomeEmbedded.Identifier: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshalBytes_EmptyInput(t *testing.T) {
+	stuff := &validatortest.ValidatorMessage3{}
+	err := nicejsonpb.UnmarshalBytes([]byte("  \n"), stuff)
+	require.EqualError(t, err, "unexpected EOF")
+}
+
+// upperCaseMessage decodes itself from a bare JSON string, upper-casing it on the way.
+type upperCaseMessage struct {
+	validatortest.ValidatorMessage3_Embedded
+}
+
+func (m *upperCaseMessage) UnmarshalJSONPB(u *nicejsonpb.Unmarshaler, b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	m.Identifier = strings.ToUpper(s)
+	return nil
+}
+
+func TestUnmarshal_DelegatesToJSONPBUnmarshaler(t *testing.T) {
+	stuff := &upperCaseMessage{}
+	require.NoError(t, nicejsonpb.UnmarshalString(`"foo"`, stuff))
+	require.Equal(t, "FOO", stuff.Identifier)
+}
+
+func TestUnmarshal_WrapsJSONPBUnmarshalerErrors(t *testing.T) {
+	input := `["foo", 3]`
+	err := nicejsonpb.DecodeArray(json.NewDecoder(strings.NewReader(input)), func() proto.Message {
+		return &upperCaseMessage{}
+	}, func(proto.Message) error {
+		return nil
+	})
+	require.EqualError(t, err, "unparsable field [1]: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshal_UsesRegisteredTypeHandler(t *testing.T) {
+	u := &nicejsonpb.Unmarshaler{}
+	u.RegisterTypeHandler("validatortest.ValidatorMessage3.Embedded", func(raw json.RawMessage, target proto.Message) error {
+		return json.Unmarshal(raw, &target.(*validatortest.ValidatorMessage3_Embedded).Identifier)
+	})
+	stuff := &validatortest.ValidatorMessage3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(`{"someEmbedded": "foo"}`), stuff))
+	require.Equal(t, "foo", stuff.SomeEmbedded.Identifier)
+
+	err := u.Unmarshal(strings.NewReader(`{"someEmbeddedRep": ["foo", 3]}`), stuff)
+	require.EqualError(t, err, "unparsable field SomeEmbeddedRep.[1]: json: cannot unmarshal number into Go value of type string")
+}
+
+func TestUnmarshal_UnknownFieldSinkCapturesFields(t *testing.T) {
+	type unknown struct {
+		path, key, raw string
+	}
+	captured := []unknown{}
+	u := &nicejsonpb.Unmarshaler{
+		UnknownFieldSink: func(path string, key string, raw json.RawMessage) {
+			captured = append(captured, unknown{path, key, string(raw)})
+		},
+	}
+	input := `{"someUnknown": true, "someEmbeddedRep": [{"identifier": "a"}, {"identifier": "b", "newField": {"x": 1}}]}`
+	stuff := &validatortest.ValidatorMessage3{}
+	require.NoError(t, u.Unmarshal(strings.NewReader(input), stuff))
+	require.Equal(t, "b", stuff.SomeEmbeddedRep[1].Identifier)
+	require.Equal(t, []unknown{
+		{"SomeEmbeddedRep.[1]", "newField", `{"x": 1}`},
+		{"", "someUnknown", "true"},
+	}, captured)
 }